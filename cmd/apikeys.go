@@ -0,0 +1,74 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/lucasfarolfi/hire.me/internal/entity"
+	"github.com/lucasfarolfi/hire.me/internal/service"
+)
+
+const apiKeysUsage = "usage: apikeys create -owner id [-tenant slug] [-name name] [-admin] | apikeys list | apikeys revoke id"
+
+// runAPIKeys implements the apikeys subcommand: create issues a key and prints it once, list shows the
+// issued keys without their secrets and revoke stops a key from authenticating requests.
+func runAPIKeys(args []string) {
+	if len(args) == 0 {
+		log.Fatal(apiKeysUsage)
+	}
+
+	storage := openStorage()
+	apiKeys := service.NewAPIKeyService(storage.apiKeys)
+	switch args[0] {
+	case "create":
+		flags := flag.NewFlagSet("apikeys create", flag.ExitOnError)
+		owner := flags.String("owner", "", "owner of the links created with the key")
+		tenantSlug := flags.String("tenant", "", "slug of the tenant of the key, the default tenant when empty")
+		name := flags.String("name", "", "description of the key")
+		admin := flags.Bool("admin", false, "manage the links of every owner of the tenant and the admin routes")
+		flags.Parse(args[1:])
+
+		tenantID := entity.DefaultTenantID
+		if tenant := findTenant(storage, *tenantSlug); tenant != nil {
+			tenantID = tenant.ID
+		}
+		apiKey, key, err := apiKeys.Issue(tenantID, *owner, *name, *admin)
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("created key %d for owner %s, store it now, it cannot be shown again:\n%s\n", apiKey.ID, apiKey.OwnerID, key)
+	case "list":
+		keys, err := apiKeys.List()
+		if err != nil {
+			log.Fatal(err)
+		}
+		for _, key := range keys {
+			state := "active"
+			if key.IsRevoked() {
+				state = "revoked at " + key.RevokedAt.Format(time.RFC3339)
+			}
+			role := "owner"
+			if key.Admin {
+				role = "admin"
+			}
+			fmt.Printf("%d %s... tenant %d %s %s %q: %s\n", key.ID, key.Prefix, key.TenantID, role, key.OwnerID, key.Name, state)
+		}
+	case "revoke":
+		if len(args) != 2 {
+			log.Fatal(apiKeysUsage)
+		}
+		id, err := strconv.Atoi(args[1])
+		if err != nil {
+			log.Fatal(apiKeysUsage)
+		}
+		if err := apiKeys.Revoke(id); err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("revoked key %d\n", id)
+	default:
+		log.Fatal(apiKeysUsage)
+	}
+}
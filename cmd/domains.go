@@ -0,0 +1,65 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+
+	"github.com/lucasfarolfi/hire.me/internal/entity"
+	"github.com/lucasfarolfi/hire.me/internal/service"
+)
+
+const domainsUsage = "usage: domains add -host host [-tenant slug] [-scheme http|https] [-canonical] | domains list | domains remove host"
+
+// runDomains implements the domains subcommand: add maps a custom short domain to a tenant, list shows
+// the registered domains and remove unregisters one. Running servers pick the changes up within
+// DOMAINS_REFRESH_INTERVAL.
+func runDomains(args []string) {
+	if len(args) == 0 {
+		log.Fatal(domainsUsage)
+	}
+
+	storage := openStorage()
+	domains := service.NewDomainService(storage.domains, service.DefaultDomainRefreshInterval)
+	switch args[0] {
+	case "add":
+		flags := flag.NewFlagSet("domains add", flag.ExitOnError)
+		host := flags.String("host", "", "host of the domain, e.g. go.acme.io")
+		tenantSlug := flags.String("tenant", "", "slug of the tenant served on the domain, the default tenant when empty")
+		scheme := flags.String("scheme", "https", "scheme of the short URLs built on the domain")
+		canonical := flags.Bool("canonical", false, "build the short URLs of the tenant on this domain")
+		flags.Parse(args[1:])
+
+		tenantID := entity.DefaultTenantID
+		if tenant := findTenant(storage, *tenantSlug); tenant != nil {
+			tenantID = tenant.ID
+		}
+		domain, err := domains.Add(*host, tenantID, *scheme, *canonical)
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("added domain %s for tenant %d\n", domain.Host, domain.TenantID)
+	case "list":
+		list, err := domains.List()
+		if err != nil {
+			log.Fatal(err)
+		}
+		for _, domain := range list {
+			canonical := ""
+			if domain.Canonical {
+				canonical = " canonical"
+			}
+			fmt.Printf("%s://%s tenant %d%s\n", domain.Scheme, domain.Host, domain.TenantID, canonical)
+		}
+	case "remove":
+		if len(args) != 2 {
+			log.Fatal(domainsUsage)
+		}
+		if err := domains.Remove(args[1]); err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("removed domain %s\n", args[1])
+	default:
+		log.Fatal(domainsUsage)
+	}
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	"github.com/lucasfarolfi/hire.me/internal/service"
+)
+
+const linksUsage = "usage: links export [-tenant slug] [-format csv|ndjson] [-output file] | links import [-tenant slug] [-format csv|ndjson] [-on-conflict skip|overwrite|fail] [file]"
+
+// runLinks implements the links subcommand: export writes every shortened URL of a tenant to a file or
+// stdout and import stores the shortened URLs read from a file or stdin in a tenant.
+func runLinks(args []string) {
+	if len(args) == 0 {
+		log.Fatal(linksUsage)
+	}
+
+	switch args[0] {
+	case "export":
+		flags := flag.NewFlagSet("links export", flag.ExitOnError)
+		tenant := flags.String("tenant", "", "slug of the tenant, the default tenant when empty")
+		format := flags.String("format", service.TransferFormatNDJSON, "csv or ndjson")
+		output := flags.String("output", "", "file to write, stdout when empty")
+		flags.Parse(args[1:])
+
+		var w io.Writer = os.Stdout
+		if *output != "" {
+			file, err := os.Create(*output)
+			if err != nil {
+				log.Fatal(err)
+			}
+			defer file.Close()
+			w = file
+		}
+		exported, err := linksService(*tenant).ExportLinks(w, *format)
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Fprintf(os.Stderr, "exported %d links\n", exported)
+	case "import":
+		flags := flag.NewFlagSet("links import", flag.ExitOnError)
+		tenant := flags.String("tenant", "", "slug of the tenant, the default tenant when empty")
+		format := flags.String("format", service.TransferFormatNDJSON, "csv or ndjson")
+		onConflict := flags.String("on-conflict", service.ConflictFail, "skip, overwrite or fail")
+		flags.Parse(args[1:])
+		if flags.NArg() > 1 {
+			log.Fatal(linksUsage)
+		}
+
+		var r io.Reader = os.Stdin
+		if flags.NArg() == 1 {
+			file, err := os.Open(flags.Arg(0))
+			if err != nil {
+				log.Fatal(err)
+			}
+			defer file.Close()
+			r = file
+		}
+		summary, err := linksService(*tenant).ImportLinks(r, *format, *onConflict)
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("created %d, overwritten %d, skipped %d\n", summary.Created, summary.Overwritten, summary.Skipped)
+	default:
+		log.Fatal(linksUsage)
+	}
+}
+
+// linksService builds a service over the configured storage with the URL and alias rules of the server,
+// so imported links are validated the same way as created ones, scoped to the tenant of the slug.
+func linksService(tenantSlug string) *service.URLShortenerService {
+	storage := openStorage()
+	tenant := findTenant(storage, tenantSlug)
+	opts := []service.ServiceOption{
+		service.WithURLPolicy(urlPolicy()),
+		service.WithAliasPolicy(aliasPolicy()),
+		service.WithBulkBatchSize(intFromEnv("BULK_BATCH_SIZE", service.DefaultBulkBatchSize)),
+	}
+	if storage.transactor != nil {
+		opts = append(opts, service.WithTransactor(storage.transactor))
+	}
+	if screener, _ := loadURLBlocklist(); screener != nil {
+		opts = append(opts, service.WithURLScreener(screener))
+	}
+	return service.NewURLShortenerService(storage.shortenedURLs, opts...).ForTenant(tenant)
+}
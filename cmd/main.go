@@ -1,27 +1,327 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"log"
 	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
 
+	"github.com/lucasfarolfi/hire.me/infrastructure/blocklist"
+	"github.com/lucasfarolfi/hire.me/infrastructure/cache"
 	"github.com/lucasfarolfi/hire.me/infrastructure/db"
+	"github.com/lucasfarolfi/hire.me/infrastructure/ratelimit"
 	"github.com/lucasfarolfi/hire.me/infrastructure/repository"
+	"github.com/lucasfarolfi/hire.me/infrastructure/repository/memory"
 	"github.com/lucasfarolfi/hire.me/infrastructure/webserver/handlers"
+	"github.com/lucasfarolfi/hire.me/internal/entity"
 	"github.com/lucasfarolfi/hire.me/internal/service"
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		runMigrate(os.Args[2:])
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "links" {
+		runLinks(os.Args[2:])
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "apikeys" {
+		runAPIKeys(os.Args[2:])
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "tenants" {
+		runTenants(os.Args[2:])
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "domains" {
+		runDomains(os.Args[2:])
+		return
+	}
+
 	log.Println("Application starting...")
 
-	db := db.InitializeDatabase()
-	repository := repository.NewShortenedURLRepository(db)
-	service := service.NewURLShortenerService(repository)
-	handler := handlers.NewURLShortenerHandler(service)
+	storage := openStorage()
+	shortenedURLRepository := storage.shortenedURLs
+	serviceOpts := []service.ServiceOption{
+		service.WithClickEventRepository(storage.clickEvents),
+	}
+	aliasIDs := service.NewBlockIDSource(storage.idBlocks, service.AliasSequence, int64(intFromEnv("ALIAS_BLOCK_SIZE", 1000)))
+	aliasGenerator, err := service.NewAliasGenerator(os.Getenv("ALIAS_STRATEGY"), intFromEnv("ALIAS_LENGTH", 0), aliasIDs)
+	if err != nil {
+		log.Fatal("ALIAS_STRATEGY must be one of timestamp, random, sequential, block, hash or pronounceable")
+	}
+	serviceOpts = append(serviceOpts,
+		service.WithAliasGenerator(aliasGenerator),
+		service.WithAliasGenerationAttempts(intFromEnv("ALIAS_MAX_ATTEMPTS", service.DefaultAliasGenerationAttempts)))
+	serviceOpts = append(serviceOpts, service.WithURLPolicy(urlPolicy()), service.WithAliasPolicy(aliasPolicy()))
+	serviceOpts = append(serviceOpts, service.WithBulkBatchSize(intFromEnv("BULK_BATCH_SIZE", service.DefaultBulkBatchSize)))
+	if storage.transactor != nil {
+		serviceOpts = append(serviceOpts, service.WithTransactor(storage.transactor))
+	}
+	if os.Getenv("DEDUPLICATE_URLS") == "true" {
+		serviceOpts = append(serviceOpts, service.WithDeduplication())
+	}
+	if os.Getenv("ANONYMIZE_CLIENT_IP") == "true" {
+		serviceOpts = append(serviceOpts, service.WithClientIPAnonymization())
+	}
+
+	urlScreener, blocklistWatcher := loadURLBlocklist()
+	if urlScreener != nil {
+		blocklistWatcher.Start()
+		serviceOpts = append(serviceOpts, service.WithURLScreener(urlScreener))
+	}
+
+	var accessCounter *service.AccessCounter
+	if os.Getenv("ASYNC_ACCESS_COUNTING") == "true" {
+		accessCounter = service.NewAccessCounter(shortenedURLRepository,
+			durationFromEnv("ACCESS_COUNT_FLUSH_INTERVAL", time.Second),
+			intFromEnv("ACCESS_COUNT_BATCH_SIZE", 1000))
+		accessCounter.Start()
+		serviceOpts = append(serviceOpts, service.WithAccessCounter(accessCounter))
+	}
+
+	var serviceRepository service.ShortenedURLRepository = shortenedURLRepository
+	if os.Getenv("CACHE_DISABLED") != "true" {
+		serviceRepository = cache.NewShortenedURLRepository(shortenedURLRepository, cache.NewLRU(intFromEnv("CACHE_SIZE", 10000)),
+			durationFromEnv("CACHE_TTL", time.Minute),
+			durationFromEnv("CACHE_NEGATIVE_TTL", 10*time.Second))
+	}
+
+	urlShortenerService := service.NewURLShortenerService(serviceRepository, serviceOpts...)
+	handlerOpts := append(handlerOptions(),
+		handlers.WithTenants(service.NewTenantService(storage.tenants)),
+		handlers.WithDomains(service.NewDomainService(storage.domains,
+			durationFromEnv("DOMAINS_REFRESH_INTERVAL", service.DefaultDomainRefreshInterval))))
+	handler := handlers.NewURLShortenerHandler(urlShortenerService, handlerOpts...)
+
+	sweeper := service.NewExpirationSweeper(shortenedURLRepository,
+		durationFromEnv("EXPIRATION_SWEEP_INTERVAL", time.Hour),
+		durationFromEnv("EXPIRATION_RETENTION", 24*time.Hour))
+	sweeper.Start()
+
+	// Redirects stay public; every other route needs an API key unless AUTH_DISABLED=true.
+	var routerOpts []handlers.RouterOption
+	if os.Getenv("AUTH_DISABLED") == "true" {
+		log.Println("Authentication is disabled, anyone can create and manage links")
+	} else {
+		apiKeys := service.NewAPIKeyService(storage.apiKeys)
+		if os.Getenv("STORAGE") == "memory" {
+			// In-memory keys cannot be issued from the apikeys command, which runs in another process.
+			_, key, err := apiKeys.Issue(entity.DefaultTenantID, "admin", "in-memory admin", true)
+			if err != nil {
+				log.Fatal("Failed to issue the in-memory admin API key:", err)
+			}
+			log.Println("Admin API key for this run:", key)
+		}
+		routerOpts = append(routerOpts, handlers.WithAuthenticator(handlers.NewAuthenticator(apiKeys)))
+	}
+
+	if os.Getenv("RATE_LIMIT_DISABLED") == "true" {
+		log.Println("Rate limiting is disabled")
+	} else {
+		limiter := handlers.NewRateLimiter(ratelimit.NewMemoryStore(intFromEnv("RATE_LIMIT_MAX_CLIENTS", 100000)))
+		routerOpts = append(routerOpts, handlers.WithRateLimits(limiter, handlers.RateLimits{
+			Create:   limitFromEnv("RATE_LIMIT_CREATE", ratelimit.Limit{Requests: 30, Period: time.Minute}),
+			Redirect: limitFromEnv("RATE_LIMIT_REDIRECT", ratelimit.Limit{Requests: 300, Period: time.Minute}),
+			API:      limitFromEnv("RATE_LIMIT_API", ratelimit.Limit{Requests: 600, Period: time.Minute}),
+		}))
+	}
+
+	server := &http.Server{Addr: ":8080", Handler: handlers.NewRouter(handler, routerOpts...)}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	go func() {
+		log.Println("Server is running at port 8080")
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatal("Server failed:", err)
+		}
+	}()
+
+	<-ctx.Done()
+	log.Println("Shutting down server...")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		log.Println("Failed to gracefully shut down server:", err)
+	}
+
+	sweeper.Stop()
+	if blocklistWatcher != nil {
+		blocklistWatcher.Stop()
+	}
+	if accessCounter != nil {
+		if err := accessCounter.Close(); err != nil {
+			log.Println("Failed to flush access counts on shutdown:", err)
+		}
+	}
+	log.Println("Server stopped")
+}
+
+type storage struct {
+	shortenedURLs service.ShortenedURLRepository
+	clickEvents   service.ClickEventRepository
+	idBlocks      service.IDBlockRepository
+	apiKeys       service.APIKeyRepository
+	tenants       service.TenantRepository
+	domains       service.DomainRepository
+	// transactor runs bulk writes in database transactions; nil for the memory storage.
+	transactor service.Transactor
+}
 
-	http.HandleFunc("POST /", handler.Create)
-	http.HandleFunc("GET /u/{alias}", handler.RetrieveByAlias)
-	http.HandleFunc("GET /most_acessed", handler.GetMostAcessedUrls)
+// openStorage builds the repositories selected by STORAGE: "database" (default) or "memory",
+// which keeps everything in process and is lost on restart.
+func openStorage() storage {
+	switch kind := os.Getenv("STORAGE"); kind {
+	case "", "database":
+		db := db.InitializeDatabase()
+		ensureSchemaUpToDate(db)
+		shortenedURLs := repository.NewShortenedURLRepository(db)
+		return storage{
+			shortenedURLs: shortenedURLs,
+			clickEvents:   repository.NewClickEventRepository(db),
+			idBlocks:      repository.NewIDBlockRepository(db),
+			apiKeys:       repository.NewAPIKeyRepository(db),
+			tenants:       repository.NewTenantRepository(db),
+			domains:       repository.NewDomainRepository(db),
+			transactor: func(fn func(service.ShortenedURLRepository) error) error {
+				return shortenedURLs.WithTransaction(func(tx *repository.ShortenedURLRepository) error {
+					return fn(tx)
+				})
+			},
+		}
+	case "memory":
+		log.Println("Using in-memory storage, data will be lost on shutdown")
+		clickEvents := memory.NewClickEventRepository()
+		return storage{
+			shortenedURLs: memory.NewShortenedURLRepository(clickEvents),
+			clickEvents:   clickEvents,
+			idBlocks:      memory.NewIDBlockRepository(),
+			apiKeys:       memory.NewAPIKeyRepository(),
+			tenants:       memory.NewTenantRepository(),
+			domains:       memory.NewDomainRepository(),
+		}
+	default:
+		log.Fatalf("STORAGE must be database or memory, got %q", kind)
+		return storage{}
+	}
+}
+
+// urlPolicy reads the accepted destination URLs from ALLOWED_URL_SCHEMES and MAX_URL_LENGTH.
+func urlPolicy() service.URLPolicy {
+	policy := service.DefaultURLPolicy()
+	if schemes := os.Getenv("ALLOWED_URL_SCHEMES"); schemes != "" {
+		policy.AllowedSchemes = strings.Split(schemes, ",")
+	}
+	policy.MaxLength = intFromEnv("MAX_URL_LENGTH", policy.MaxLength)
+	return policy
+}
+
+// aliasPolicy reads the custom alias rules from ALIAS_MIN_LENGTH, ALIAS_MAX_LENGTH, ALIAS_CHARSET and
+// ALIAS_CASE, adding the entries of ALIAS_BLOCKLIST_FILE to the reserved aliases.
+func aliasPolicy() service.AliasPolicy {
+	policy := service.DefaultAliasPolicy()
+	policy.MinLength = intFromEnv("ALIAS_MIN_LENGTH", policy.MinLength)
+	policy.MaxLength = intFromEnv("ALIAS_MAX_LENGTH", policy.MaxLength)
+	if charset := os.Getenv("ALIAS_CHARSET"); charset != "" {
+		policy.AllowedCharacters = charset
+	}
+	if casePolicy := os.Getenv("ALIAS_CASE"); casePolicy != "" {
+		policy.CasePolicy = casePolicy
+	}
+	if err := policy.Validate(); err != nil {
+		log.Fatal("ALIAS_MIN_LENGTH must not exceed ALIAS_MAX_LENGTH and ALIAS_CASE must be preserve or lower")
+	}
+
+	if path := os.Getenv("ALIAS_BLOCKLIST_FILE"); path != "" {
+		file, err := os.Open(path)
+		if err != nil {
+			log.Fatal("Failed to open ALIAS_BLOCKLIST_FILE:", err)
+		}
+		defer file.Close()
+		entries, err := service.ParseAliasBlocklist(file)
+		if err != nil {
+			log.Fatal("Failed to read ALIAS_BLOCKLIST_FILE:", err)
+		}
+		policy.Blocklist = append(policy.Blocklist, entries...)
+		log.Printf("Loaded %d blocked aliases from %s", len(entries), path)
+	}
+	return policy
+}
+
+// loadURLBlocklist loads the malicious hosts and URLs of the comma-separated URL_BLOCKLIST_FILES into a
+// URL screener, returning it with the watcher that reloads the files every URL_BLOCKLIST_RELOAD_INTERVAL
+// once started. Both are nil when no file is configured.
+func loadURLBlocklist() (*service.URLScreener, *blocklist.Watcher) {
+	files := os.Getenv("URL_BLOCKLIST_FILES")
+	if files == "" {
+		return nil, nil
+	}
+	screener := service.NewURLScreener(nil)
+	watcher := blocklist.NewWatcher(screener, strings.Split(files, ","),
+		durationFromEnv("URL_BLOCKLIST_RELOAD_INTERVAL", blocklist.DefaultReloadInterval))
+	entries, err := watcher.Load()
+	if err != nil {
+		log.Fatal("Failed to load URL_BLOCKLIST_FILES:", err)
+	}
+	log.Printf("Loaded %d blocked hosts and URLs from %s", entries, files)
+	return screener, watcher
+}
+
+func handlerOptions() []handlers.HandlerOption {
+	var opts []handlers.HandlerOption
+	if redirectType := os.Getenv("REDIRECT_TYPE"); redirectType != "" {
+		code, err := strconv.Atoi(redirectType)
+		if err != nil || !entity.IsValidRedirectType(code) {
+			log.Fatal("REDIRECT_TYPE must be one of 301, 302, 307 or 308")
+		}
+		opts = append(opts, handlers.WithDefaultRedirectType(code))
+	}
+	opts = append(opts, handlers.WithMaxBodyBytes(int64(intFromEnv("MAX_BODY_BYTES", int(handlers.DefaultMaxBodyBytes)))))
+	opts = append(opts, handlers.WithMaxBulkBodyBytes(int64(intFromEnv("MAX_BULK_BODY_BYTES", int(handlers.DefaultMaxBulkBodyBytes)))))
+	return opts
+}
+
+func durationFromEnv(name string, fallback time.Duration) time.Duration {
+	value := os.Getenv(name)
+	if value == "" {
+		return fallback
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration <= 0 {
+		log.Fatalf("%s must be a positive duration, e.g. 30m or 1h", name)
+	}
+	return duration
+}
+
+// limitFromEnv reads a rate limit written as requests/period, e.g. 30/1m.
+func limitFromEnv(name string, fallback ratelimit.Limit) ratelimit.Limit {
+	value := os.Getenv(name)
+	if value == "" {
+		return fallback
+	}
+	limit, err := ratelimit.ParseLimit(value)
+	if err != nil {
+		log.Fatalf("%s must be requests/period, e.g. 30/1m", name)
+	}
+	return limit
+}
 
-	log.Println("Server is running at port 8080")
-	http.ListenAndServe(":8080", nil)
+func intFromEnv(name string, fallback int) int {
+	value := os.Getenv(name)
+	if value == "" {
+		return fallback
+	}
+	number, err := strconv.Atoi(value)
+	if err != nil || number <= 0 {
+		log.Fatalf("%s must be a positive integer", name)
+	}
+	return number
 }
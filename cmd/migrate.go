@@ -0,0 +1,85 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/lucasfarolfi/hire.me/infrastructure/db"
+	"gorm.io/gorm"
+)
+
+const migrateUsage = "usage: migrate up|down|status"
+
+// runMigrate implements the migrate subcommand: up applies every pending migration, down rolls back
+// the last applied one and status lists all of them.
+func runMigrate(args []string) {
+	if len(args) != 1 {
+		log.Fatal(migrateUsage)
+	}
+
+	migrator := db.NewMigrator(db.InitializeDatabase(), db.Migrations)
+	switch args[0] {
+	case "up":
+		applied, err := migrator.Up()
+		for _, migration := range applied {
+			fmt.Printf("applied %d %s\n", migration.Version, migration.Name)
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+		if len(applied) == 0 {
+			fmt.Println("schema is up to date")
+		}
+	case "down":
+		migration, err := migrator.Down()
+		if errors.Is(err, db.ErrNoMigrationToRollback) {
+			fmt.Println("no migration to roll back")
+			return
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("rolled back %d %s\n", migration.Version, migration.Name)
+	case "status":
+		statuses, err := migrator.Status()
+		if err != nil {
+			log.Fatal(err)
+		}
+		for _, status := range statuses {
+			state := "pending"
+			if status.Applied() {
+				state = "applied at " + status.AppliedAt.Format(time.RFC3339)
+			}
+			fmt.Printf("%d %s: %s\n", status.Version, status.Name, state)
+		}
+	default:
+		log.Fatal(migrateUsage)
+	}
+}
+
+// ensureSchemaUpToDate refuses to start the server on a database with pending migrations,
+// unless MIGRATE_ON_START=true asks to apply them first.
+func ensureSchemaUpToDate(conn *gorm.DB) {
+	migrator := db.NewMigrator(conn, db.Migrations)
+	if os.Getenv("MIGRATE_ON_START") == "true" {
+		applied, err := migrator.Up()
+		if err != nil {
+			log.Fatal("Failed to migrate database:", err)
+		}
+		for _, migration := range applied {
+			log.Printf("Applied migration %d %s", migration.Version, migration.Name)
+		}
+	}
+
+	pending, err := migrator.Pending()
+	if err != nil {
+		log.Fatal("Failed to check database schema:", err)
+	}
+	if len(pending) > 0 {
+		log.Fatalf("Database schema is behind by %d migration(s), starting with %d %s. Run the migrate up command first.",
+			len(pending), pending[0].Version, pending[0].Name)
+	}
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"strconv"
+
+	"github.com/lucasfarolfi/hire.me/internal/entity"
+	"github.com/lucasfarolfi/hire.me/internal/service"
+)
+
+const tenantsUsage = "usage: tenants create -slug slug [-name name] [-max-links n] | tenants list | tenants quota slug max-links|unlimited"
+
+// runTenants implements the tenants subcommand: create adds a tenant with its own alias namespace, list
+// shows the tenants and quota changes how many links a tenant may have.
+func runTenants(args []string) {
+	if len(args) == 0 {
+		log.Fatal(tenantsUsage)
+	}
+
+	tenants := service.NewTenantService(openStorage().tenants)
+	switch args[0] {
+	case "create":
+		flags := flag.NewFlagSet("tenants create", flag.ExitOnError)
+		slug := flags.String("slug", "", "lowercase name of the tenant in URLs")
+		name := flags.String("name", "", "description of the tenant")
+		maxLinks := flags.Int("max-links", -1, "how many links the tenant may have, unlimited when negative")
+		flags.Parse(args[1:])
+
+		tenant, err := tenants.Create(*slug, *name, quota(*maxLinks))
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("created tenant %d %s\n", tenant.ID, tenant.Slug)
+	case "list":
+		list, err := tenants.List()
+		if err != nil {
+			log.Fatal(err)
+		}
+		for _, tenant := range list {
+			maxLinks := "unlimited"
+			if tenant.MaxLinks != nil {
+				maxLinks = strconv.Itoa(*tenant.MaxLinks)
+			}
+			fmt.Printf("%d %s %q: %s links\n", tenant.ID, tenant.Slug, tenant.Name, maxLinks)
+		}
+	case "quota":
+		if len(args) != 3 {
+			log.Fatal(tenantsUsage)
+		}
+		tenant, err := tenants.FindBySlug(args[1])
+		if err != nil {
+			log.Fatal(err)
+		}
+		maxLinks := -1
+		if args[2] != "unlimited" {
+			if maxLinks, err = strconv.Atoi(args[2]); err != nil || maxLinks < 0 {
+				log.Fatal(tenantsUsage)
+			}
+		}
+		if err := tenants.SetQuota(tenant.ID, quota(maxLinks)); err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("updated quota of tenant %s\n", tenant.Slug)
+	default:
+		log.Fatal(tenantsUsage)
+	}
+}
+
+func quota(maxLinks int) *int {
+	if maxLinks < 0 {
+		return nil
+	}
+	return &maxLinks
+}
+
+// findTenant returns the tenant of the slug, or nil for the default tenant when slug is empty.
+func findTenant(storage storage, slug string) *entity.Tenant {
+	if slug == "" {
+		return nil
+	}
+	tenant, err := service.NewTenantService(storage.tenants).FindBySlug(slug)
+	if err != nil {
+		log.Fatalf("Failed to find tenant %q: %v", slug, err)
+	}
+	return tenant
+}
@@ -0,0 +1,146 @@
+package blocklist
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/lucasfarolfi/hire.me/internal/service"
+)
+
+const DefaultReloadInterval = time.Minute
+
+// Watcher loads blocklist files into a URLScreener and reloads them when one of them changes, so entries
+// can be added without restarting the app.
+type Watcher struct {
+	screener *service.URLScreener
+	paths    []string
+	interval time.Duration
+
+	mu       sync.Mutex
+	versions map[string]fileVersion
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+// fileVersion tells whether a file changed since it was loaded.
+type fileVersion struct {
+	modTime time.Time
+	size    int64
+}
+
+func NewWatcher(screener *service.URLScreener, paths []string, interval time.Duration) *Watcher {
+	return &Watcher{screener: screener, paths: paths, interval: interval}
+}
+
+// Load reads every file into a new blocklist and hands it to the screener, returning how many entries it
+// holds. On failure the screener keeps its previous blocklist.
+func (w *Watcher) Load() (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.load()
+}
+
+// Reload works like Load but only when a file changed since the last load, reporting whether it did.
+func (w *Watcher) Reload() (bool, int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	changed, err := w.changed()
+	if err != nil || !changed {
+		return false, 0, err
+	}
+	entries, err := w.load()
+	return err == nil, entries, err
+}
+
+func (w *Watcher) load() (int, error) {
+	blocklist := service.NewURLBlocklist()
+	versions := make(map[string]fileVersion, len(w.paths))
+	for _, path := range w.paths {
+		version, err := loadFile(blocklist, path)
+		if err != nil {
+			return 0, err
+		}
+		versions[path] = version
+	}
+	w.screener.Replace(blocklist)
+	w.versions = versions
+	return blocklist.Len(), nil
+}
+
+func loadFile(blocklist *service.URLBlocklist, path string) (fileVersion, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return fileVersion{}, err
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return fileVersion{}, err
+	}
+	if err := blocklist.Load(file); err != nil {
+		return fileVersion{}, fmt.Errorf("%s: %w", path, err)
+	}
+	return fileVersion{modTime: info.ModTime(), size: info.Size()}, nil
+}
+
+func (w *Watcher) changed() (bool, error) {
+	for _, path := range w.paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return false, err
+		}
+		if w.versions[path] != (fileVersion{modTime: info.ModTime(), size: info.Size()}) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// Start runs Reload on every interval in a background goroutine until Stop is called.
+func (w *Watcher) Start() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.stop != nil {
+		return
+	}
+	w.stop = make(chan struct{})
+	w.done = make(chan struct{})
+	go w.run(w.stop, w.done)
+}
+
+func (w *Watcher) run(stop, done chan struct{}) {
+	defer close(done)
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			reloaded, entries, err := w.Reload()
+			if err != nil {
+				log.Println("Failed to reload the URL blocklist, keeping the previous one:", err)
+				continue
+			}
+			if reloaded {
+				log.Println("Reloaded the URL blocklist with entries:", entries)
+			}
+		case <-stop:
+			return
+		}
+	}
+}
+
+// Stop halts the background reloading and waits for an in-flight reload to finish.
+func (w *Watcher) Stop() {
+	w.mu.Lock()
+	stop, done := w.stop, w.done
+	w.stop, w.done = nil, nil
+	w.mu.Unlock()
+	if stop == nil {
+		return
+	}
+	close(stop)
+	<-done
+}
@@ -0,0 +1,88 @@
+package blocklist
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/lucasfarolfi/hire.me/internal/service"
+	"github.com/stretchr/testify/assert"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+}
+
+func blocked(screener *service.URLScreener, url string) bool {
+	_, ok := screener.Screen(url)
+	return ok
+}
+
+func TestWatcherUnit(t *testing.T) {
+	t.Run("Given blocklist files, when they are loaded, then the screener should block the entries of every file", func(t *testing.T) {
+		dir := t.TempDir()
+		hosts, plain := filepath.Join(dir, "hosts"), filepath.Join(dir, "urls.txt")
+		writeFile(t, hosts, "0.0.0.0 evil.example.com\n")
+		writeFile(t, plain, "www.google.com/phish\n")
+		screener := service.NewURLScreener(nil)
+
+		entries, err := NewWatcher(screener, []string{hosts, plain}, time.Minute).Load()
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, entries)
+		assert.True(t, blocked(screener, "http://evil.example.com"))
+		assert.True(t, blocked(screener, "https://www.google.com/phish"))
+	})
+
+	t.Run("Given a loaded file, when it changes, then Reload should pick up the new entries only once", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "hosts")
+		writeFile(t, path, "evil.example.com\n")
+		screener := service.NewURLScreener(nil)
+		watcher := NewWatcher(screener, []string{path}, time.Minute)
+		_, err := watcher.Load()
+		assert.NoError(t, err)
+
+		reloaded, _, err := watcher.Reload()
+		assert.NoError(t, err)
+		assert.False(t, reloaded, "An unchanged file should not be reloaded")
+
+		writeFile(t, path, "scam.example.net\nevil.example.com\n")
+		reloaded, entries, err := watcher.Reload()
+
+		assert.NoError(t, err)
+		assert.True(t, reloaded)
+		assert.Equal(t, 2, entries)
+		assert.True(t, blocked(screener, "http://scam.example.net"))
+	})
+
+	t.Run("Given a loaded file, when it is replaced by a malformed one, then the screener should keep the previous entries", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "hosts")
+		writeFile(t, path, "evil.example.com\n")
+		screener := service.NewURLScreener(nil)
+		watcher := NewWatcher(screener, []string{path}, time.Minute)
+		_, err := watcher.Load()
+		assert.NoError(t, err)
+
+		writeFile(t, path, "evil.example.com\nnot a valid line\n")
+		_, _, err = watcher.Reload()
+
+		assert.ErrorIs(t, err, service.ErrInvalidURLBlocklist)
+		assert.True(t, blocked(screener, "http://evil.example.com"))
+	})
+
+	t.Run("Given a started watcher, when a file changes, then it should be reloaded in the background", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "hosts")
+		writeFile(t, path, "evil.example.com\n")
+		screener := service.NewURLScreener(nil)
+		watcher := NewWatcher(screener, []string{path}, 10*time.Millisecond)
+		_, err := watcher.Load()
+		assert.NoError(t, err)
+		watcher.Start()
+		defer watcher.Stop()
+
+		writeFile(t, path, "evil.example.com\nscam.example.net\n")
+
+		assert.Eventually(t, func() bool { return blocked(screener, "http://scam.example.net") }, time.Second, 10*time.Millisecond)
+	})
+}
@@ -0,0 +1,73 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLRUUnit_GetSet(t *testing.T) {
+	t.Run("Given a stored value, when Get is called before it expires, then it should return the value", func(t *testing.T) {
+		lru := NewLRU(10)
+
+		lru.Set("key", []byte("value"), time.Minute)
+		value, ok := lru.Get("key")
+
+		assert.True(t, ok)
+		assert.Equal(t, []byte("value"), value)
+	})
+
+	t.Run("Given a stored value, when Get is called after it expires, then it should be a miss", func(t *testing.T) {
+		now := time.Now()
+		lru := NewLRU(10)
+		lru.now = func() time.Time { return now }
+
+		lru.Set("key", []byte("value"), time.Minute)
+		now = now.Add(time.Minute)
+		_, ok := lru.Get("key")
+
+		assert.False(t, ok, "Expired values should not be returned")
+		assert.Equal(t, 0, lru.Len(), "Expired values should be evicted on read")
+	})
+
+	t.Run("Given a deleted value, when Get is called, then it should be a miss", func(t *testing.T) {
+		lru := NewLRU(10)
+
+		lru.Set("key", []byte("value"), time.Minute)
+		lru.Delete("key")
+		_, ok := lru.Get("key")
+
+		assert.False(t, ok)
+	})
+}
+
+func TestLRUUnit_Eviction(t *testing.T) {
+	t.Run("Given a full cache, when a new value is stored, then it should evict the least recently used value", func(t *testing.T) {
+		lru := NewLRU(2)
+
+		lru.Set("first", []byte("1"), time.Minute)
+		lru.Set("second", []byte("2"), time.Minute)
+		lru.Get("first")
+		lru.Set("third", []byte("3"), time.Minute)
+
+		_, firstOk := lru.Get("first")
+		_, secondOk := lru.Get("second")
+		_, thirdOk := lru.Get("third")
+		assert.True(t, firstOk, "The recently read value should be kept")
+		assert.False(t, secondOk, "The least recently used value should be evicted")
+		assert.True(t, thirdOk)
+		assert.Equal(t, 2, lru.Len())
+	})
+
+	t.Run("Given an existing key, when it is stored again, then it should replace the value without growing the cache", func(t *testing.T) {
+		lru := NewLRU(2)
+
+		lru.Set("key", []byte("old"), time.Minute)
+		lru.Set("key", []byte("new"), time.Minute)
+		value, _ := lru.Get("key")
+
+		assert.Equal(t, []byte("new"), value)
+		assert.Equal(t, 1, lru.Len())
+	})
+}
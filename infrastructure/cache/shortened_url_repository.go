@@ -0,0 +1,129 @@
+package cache
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/lucasfarolfi/hire.me/internal/entity"
+	"github.com/lucasfarolfi/hire.me/internal/service"
+	"gorm.io/gorm"
+)
+
+// ShortenedURLRepository decorates a service.ShortenedURLRepository with a read-through cache for
+// FindByAlias. Unknown aliases are cached as well (negative caching) for a shorter time to live, and
+// entries are invalidated whenever the shortened URL is written through this repository.
+//
+// Cached shortened URLs may carry stale access times, which are only refreshed when the entry expires.
+type ShortenedURLRepository struct {
+	repository  service.ShortenedURLRepository
+	cache       Cache
+	ttl         time.Duration
+	negativeTTL time.Duration
+}
+
+// notFoundMarker is stored for aliases known not to exist; encoded shortened URLs are never empty.
+var notFoundMarker = []byte{}
+
+func NewShortenedURLRepository(repository service.ShortenedURLRepository, cache Cache, ttl, negativeTTL time.Duration) *ShortenedURLRepository {
+	return &ShortenedURLRepository{repository: repository, cache: cache, ttl: ttl, negativeTTL: negativeTTL}
+}
+
+func (cr *ShortenedURLRepository) Create(shortUrl *entity.ShortenedURL) error {
+	err := cr.repository.Create(shortUrl)
+	cr.Invalidate(shortUrl.TenantID, shortUrl.Alias)
+	return err
+}
+
+func (cr *ShortenedURLRepository) FindByAlias(tenantID int, alias string) (*entity.ShortenedURL, error) {
+	key := aliasKey(tenantID, alias)
+	if cached, ok := cr.cache.Get(key); ok {
+		if len(cached) == 0 {
+			return nil, gorm.ErrRecordNotFound
+		}
+		var shortUrl entity.ShortenedURL
+		if err := json.Unmarshal(cached, &shortUrl); err == nil {
+			return &shortUrl, nil
+		}
+		cr.cache.Delete(key)
+	}
+
+	shortUrl, err := cr.repository.FindByAlias(tenantID, alias)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			cr.cache.Set(key, notFoundMarker, cr.negativeTTL)
+		}
+		return nil, err
+	}
+	if encoded, err := json.Marshal(shortUrl); err == nil {
+		cr.cache.Set(key, encoded, cr.ttl)
+	}
+	return shortUrl, nil
+}
+
+func (cr *ShortenedURLRepository) FindReusableByURLHash(tenantID int, urlHash string, redirectType int, ownerID string) (*entity.ShortenedURL, error) {
+	return cr.repository.FindReusableByURLHash(tenantID, urlHash, redirectType, ownerID)
+}
+
+// ExistsByAlias always checks the underlying repository, since it guards the creation of custom aliases.
+func (cr *ShortenedURLRepository) ExistsByAlias(tenantID int, alias string) bool {
+	return cr.repository.ExistsByAlias(tenantID, alias)
+}
+
+func (cr *ShortenedURLRepository) Update(shortUrl *entity.ShortenedURL) error {
+	err := cr.repository.Update(shortUrl)
+	cr.Invalidate(shortUrl.TenantID, shortUrl.Alias)
+	return err
+}
+
+func (cr *ShortenedURLRepository) Replace(shortUrl *entity.ShortenedURL) error {
+	err := cr.repository.Replace(shortUrl)
+	cr.Invalidate(shortUrl.TenantID, shortUrl.Alias)
+	return err
+}
+
+func (cr *ShortenedURLRepository) SoftDelete(shortUrl *entity.ShortenedURL) error {
+	err := cr.repository.SoftDelete(shortUrl)
+	cr.Invalidate(shortUrl.TenantID, shortUrl.Alias)
+	return err
+}
+
+func (cr *ShortenedURLRepository) UpdateFlag(shortUrl *entity.ShortenedURL) error {
+	err := cr.repository.UpdateFlag(shortUrl)
+	cr.Invalidate(shortUrl.TenantID, shortUrl.Alias)
+	return err
+}
+
+func (cr *ShortenedURLRepository) IncrementAccessTimesByID(id int) (bool, error) {
+	return cr.repository.IncrementAccessTimesByID(id)
+}
+
+func (cr *ShortenedURLRepository) AddAccessTimes(increments map[int]int32) error {
+	return cr.repository.AddAccessTimes(increments)
+}
+
+func (cr *ShortenedURLRepository) CountLinks(tenantID int) (int64, error) {
+	return cr.repository.CountLinks(tenantID)
+}
+
+func (cr *ShortenedURLRepository) FindLinks(query entity.LinkQuery) ([]entity.ShortenedURL, error) {
+	return cr.repository.FindLinks(query)
+}
+
+func (cr *ShortenedURLRepository) FindMostAcessedUrls(tenantID, limit, offset int, since, until *time.Time) ([]entity.ShortenedURL, error) {
+	return cr.repository.FindMostAcessedUrls(tenantID, limit, offset, since, until)
+}
+
+func (cr *ShortenedURLRepository) DeleteExpiredBefore(before time.Time) (int64, error) {
+	return cr.repository.DeleteExpiredBefore(before)
+}
+
+// Invalidate drops the cached entry of the alias of the tenant, if any.
+func (cr *ShortenedURLRepository) Invalidate(tenantID int, alias string) {
+	cr.cache.Delete(aliasKey(tenantID, alias))
+}
+
+func aliasKey(tenantID int, alias string) string {
+	return "shortened_url:alias:" + strconv.Itoa(tenantID) + ":" + alias
+}
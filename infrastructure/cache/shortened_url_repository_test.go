@@ -0,0 +1,124 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lucasfarolfi/hire.me/internal/entity"
+	"github.com/lucasfarolfi/hire.me/internal/service"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestCachedShortenedURLRepositoryUnit_FindByAlias(t *testing.T) {
+	t.Run("Given a stored alias, when FindByAlias is called twice, then it should query the underlying repository only once", func(t *testing.T) {
+		inner := &service.MockShortenedURLRepository{}
+		inner.On("FindByAlias", 0, "abc123").Return(&entity.ShortenedURL{ID: 1, Alias: "abc123", Url: "http://www.bemobi.com.br"}, nil).Once()
+		repository := NewShortenedURLRepository(inner, NewLRU(10), time.Minute, time.Second)
+
+		first, err := repository.FindByAlias(0, "abc123")
+		assert.NoError(t, err)
+		second, err := repository.FindByAlias(0, "abc123")
+		assert.NoError(t, err)
+
+		assert.Equal(t, first, second, "The cached shortened URL should match the stored one")
+		assert.NotSame(t, first, second, "Each call should return its own copy")
+		inner.AssertNumberOfCalls(t, "FindByAlias", 1)
+	})
+
+	t.Run("Given an unknown alias, when FindByAlias is called twice, then it should cache the miss", func(t *testing.T) {
+		inner := &service.MockShortenedURLRepository{}
+		inner.On("FindByAlias", 0, "unknown").Return(nil, gorm.ErrRecordNotFound).Once()
+		repository := NewShortenedURLRepository(inner, NewLRU(10), time.Minute, time.Second)
+
+		_, err := repository.FindByAlias(0, "unknown")
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+		_, err = repository.FindByAlias(0, "unknown")
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+		inner.AssertNumberOfCalls(t, "FindByAlias", 1)
+	})
+
+	t.Run("Given an unexpected repository error, when FindByAlias is called, then it should not cache the error", func(t *testing.T) {
+		inner := &service.MockShortenedURLRepository{}
+		inner.On("FindByAlias", 0, "abc123").Return(nil, assert.AnError).Twice()
+		repository := NewShortenedURLRepository(inner, NewLRU(10), time.Minute, time.Second)
+
+		_, err := repository.FindByAlias(0, "abc123")
+		assert.ErrorIs(t, err, assert.AnError)
+		_, err = repository.FindByAlias(0, "abc123")
+		assert.ErrorIs(t, err, assert.AnError)
+
+		inner.AssertNumberOfCalls(t, "FindByAlias", 2)
+	})
+}
+
+func TestCachedShortenedURLRepositoryUnit_Invalidation(t *testing.T) {
+	t.Run("Given a cached miss, when the alias is created, then the next FindByAlias should query the underlying repository", func(t *testing.T) {
+		shortUrl := &entity.ShortenedURL{Alias: "abc123", Url: "http://www.bemobi.com.br"}
+		inner := &service.MockShortenedURLRepository{}
+		inner.On("FindByAlias", 0, "abc123").Return(nil, gorm.ErrRecordNotFound).Once()
+		inner.On("Create", shortUrl).Return(nil)
+		inner.On("FindByAlias", 0, "abc123").Return(shortUrl, nil).Once()
+		repository := NewShortenedURLRepository(inner, NewLRU(10), time.Minute, time.Minute)
+
+		_, err := repository.FindByAlias(0, "abc123")
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+		assert.NoError(t, repository.Create(shortUrl))
+		found, err := repository.FindByAlias(0, "abc123")
+
+		assert.NoError(t, err)
+		assert.Equal(t, shortUrl.Url, found.Url)
+		inner.AssertNumberOfCalls(t, "FindByAlias", 2)
+	})
+
+	t.Run("Given a cached alias, when it is invalidated, then the next FindByAlias should query the underlying repository", func(t *testing.T) {
+		inner := &service.MockShortenedURLRepository{}
+		inner.On("FindByAlias", 0, "abc123").Return(&entity.ShortenedURL{Alias: "abc123"}, nil).Twice()
+		repository := NewShortenedURLRepository(inner, NewLRU(10), time.Minute, time.Minute)
+
+		_, err := repository.FindByAlias(0, "abc123")
+		assert.NoError(t, err)
+		repository.Invalidate(0, "abc123")
+		_, err = repository.FindByAlias(0, "abc123")
+		assert.NoError(t, err)
+
+		inner.AssertNumberOfCalls(t, "FindByAlias", 2)
+	})
+
+	t.Run("Given a cached alias, when ExistsByAlias is called, then it should always check the underlying repository", func(t *testing.T) {
+		inner := &service.MockShortenedURLRepository{}
+		inner.On("FindByAlias", 0, "abc123").Return(&entity.ShortenedURL{Alias: "abc123"}, nil).Once()
+		inner.On("ExistsByAlias", 0, "abc123").Return(false)
+		repository := NewShortenedURLRepository(inner, NewLRU(10), time.Minute, time.Minute)
+
+		_, err := repository.FindByAlias(0, "abc123")
+		assert.NoError(t, err)
+
+		assert.False(t, repository.ExistsByAlias(0, "abc123"))
+		inner.AssertCalled(t, "ExistsByAlias", 0, "abc123")
+	})
+}
+
+func TestCachedShortenedURLRepositoryUnit_WriteInvalidation(t *testing.T) {
+	t.Run("Given a cached alias, when it is updated or deleted, then the next FindByAlias should query the underlying repository", func(t *testing.T) {
+		inner := &service.MockShortenedURLRepository{}
+		shortUrl := &entity.ShortenedURL{ID: 1, Alias: "abc123"}
+		inner.On("FindByAlias", 0, "abc123").Return(shortUrl, nil).Times(3)
+		inner.On("Update", shortUrl).Return(nil)
+		inner.On("SoftDelete", shortUrl).Return(nil)
+		repository := NewShortenedURLRepository(inner, NewLRU(10), time.Minute, time.Minute)
+
+		_, err := repository.FindByAlias(0, "abc123")
+		assert.NoError(t, err)
+		assert.NoError(t, repository.Update(shortUrl))
+		_, err = repository.FindByAlias(0, "abc123")
+		assert.NoError(t, err)
+		assert.NoError(t, repository.SoftDelete(shortUrl))
+		_, err = repository.FindByAlias(0, "abc123")
+		assert.NoError(t, err)
+
+		inner.AssertNumberOfCalls(t, "FindByAlias", 3)
+	})
+}
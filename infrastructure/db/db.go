@@ -6,28 +6,41 @@ import (
 	"os"
 	"time"
 
-	"github.com/lucasfarolfi/hire.me/internal/entity"
 	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
+	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 )
 
-func InitializeDatabase() *gorm.DB {
-	dbUser := os.Getenv("DB_USER")
-	dbPassword := os.Getenv("DB_PASSWORD")
-	dbHost := os.Getenv("DB_HOST")
-	dbPort := os.Getenv("DB_PORT")
-	dbName := os.Getenv("DB_NAME")
+const (
+	DriverMySQL    = "mysql"
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
 
-	if dbHost == "" || dbPort == "" || dbUser == "" || dbPassword == "" || dbName == "" {
-		log.Fatal("One or more DB environment variable are not available!")
+	defaultSQLitePath = "shortener.db"
+)
+
+// InitializeDatabase connects to the database selected by DB_DRIVER (mysql by default, postgres or sqlite).
+// The connection string is taken from DB_DSN when set, otherwise it is built from the driver specific
+// DB_* variables. The schema is left untouched: it is managed by the Migrator.
+func InitializeDatabase() *gorm.DB {
+	driver := os.Getenv("DB_DRIVER")
+	if driver == "" {
+		driver = DriverMySQL
+	}
+	connString, err := connectionString(driver)
+	if err != nil {
+		log.Fatal(err)
+	}
+	dialector, err := openDialector(driver, connString)
+	if err != nil {
+		log.Fatal(err)
 	}
-	connString := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?charset=utf8mb4&parseTime=True&loc=Local", dbUser, dbPassword, dbHost, dbPort, dbName)
 
 	var db *gorm.DB
-	var err error
 	for i := 0; i < 10; i++ {
-		log.Println("Trying to connect to database... Attempt", i+1)
-		db, err = gorm.Open(mysql.Open(connString), &gorm.Config{})
+		log.Printf("Trying to connect to %s database... Attempt %d", driver, i+1)
+		db, err = gorm.Open(dialector, &gorm.Config{TranslateError: true, NowFunc: func() time.Time { return time.Now().UTC() }})
 		if err == nil {
 			sqlDB, err := db.DB()
 			if err != nil {
@@ -48,10 +61,55 @@ func InitializeDatabase() *gorm.DB {
 	if err != nil {
 		panic(err)
 	}
+	return db
+}
 
-	err = db.AutoMigrate(&entity.ShortenedURL{})
-	if err != nil {
-		panic(err)
+func openDialector(driver, connString string) (gorm.Dialector, error) {
+	switch driver {
+	case DriverMySQL:
+		return mysql.Open(connString), nil
+	case DriverPostgres:
+		return postgres.Open(connString), nil
+	case DriverSQLite:
+		return sqlite.Open(connString), nil
 	}
-	return db
+	return nil, fmt.Errorf("DB_DRIVER must be one of mysql, postgres or sqlite, got %q", driver)
+}
+
+// connectionString returns DB_DSN when set, otherwise the DSN built from the driver specific variables:
+// DB_HOST, DB_PORT, DB_USER, DB_PASSWORD and DB_NAME for mysql and postgres (plus the optional
+// DB_SSLMODE for postgres), and DB_PATH for sqlite.
+func connectionString(driver string) (string, error) {
+	if dsn := os.Getenv("DB_DSN"); dsn != "" {
+		return dsn, nil
+	}
+
+	if driver == DriverSQLite {
+		path := os.Getenv("DB_PATH")
+		if path == "" {
+			path = defaultSQLitePath
+		}
+		return path, nil
+	}
+
+	dbUser := os.Getenv("DB_USER")
+	dbPassword := os.Getenv("DB_PASSWORD")
+	dbHost := os.Getenv("DB_HOST")
+	dbPort := os.Getenv("DB_PORT")
+	dbName := os.Getenv("DB_NAME")
+	if dbHost == "" || dbPort == "" || dbUser == "" || dbPassword == "" || dbName == "" {
+		return "", fmt.Errorf("One or more DB environment variable are not available!")
+	}
+
+	switch driver {
+	case DriverMySQL:
+		return fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?charset=utf8mb4&parseTime=True&loc=Local", dbUser, dbPassword, dbHost, dbPort, dbName), nil
+	case DriverPostgres:
+		sslMode := os.Getenv("DB_SSLMODE")
+		if sslMode == "" {
+			sslMode = "disable"
+		}
+		return fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s TimeZone=UTC", dbHost, dbPort, dbUser, dbPassword, dbName, sslMode), nil
+	}
+	return "", fmt.Errorf("DB_DRIVER must be one of mysql, postgres or sqlite, got %q", driver)
 }
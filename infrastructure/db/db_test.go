@@ -0,0 +1,83 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func setDBEnv(t *testing.T) {
+	t.Setenv("DB_DSN", "")
+	t.Setenv("DB_HOST", "localhost")
+	t.Setenv("DB_PORT", "5432")
+	t.Setenv("DB_USER", "user")
+	t.Setenv("DB_PASSWORD", "secret")
+	t.Setenv("DB_NAME", "shortener")
+}
+
+func TestDatabaseUnit_ConnectionString(t *testing.T) {
+	t.Run("Given the mysql driver, when the connection string is built, then it should use the mysql DSN format", func(t *testing.T) {
+		setDBEnv(t)
+
+		dsn, err := connectionString(DriverMySQL)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "user:secret@tcp(localhost:5432)/shortener?charset=utf8mb4&parseTime=True&loc=Local", dsn)
+	})
+
+	t.Run("Given the postgres driver, when the connection string is built, then it should use the postgres keyword format", func(t *testing.T) {
+		setDBEnv(t)
+		t.Setenv("DB_SSLMODE", "require")
+
+		dsn, err := connectionString(DriverPostgres)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "host=localhost port=5432 user=user password=secret dbname=shortener sslmode=require TimeZone=UTC", dsn)
+	})
+
+	t.Run("Given the sqlite driver without a path, when the connection string is built, then it should use the default database file", func(t *testing.T) {
+		t.Setenv("DB_DSN", "")
+		t.Setenv("DB_PATH", "")
+
+		dsn, err := connectionString(DriverSQLite)
+
+		assert.NoError(t, err)
+		assert.Equal(t, defaultSQLitePath, dsn)
+	})
+
+	t.Run("Given a full DSN, when the connection string is built, then it should be used as is", func(t *testing.T) {
+		t.Setenv("DB_DSN", "postgres://user:secret@db:5432/shortener")
+
+		dsn, err := connectionString(DriverPostgres)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "postgres://user:secret@db:5432/shortener", dsn)
+	})
+
+	t.Run("Given missing variables, when the connection string is built, then it should return an error", func(t *testing.T) {
+		setDBEnv(t)
+		t.Setenv("DB_HOST", "")
+
+		_, err := connectionString(DriverMySQL)
+
+		assert.Error(t, err)
+	})
+}
+
+func TestDatabaseUnit_OpenDialector(t *testing.T) {
+	t.Run("Given an unknown driver, when the dialector is opened, then it should return an error", func(t *testing.T) {
+		dialector, err := openDialector("oracle", "dsn")
+
+		assert.Nil(t, dialector)
+		assert.Error(t, err)
+	})
+
+	t.Run("Given a supported driver, when the dialector is opened, then it should return the matching dialector", func(t *testing.T) {
+		for _, driver := range []string{DriverMySQL, DriverPostgres, DriverSQLite} {
+			dialector, err := openDialector(driver, "dsn")
+
+			assert.NoError(t, err)
+			assert.Equal(t, driver, dialector.Name())
+		}
+	})
+}
@@ -0,0 +1,421 @@
+package db
+
+import (
+	"time"
+
+	"github.com/lucasfarolfi/hire.me/internal/entity"
+	"gorm.io/gorm"
+)
+
+// Migrations is the ordered schema history of the app. The structs below are snapshots of the tables
+// at the time of each migration, so later changes to the entities do not rewrite past migrations.
+var Migrations = []Migration{
+	{
+		Version: 1,
+		Name:    "create_shortened_urls",
+		// AutoMigrate adopts databases created before versioned migrations existed.
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&shortenedURLV1{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&shortenedURLV1{})
+		},
+	},
+	{
+		Version: 2,
+		Name:    "create_click_events",
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&clickEventV2{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&clickEventV2{})
+		},
+	},
+	{
+		Version: 3,
+		Name:    "create_id_blocks",
+		Up: func(tx *gorm.DB) error {
+			return tx.Migrator().CreateTable(&idBlockV3{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&idBlockV3{})
+		},
+	},
+	{
+		Version: 4,
+		Name:    "add_shortened_urls_url_hash_and_custom_alias",
+		// Existing rows keep an empty hash, so they are never reused by deduplication.
+		Up: func(tx *gorm.DB) error {
+			if err := tx.Migrator().AddColumn(&shortenedURLV4{}, "URLHash"); err != nil {
+				return err
+			}
+			if err := tx.Migrator().AddColumn(&shortenedURLV4{}, "CustomAlias"); err != nil {
+				return err
+			}
+			return tx.Migrator().CreateIndex(&shortenedURLV4{}, "URLHash")
+		},
+		Down: func(tx *gorm.DB) error {
+			if err := dropIndexIfExists(tx, &shortenedURLV4{}, "URLHash"); err != nil {
+				return err
+			}
+			if err := tx.Migrator().DropColumn(&shortenedURLV4{}, "CustomAlias"); err != nil {
+				return err
+			}
+			return tx.Migrator().DropColumn(&shortenedURLV4{}, "URLHash")
+		},
+	},
+	{
+		Version: 5,
+		Name:    "add_shortened_urls_deleted_at",
+		Up: func(tx *gorm.DB) error {
+			if err := tx.Migrator().AddColumn(&shortenedURLV5{}, "DeletedAt"); err != nil {
+				return err
+			}
+			return tx.Migrator().CreateIndex(&shortenedURLV5{}, "DeletedAt")
+		},
+		Down: func(tx *gorm.DB) error {
+			if err := dropIndexIfExists(tx, &shortenedURLV5{}, "DeletedAt"); err != nil {
+				return err
+			}
+			return tx.Migrator().DropColumn(&shortenedURLV5{}, "DeletedAt")
+		},
+	},
+	{
+		Version: 6,
+		Name:    "add_shortened_urls_url_host",
+		// Existing rows are backfilled from their URL so they can be searched by domain.
+		Up: func(tx *gorm.DB) error {
+			if err := tx.Migrator().AddColumn(&shortenedURLV6{}, "URLHost"); err != nil {
+				return err
+			}
+			if err := tx.Migrator().CreateIndex(&shortenedURLV6{}, "URLHost"); err != nil {
+				return err
+			}
+			var rows []shortenedURLV6
+			return tx.Select("id", "url").Where("url_host IS NULL").FindInBatches(&rows, 500, func(batch *gorm.DB, _ int) error {
+				for _, row := range rows {
+					err := tx.Model(&shortenedURLV6{}).Where("id = ?", row.ID).
+						UpdateColumn("url_host", entity.URLHost(row.Url)).Error
+					if err != nil {
+						return err
+					}
+				}
+				return nil
+			}).Error
+		},
+		Down: func(tx *gorm.DB) error {
+			if err := dropIndexIfExists(tx, &shortenedURLV6{}, "URLHost"); err != nil {
+				return err
+			}
+			return tx.Migrator().DropColumn(&shortenedURLV6{}, "URLHost")
+		},
+	},
+	{
+		Version: 7,
+		Name:    "create_api_keys",
+		Up: func(tx *gorm.DB) error {
+			return tx.Migrator().CreateTable(&apiKeyV7{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&apiKeyV7{})
+		},
+	},
+	{
+		Version: 8,
+		Name:    "add_shortened_urls_owner_id",
+		// Existing rows get an empty owner, so only admin keys can manage them.
+		Up: func(tx *gorm.DB) error {
+			if err := tx.Migrator().AddColumn(&shortenedURLV8{}, "OwnerID"); err != nil {
+				return err
+			}
+			return tx.Migrator().CreateIndex(&shortenedURLV8{}, "OwnerID")
+		},
+		Down: func(tx *gorm.DB) error {
+			if err := dropIndexIfExists(tx, &shortenedURLV8{}, "OwnerID"); err != nil {
+				return err
+			}
+			return tx.Migrator().DropColumn(&shortenedURLV8{}, "OwnerID")
+		},
+	},
+	{
+		Version: 9,
+		Name:    "create_tenants",
+		Up: func(tx *gorm.DB) error {
+			return tx.Migrator().CreateTable(&tenantV9{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&tenantV9{})
+		},
+	},
+	{
+		Version: 10,
+		Name:    "add_tenant_id",
+		// Existing rows and keys belong to the default tenant. Aliases become unique per tenant, so the
+		// global unique constraint on the alias is replaced by a unique index on the tenant and the alias.
+		Up: func(tx *gorm.DB) error {
+			if err := tx.Migrator().AddColumn(&apiKeyV10{}, "TenantID"); err != nil {
+				return err
+			}
+			if err := tx.Migrator().CreateIndex(&apiKeyV10{}, "TenantID"); err != nil {
+				return err
+			}
+			if err := tx.Migrator().AddColumn(&shortenedURLV10{}, "TenantID"); err != nil {
+				return err
+			}
+			if tx.Migrator().HasConstraint(&shortenedURLV1{}, "Alias") {
+				if err := tx.Migrator().DropConstraint(&shortenedURLV1{}, "Alias"); err != nil {
+					return err
+				}
+			}
+			return createMissingIndexes(tx, &shortenedURLV10{})
+		},
+		Down: func(tx *gorm.DB) error {
+			if err := dropIndexIfExists(tx, &shortenedURLV10{}, "idx_shortened_urls_tenant_alias"); err != nil {
+				return err
+			}
+			if err := tx.Migrator().DropColumn(&shortenedURLV10{}, "TenantID"); err != nil {
+				return err
+			}
+			if err := tx.Migrator().CreateConstraint(&shortenedURLV1{}, "Alias"); err != nil {
+				return err
+			}
+			if err := createMissingIndexes(tx, &shortenedURLV8Indexes{}); err != nil {
+				return err
+			}
+			if err := dropIndexIfExists(tx, &apiKeyV10{}, "TenantID"); err != nil {
+				return err
+			}
+			return tx.Migrator().DropColumn(&apiKeyV10{}, "TenantID")
+		},
+	},
+	{
+		Version: 11,
+		Name:    "create_domains",
+		Up: func(tx *gorm.DB) error {
+			return tx.Migrator().CreateTable(&domainV11{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&domainV11{})
+		},
+	},
+	{
+		Version: 12,
+		Name:    "add_shortened_urls_flags",
+		Up: func(tx *gorm.DB) error {
+			for _, field := range []string{"FlaggedAt", "FlagReason"} {
+				if err := tx.Migrator().AddColumn(&shortenedURLV12{}, field); err != nil {
+					return err
+				}
+			}
+			return tx.Migrator().CreateIndex(&shortenedURLV12{}, "FlaggedAt")
+		},
+		Down: func(tx *gorm.DB) error {
+			if err := dropIndexIfExists(tx, &shortenedURLV12{}, "FlaggedAt"); err != nil {
+				return err
+			}
+			for _, field := range []string{"FlagReason", "FlaggedAt"} {
+				if err := tx.Migrator().DropColumn(&shortenedURLV12{}, field); err != nil {
+					return err
+				}
+			}
+			return createMissingIndexes(tx, &shortenedURLV10{})
+		},
+	},
+}
+
+// dropIndexIfExists drops the index unless it is already gone. SQLite drops columns by recreating the
+// table, which loses the indexes created by earlier migrations.
+func dropIndexIfExists(tx *gorm.DB, model interface{}, name string) error {
+	if !tx.Migrator().HasIndex(model, name) {
+		return nil
+	}
+	return tx.Migrator().DropIndex(model, name)
+}
+
+// createMissingIndexes creates the indexes of the snapshot that do not exist. SQLite changes constraints
+// and drops columns by recreating the table, which loses every index.
+func createMissingIndexes(tx *gorm.DB, model interface{}) error {
+	indexes, err := indexNames(tx, model)
+	if err != nil {
+		return err
+	}
+	for _, name := range indexes {
+		if tx.Migrator().HasIndex(model, name) {
+			continue
+		}
+		if err := tx.Migrator().CreateIndex(model, name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func indexNames(tx *gorm.DB, model interface{}) ([]string, error) {
+	stmt := &gorm.Statement{DB: tx}
+	if err := stmt.Parse(model); err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, index := range stmt.Schema.ParseIndexes() {
+		names = append(names, index.Name)
+	}
+	return names, nil
+}
+
+type shortenedURLV1 struct {
+	ID             int        `gorm:"primaryKey;autoIncrement"`
+	Alias          string     `gorm:"column:alias;unique"`
+	Url            string     `gorm:"column:url"`
+	AccessTimes    int32      `gorm:"column:access_times"`
+	MaxAccessTimes *int32     `gorm:"column:max_access_times"`
+	RedirectType   int        `gorm:"column:redirect_type"`
+	ExpiresAt      *time.Time `gorm:"column:expires_at;index"`
+	CreatedAt      time.Time  `gorm:"column:created_at"`
+}
+
+func (shortenedURLV1) TableName() string {
+	return "shortened_urls"
+}
+
+type clickEventV2 struct {
+	ID             int       `gorm:"primaryKey;autoIncrement"`
+	ShortenedURLID int       `gorm:"column:shortened_url_id;index:idx_click_events_shortened_url_id_clicked_at,priority:1"`
+	Alias          string    `gorm:"column:alias;index"`
+	ClickedAt      time.Time `gorm:"column:clicked_at;index;index:idx_click_events_shortened_url_id_clicked_at,priority:2"`
+	Referrer       string    `gorm:"column:referrer"`
+	UserAgent      string    `gorm:"column:user_agent"`
+	ClientIP       string    `gorm:"column:client_ip"`
+	AcceptLanguage string    `gorm:"column:accept_language"`
+}
+
+func (clickEventV2) TableName() string {
+	return "click_events"
+}
+
+type idBlockV3 struct {
+	Name      string `gorm:"column:name;primaryKey;size:64"`
+	NextValue int64  `gorm:"column:next_value"`
+}
+
+func (idBlockV3) TableName() string {
+	return "id_blocks"
+}
+
+type shortenedURLV4 struct {
+	URLHash     string `gorm:"column:url_hash;size:64;index"`
+	CustomAlias bool   `gorm:"column:custom_alias"`
+}
+
+func (shortenedURLV4) TableName() string {
+	return "shortened_urls"
+}
+
+type shortenedURLV5 struct {
+	DeletedAt *time.Time `gorm:"column:deleted_at;index"`
+}
+
+func (shortenedURLV5) TableName() string {
+	return "shortened_urls"
+}
+
+type shortenedURLV6 struct {
+	ID      int    `gorm:"primaryKey;autoIncrement"`
+	Url     string `gorm:"column:url"`
+	URLHost string `gorm:"column:url_host;size:255;index"`
+}
+
+func (shortenedURLV6) TableName() string {
+	return "shortened_urls"
+}
+
+type apiKeyV7 struct {
+	ID        int        `gorm:"primaryKey;autoIncrement"`
+	OwnerID   string     `gorm:"column:owner_id;size:64;index"`
+	Name      string     `gorm:"column:name"`
+	KeyHash   string     `gorm:"column:key_hash;size:64;unique"`
+	Prefix    string     `gorm:"column:prefix;size:16"`
+	Admin     bool       `gorm:"column:admin"`
+	CreatedAt time.Time  `gorm:"column:created_at"`
+	RevokedAt *time.Time `gorm:"column:revoked_at"`
+}
+
+func (apiKeyV7) TableName() string {
+	return "api_keys"
+}
+
+type shortenedURLV8 struct {
+	OwnerID string `gorm:"column:owner_id;size:64;not null;default:'';index"`
+}
+
+func (shortenedURLV8) TableName() string {
+	return "shortened_urls"
+}
+
+type tenantV9 struct {
+	ID        int       `gorm:"primaryKey;autoIncrement"`
+	Slug      string    `gorm:"column:slug;size:63;unique"`
+	Name      string    `gorm:"column:name"`
+	MaxLinks  *int      `gorm:"column:max_links"`
+	CreatedAt time.Time `gorm:"column:created_at"`
+}
+
+func (tenantV9) TableName() string {
+	return "tenants"
+}
+
+type apiKeyV10 struct {
+	TenantID int `gorm:"column:tenant_id;not null;default:0;index"`
+}
+
+func (apiKeyV10) TableName() string {
+	return "api_keys"
+}
+
+// shortenedURLV8Indexes lists every index of the shortened urls table before tenants existed.
+type shortenedURLV8Indexes struct {
+	ExpiresAt *time.Time `gorm:"column:expires_at;index"`
+	URLHash   string     `gorm:"column:url_hash;size:64;index"`
+	DeletedAt *time.Time `gorm:"column:deleted_at;index"`
+	URLHost   string     `gorm:"column:url_host;size:255;index"`
+	OwnerID   string     `gorm:"column:owner_id;size:64;not null;default:'';index"`
+}
+
+func (shortenedURLV8Indexes) TableName() string {
+	return "shortened_urls"
+}
+
+type shortenedURLV10 struct {
+	ExpiresAt *time.Time `gorm:"column:expires_at;index"`
+	URLHash   string     `gorm:"column:url_hash;size:64;index"`
+	DeletedAt *time.Time `gorm:"column:deleted_at;index"`
+	URLHost   string     `gorm:"column:url_host;size:255;index"`
+	OwnerID   string     `gorm:"column:owner_id;size:64;not null;default:'';index"`
+	Alias     string     `gorm:"column:alias;uniqueIndex:idx_shortened_urls_tenant_alias,priority:2"`
+	TenantID  int        `gorm:"column:tenant_id;not null;default:0;uniqueIndex:idx_shortened_urls_tenant_alias,priority:1"`
+}
+
+func (shortenedURLV10) TableName() string {
+	return "shortened_urls"
+}
+
+type domainV11 struct {
+	ID        int       `gorm:"primaryKey;autoIncrement"`
+	Host      string    `gorm:"column:host;size:253;unique"`
+	TenantID  int       `gorm:"column:tenant_id;not null;default:0;index"`
+	Scheme    string    `gorm:"column:scheme;size:5;not null;default:'https'"`
+	Canonical bool      `gorm:"column:canonical"`
+	CreatedAt time.Time `gorm:"column:created_at"`
+}
+
+func (domainV11) TableName() string {
+	return "domains"
+}
+
+type shortenedURLV12 struct {
+	FlaggedAt  *time.Time `gorm:"column:flagged_at;index"`
+	FlagReason string     `gorm:"column:flag_reason;size:255;not null;default:''"`
+}
+
+func (shortenedURLV12) TableName() string {
+	return "shortened_urls"
+}
@@ -0,0 +1,249 @@
+package db
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/lucasfarolfi/hire.me/internal/entity"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+func loadDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{TranslateError: true})
+	if err != nil {
+		t.Fatalf("Failed to connect to database: %v", err)
+	}
+	return db
+}
+
+func TestMigratorIntegration_Up(t *testing.T) {
+	t.Run("Given an empty database, when Up is called, then it should apply every migration in order and record them", func(t *testing.T) {
+		db := loadDB(t)
+		migrator := NewMigrator(db, Migrations)
+
+		applied, err := migrator.Up()
+
+		assert.NoError(t, err)
+		assert.Len(t, applied, len(Migrations))
+		for i, migration := range applied {
+			assert.Equal(t, i+1, migration.Version)
+		}
+		pending, err := migrator.Pending()
+		assert.NoError(t, err)
+		assert.Empty(t, pending)
+	})
+
+	t.Run("Given an up to date database, when Up is called again, then it should apply nothing", func(t *testing.T) {
+		db := loadDB(t)
+		migrator := NewMigrator(db, Migrations)
+		_, err := migrator.Up()
+		assert.NoError(t, err)
+
+		applied, err := migrator.Up()
+
+		assert.NoError(t, err)
+		assert.Empty(t, applied)
+	})
+
+	t.Run("Given a database created by AutoMigrate, when Up is called, then it should adopt the existing tables", func(t *testing.T) {
+		db := loadDB(t)
+		assert.NoError(t, db.AutoMigrate(&shortenedURLV1{}, &clickEventV2{}))
+		assert.NoError(t, db.Create(&shortenedURLV1{Alias: "abc123", Url: "http://www.bemobi.com.br"}).Error)
+
+		_, err := NewMigrator(db, Migrations).Up()
+
+		assert.NoError(t, err)
+		var count int64
+		db.Model(&entity.ShortenedURL{}).Count(&count)
+		assert.Equal(t, int64(1), count)
+	})
+
+	t.Run("Given shortened urls created before the url host column, when Up is called, then it should backfill their hosts", func(t *testing.T) {
+		db := loadDB(t)
+		_, err := NewMigrator(db, Migrations[:5]).Up()
+		assert.NoError(t, err)
+		assert.NoError(t, db.Create(&shortenedURLV1{Alias: "abc123", Url: "http://WWW.Bemobi.com.br:8080/path"}).Error)
+
+		_, err = NewMigrator(db, Migrations).Up()
+
+		assert.NoError(t, err)
+		var shortUrl entity.ShortenedURL
+		assert.NoError(t, db.Where("alias = ?", "abc123").First(&shortUrl).Error)
+		assert.Equal(t, "www.bemobi.com.br", shortUrl.URLHost)
+	})
+
+	t.Run("Given shortened urls created before owners existed, when Up is called, then they should have an empty owner", func(t *testing.T) {
+		db := loadDB(t)
+		_, err := NewMigrator(db, Migrations[:7]).Up()
+		assert.NoError(t, err)
+		assert.NoError(t, db.Create(&shortenedURLV1{Alias: "abc123", Url: "http://www.bemobi.com.br"}).Error)
+
+		_, err = NewMigrator(db, Migrations).Up()
+
+		assert.NoError(t, err)
+		var count int64
+		assert.NoError(t, db.Model(&entity.ShortenedURL{}).Where("owner_id = ?", "").Count(&count).Error)
+		assert.Equal(t, int64(1), count)
+	})
+
+	t.Run("Given shortened urls created before tenants existed, when Up is called, then they should belong to the default tenant and aliases should be unique per tenant", func(t *testing.T) {
+		db := loadDB(t)
+		_, err := NewMigrator(db, Migrations[:9]).Up()
+		assert.NoError(t, err)
+		assert.NoError(t, db.Create(&shortenedURLV1{Alias: "abc123", Url: "http://www.bemobi.com.br"}).Error)
+
+		_, err = NewMigrator(db, Migrations).Up()
+
+		assert.NoError(t, err)
+		var shortUrl entity.ShortenedURL
+		assert.NoError(t, db.Where("alias = ?", "abc123").First(&shortUrl).Error)
+		assert.Equal(t, entity.DefaultTenantID, shortUrl.TenantID)
+		assert.NoError(t, db.Create(&entity.ShortenedURL{Alias: "abc123", Url: "http://www.bemobi.com.br", TenantID: 1}).Error)
+		assert.Error(t, db.Create(&entity.ShortenedURL{Alias: "abc123", Url: "http://www.bemobi.com.br", TenantID: 1}).Error)
+		for _, index := range []string{"idx_shortened_urls_tenant_alias", "idx_shortened_urls_expires_at", "idx_shortened_urls_url_hash",
+			"idx_shortened_urls_deleted_at", "idx_shortened_urls_url_host", "idx_shortened_urls_owner_id"} {
+			assert.True(t, db.Migrator().HasIndex(&entity.ShortenedURL{}, index), "index %s should exist", index)
+		}
+	})
+
+	t.Run("Given a failing migration, when Up is called, then it should stop and leave it pending", func(t *testing.T) {
+		db := loadDB(t)
+		failing := Migration{
+			Version: len(Migrations) + 1,
+			Name:    "broken",
+			Up:      func(tx *gorm.DB) error { return tx.Exec("SELECT * FROM missing_table").Error },
+			Down:    func(tx *gorm.DB) error { return nil },
+		}
+		migrator := NewMigrator(db, append(Migrations[:len(Migrations):len(Migrations)], failing))
+
+		applied, err := migrator.Up()
+
+		assert.Error(t, err)
+		assert.Len(t, applied, len(Migrations))
+		pending, _ := migrator.Pending()
+		assert.Equal(t, []int{failing.Version}, versions(pending))
+	})
+}
+
+func TestMigratorIntegration_Schema(t *testing.T) {
+	t.Run("Given a migrated database, when the entities are inspected, then every entity column should exist", func(t *testing.T) {
+		db := loadDB(t)
+		_, err := NewMigrator(db, Migrations).Up()
+		assert.NoError(t, err)
+
+		for _, model := range []interface{}{&entity.ShortenedURL{}, &entity.ClickEvent{}, &entity.IDBlock{}, &entity.APIKey{}, &entity.Tenant{}, &entity.Domain{}} {
+			s, err := schema.Parse(model, &sync.Map{}, db.NamingStrategy)
+			assert.NoError(t, err)
+			assert.True(t, db.Migrator().HasTable(model), "table %s should exist", s.Table)
+			for _, field := range s.Fields {
+				if field.DBName != "" {
+					assert.True(t, db.Migrator().HasColumn(model, field.DBName), "column %s.%s should exist, add a migration for it", s.Table, field.DBName)
+				}
+			}
+		}
+	})
+}
+
+func TestMigratorIntegration_Down(t *testing.T) {
+	t.Run("Given a migrated database, when Down is called, then it should roll back only the last migration", func(t *testing.T) {
+		db := loadDB(t)
+		migrator := NewMigrator(db, Migrations)
+		_, err := migrator.Up()
+		assert.NoError(t, err)
+
+		migration, err := migrator.Down()
+
+		assert.NoError(t, err)
+		assert.Equal(t, len(Migrations), migration.Version)
+		pending, _ := migrator.Pending()
+		assert.Equal(t, []int{len(Migrations)}, versions(pending))
+		assert.True(t, db.Migrator().HasTable("shortened_urls"))
+	})
+
+	t.Run("Given a database with tenants, when the tenant migration is rolled back, then aliases should be unique again", func(t *testing.T) {
+		db := loadDB(t)
+		migrator := NewMigrator(db, Migrations[:10])
+		_, err := migrator.Up()
+		assert.NoError(t, err)
+
+		migration, err := migrator.Down()
+
+		assert.NoError(t, err)
+		assert.Equal(t, 10, migration.Version)
+		assert.False(t, db.Migrator().HasColumn("shortened_urls", "tenant_id"))
+		assert.False(t, db.Migrator().HasColumn("api_keys", "tenant_id"))
+		assert.True(t, db.Migrator().HasIndex("shortened_urls", "idx_shortened_urls_owner_id"))
+		assert.NoError(t, db.Create(&shortenedURLV1{Alias: "abc123", Url: "http://www.bemobi.com.br"}).Error)
+		assert.Error(t, db.Create(&shortenedURLV1{Alias: "abc123", Url: "http://www.bemobi.com.br"}).Error)
+	})
+
+	t.Run("Given a database with link flags, when the flags migration is rolled back, then the other indexes should remain", func(t *testing.T) {
+		db := loadDB(t)
+		migrator := NewMigrator(db, Migrations[:12])
+		_, err := migrator.Up()
+		assert.NoError(t, err)
+
+		migration, err := migrator.Down()
+
+		assert.NoError(t, err)
+		assert.Equal(t, 12, migration.Version)
+		assert.False(t, db.Migrator().HasColumn("shortened_urls", "flagged_at"))
+		assert.False(t, db.Migrator().HasColumn("shortened_urls", "flag_reason"))
+		for _, index := range []string{"idx_shortened_urls_tenant_alias", "idx_shortened_urls_owner_id", "idx_shortened_urls_url_host"} {
+			assert.True(t, db.Migrator().HasIndex("shortened_urls", index), "index %s should exist", index)
+		}
+	})
+
+	t.Run("Given an empty database, when Down is called, then it should report there is nothing to roll back", func(t *testing.T) {
+		migration, err := NewMigrator(loadDB(t), Migrations).Down()
+
+		assert.Nil(t, migration)
+		assert.ErrorIs(t, err, ErrNoMigrationToRollback)
+	})
+
+	t.Run("Given every migration rolled back, when Up is called again, then it should recreate the schema", func(t *testing.T) {
+		db := loadDB(t)
+		migrator := NewMigrator(db, Migrations)
+		_, err := migrator.Up()
+		assert.NoError(t, err)
+		for range Migrations {
+			_, err := migrator.Down()
+			assert.NoError(t, err)
+		}
+		assert.False(t, db.Migrator().HasTable("shortened_urls"))
+
+		applied, err := migrator.Up()
+
+		assert.NoError(t, err)
+		assert.Len(t, applied, len(Migrations))
+	})
+}
+
+func TestMigratorIntegration_Status(t *testing.T) {
+	t.Run("Given a partially migrated database, when Status is called, then it should list applied and pending migrations", func(t *testing.T) {
+		db := loadDB(t)
+		migrator := NewMigrator(db, Migrations)
+		_, err := migrator.Up()
+		assert.NoError(t, err)
+		_, err = migrator.Down()
+		assert.NoError(t, err)
+
+		statuses, err := migrator.Status()
+
+		assert.NoError(t, err)
+		assert.Len(t, statuses, len(Migrations))
+		assert.True(t, statuses[0].Applied())
+		assert.False(t, statuses[len(statuses)-1].Applied())
+	})
+}
+
+func versions(migrations []Migration) []int {
+	var result []int
+	for _, migration := range migrations {
+		result = append(result, migration.Version)
+	}
+	return result
+}
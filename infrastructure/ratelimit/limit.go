@@ -0,0 +1,41 @@
+package ratelimit
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrInvalidLimit = fmt.Errorf("rate limit must be requests/period, e.g. 60/1m")
+
+// Limit allows Requests requests per Period, in bursts of up to Requests requests.
+type Limit struct {
+	Requests int
+	Period   time.Duration
+}
+
+// ParseLimit parses limits written as requests/period, such as 60/1m or 5/1s.
+func ParseLimit(value string) (Limit, error) {
+	requests, period, ok := strings.Cut(strings.TrimSpace(value), "/")
+	if !ok {
+		return Limit{}, ErrInvalidLimit
+	}
+	limit := Limit{}
+	var err error
+	if limit.Requests, err = strconv.Atoi(requests); err != nil || limit.Requests <= 0 {
+		return Limit{}, ErrInvalidLimit
+	}
+	if limit.Period, err = time.ParseDuration(period); err != nil || limit.Period <= 0 {
+		return Limit{}, ErrInvalidLimit
+	}
+	return limit, nil
+}
+
+// Store keeps a token bucket per key. Implementations backed by shared stores, such as Redis, can be
+// plugged in wherever a Store is expected so every instance of the app enforces the same limits.
+type Store interface {
+	// Take removes n tokens from the bucket of key, reporting whether they were available and, when they
+	// were not, how long until they are. More than limit.Requests tokens are never available at once.
+	Take(key string, limit Limit, n int) (allowed bool, retryAfter time.Duration, err error)
+}
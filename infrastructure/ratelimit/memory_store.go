@@ -0,0 +1,73 @@
+package ratelimit
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// MemoryStore is a thread-safe in-memory Store bounded to a maximum number of buckets, evicting the least
+// recently used bucket when full. Evicted clients start over with a full bucket.
+type MemoryStore struct {
+	capacity int
+	now      func() time.Time
+
+	mu      sync.Mutex
+	buckets *list.List
+	items   map[string]*list.Element
+}
+
+type bucket struct {
+	key       string
+	tokens    float64
+	updatedAt time.Time
+}
+
+func NewMemoryStore(capacity int) *MemoryStore {
+	return &MemoryStore{
+		capacity: capacity,
+		now:      time.Now,
+		buckets:  list.New(),
+		items:    make(map[string]*list.Element),
+	}
+}
+
+func (s *MemoryStore) Take(key string, limit Limit, n int) (bool, time.Duration, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	capacity := float64(limit.Requests)
+	element, ok := s.items[key]
+	if !ok {
+		element = s.buckets.PushFront(&bucket{key: key, tokens: capacity, updatedAt: now})
+		s.items[key] = element
+		if s.buckets.Len() > s.capacity {
+			s.remove(s.buckets.Back())
+		}
+	}
+	s.buckets.MoveToFront(element)
+
+	b := element.Value.(*bucket)
+	perToken := float64(limit.Period) / capacity
+	b.tokens = min(capacity, b.tokens+float64(now.Sub(b.updatedAt))/perToken)
+	b.updatedAt = now
+	cost := float64(n)
+	if b.tokens >= cost {
+		b.tokens -= cost
+		return true, 0, nil
+	}
+	return false, time.Duration((cost - b.tokens) * perToken), nil
+}
+
+// Len returns the number of buckets currently stored.
+func (s *MemoryStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.buckets.Len()
+}
+
+func (s *MemoryStore) remove(element *list.Element) {
+	s.buckets.Remove(element)
+	delete(s.items, element.Value.(*bucket).key)
+}
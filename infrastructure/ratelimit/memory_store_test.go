@@ -0,0 +1,108 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseLimitUnit(t *testing.T) {
+	t.Run("Given requests per period, when it is parsed, then it should return the limit", func(t *testing.T) {
+		limit, err := ParseLimit(" 60/1m ")
+
+		assert.NoError(t, err)
+		assert.Equal(t, Limit{Requests: 60, Period: time.Minute}, limit)
+	})
+
+	t.Run("Given malformed limits, when they are parsed, then it should reject them", func(t *testing.T) {
+		for _, value := range []string{"", "60", "60/", "/1m", "0/1m", "-1/1m", "60/0s", "60/minute", "sixty/1m"} {
+			_, err := ParseLimit(value)
+			assert.ErrorIs(t, err, ErrInvalidLimit, value)
+		}
+	})
+}
+
+func TestMemoryStoreUnit_Take(t *testing.T) {
+	limit := Limit{Requests: 2, Period: time.Minute}
+
+	t.Run("Given an empty bucket, when a token is taken, then it should be rejected until a token is refilled", func(t *testing.T) {
+		now := time.Now()
+		store := NewMemoryStore(10)
+		store.now = func() time.Time { return now }
+
+		for i := 0; i < 2; i++ {
+			allowed, _, err := store.Take("client", limit, 1)
+			assert.NoError(t, err)
+			assert.True(t, allowed, "The burst should be allowed")
+		}
+		allowed, retryAfter, err := store.Take("client", limit, 1)
+		assert.NoError(t, err)
+		assert.False(t, allowed)
+		assert.Equal(t, 30*time.Second, retryAfter)
+
+		now = now.Add(20 * time.Second)
+		_, retryAfter, _ = store.Take("client", limit, 1)
+		assert.Equal(t, 10*time.Second, retryAfter, "The wait should shrink while the token refills")
+		now = now.Add(10 * time.Second)
+		allowed, _, _ = store.Take("client", limit, 1)
+		assert.True(t, allowed)
+	})
+
+	t.Run("Given a bucket idle for long, when tokens are taken, then it should refill only up to the burst", func(t *testing.T) {
+		now := time.Now()
+		store := NewMemoryStore(10)
+		store.now = func() time.Time { return now }
+		store.Take("client", limit, 1)
+
+		now = now.Add(time.Hour)
+		allowed := 0
+		for i := 0; i < 5; i++ {
+			if ok, _, _ := store.Take("client", limit, 1); ok {
+				allowed++
+			}
+		}
+
+		assert.Equal(t, 2, allowed)
+	})
+
+	t.Run("Given a bucket, when several tokens are taken at once, then it should reject costs above the tokens left", func(t *testing.T) {
+		now := time.Now()
+		store := NewMemoryStore(10)
+		store.now = func() time.Time { return now }
+
+		allowed, retryAfter, err := store.Take("client", limit, 3)
+		assert.NoError(t, err)
+		assert.False(t, allowed, "A cost above the burst should be rejected")
+		assert.Equal(t, 30*time.Second, retryAfter)
+
+		allowed, _, _ = store.Take("client", limit, 2)
+		assert.True(t, allowed, "The rejected cost should not take tokens")
+		allowed, _, _ = store.Take("client", limit, 1)
+		assert.False(t, allowed)
+	})
+
+	t.Run("Given an empty bucket, when another key takes a token, then it should have its own bucket", func(t *testing.T) {
+		store := NewMemoryStore(10)
+		store.Take("client", limit, 1)
+		store.Take("client", limit, 1)
+
+		allowed, _, err := store.Take("other", limit, 1)
+
+		assert.NoError(t, err)
+		assert.True(t, allowed)
+	})
+
+	t.Run("Given a full store, when a new key takes a token, then it should evict the least recently used bucket", func(t *testing.T) {
+		store := NewMemoryStore(2)
+		store.Take("first", limit, 1)
+		store.Take("first", limit, 1)
+		store.Take("second", limit, 1)
+		store.Take("third", limit, 1)
+
+		allowed, _, _ := store.Take("first", limit, 1)
+
+		assert.True(t, allowed, "The evicted bucket should start over full")
+		assert.Equal(t, 2, store.Len())
+	})
+}
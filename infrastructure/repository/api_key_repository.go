@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/lucasfarolfi/hire.me/internal/entity"
+	"gorm.io/gorm"
+)
+
+type APIKeyRepository struct {
+	DB *gorm.DB
+}
+
+func NewAPIKeyRepository(db *gorm.DB) *APIKeyRepository {
+	return &APIKeyRepository{DB: db}
+}
+
+func (ar *APIKeyRepository) Create(key *entity.APIKey) error {
+	return ar.DB.Create(key).Error
+}
+
+func (ar *APIKeyRepository) FindByHash(keyHash string) (*entity.APIKey, error) {
+	var key entity.APIKey
+	err := ar.DB.Where("key_hash = ?", keyHash).First(&key).Error
+	if err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+func (ar *APIKeyRepository) FindAll() ([]entity.APIKey, error) {
+	var keys []entity.APIKey
+	err := ar.DB.Order("id").Find(&keys).Error
+	if err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+// Revoke marks the API key as revoked at revokedAt, keeping the earliest revocation of a key revoked twice.
+func (ar *APIKeyRepository) Revoke(id int, revokedAt time.Time) error {
+	var key entity.APIKey
+	if err := ar.DB.First(&key, id).Error; err != nil {
+		return err
+	}
+	return ar.DB.Model(&entity.APIKey{}).Where("id = ? AND revoked_at IS NULL", id).
+		UpdateColumn("revoked_at", revokedAt).Error
+}
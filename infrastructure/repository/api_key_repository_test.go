@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lucasfarolfi/hire.me/internal/entity"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestAPIKeyRepositoryIntegration(t *testing.T) {
+	t.Run("Given a created key, when it is found by hash and revoked, then it should keep the first revocation", func(t *testing.T) {
+		repository := NewAPIKeyRepository(loadDB(t))
+		key := &entity.APIKey{OwnerID: "alice", Name: "ci", KeyHash: entity.HashAPIKey("hk_secret"), Prefix: "hk_sec",
+			CreatedAt: time.Now().UTC()}
+		assert.NoError(t, repository.Create(key))
+		assert.ErrorIs(t, repository.Create(&entity.APIKey{OwnerID: "bob", KeyHash: key.KeyHash}), gorm.ErrDuplicatedKey)
+
+		found, err := repository.FindByHash(entity.HashAPIKey("hk_secret"))
+		assert.NoError(t, err)
+		assert.Equal(t, "alice", found.OwnerID)
+		assert.False(t, found.IsRevoked())
+
+		revokedAt := time.Now().UTC().Truncate(time.Second)
+		assert.NoError(t, repository.Revoke(key.ID, revokedAt))
+		assert.NoError(t, repository.Revoke(key.ID, revokedAt.Add(time.Hour)))
+		keys, err := repository.FindAll()
+		assert.NoError(t, err)
+		assert.Len(t, keys, 1)
+		assert.True(t, revokedAt.Equal(*keys[0].RevokedAt))
+
+		_, err = repository.FindByHash(entity.HashAPIKey("hk_unknown"))
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+		assert.ErrorIs(t, repository.Revoke(key.ID+1, revokedAt), gorm.ErrRecordNotFound)
+	})
+}
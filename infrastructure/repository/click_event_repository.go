@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/lucasfarolfi/hire.me/internal/entity"
+	"gorm.io/gorm"
+)
+
+type ClickEventRepository struct {
+	DB *gorm.DB
+}
+
+func NewClickEventRepository(db *gorm.DB) *ClickEventRepository {
+	return &ClickEventRepository{DB: db}
+}
+
+func (cr *ClickEventRepository) Create(event *entity.ClickEvent) error {
+	return cr.DB.Create(event).Error
+}
+
+// CountByBucket counts the clicks of the shortened URL between from and to in buckets of step length
+// starting at bucketStart, keyed by the bucket index. The clicks are bucketed by the database, so only
+// the counts are loaded.
+func (cr *ClickEventRepository) CountByBucket(shortenedURLID int, from, to, bucketStart time.Time, step time.Duration) (map[int]int64, error) {
+	var rows []struct {
+		Bucket int
+		Count  int64
+	}
+	err := cr.inRange(shortenedURLID, from, to).
+		Select(cr.bucketExpression()+" AS bucket, COUNT(*) AS count", bucketStart, int64(step/time.Second)).
+		Group("bucket").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	counts := make(map[int]int64, len(rows))
+	for _, row := range rows {
+		counts[row.Bucket] = row.Count
+	}
+	return counts, nil
+}
+
+// bucketExpression is the index of the bucket of clicked_at, given the start of the first bucket and the
+// bucket length in seconds, in the SQL dialect of the database.
+func (cr *ClickEventRepository) bucketExpression() string {
+	switch cr.DB.Dialector.Name() {
+	case "mysql":
+		return "FLOOR(TIMESTAMPDIFF(SECOND, ?, clicked_at) / ?)"
+	case "postgres":
+		return "FLOOR(EXTRACT(EPOCH FROM clicked_at - CAST(? AS timestamptz)) / ?)"
+	default:
+		return "(CAST(strftime('%s', clicked_at) AS INTEGER) - CAST(strftime('%s', ?) AS INTEGER)) / ?"
+	}
+}
+
+func (cr *ClickEventRepository) CountUniqueVisitors(shortenedURLID int, from, to time.Time) (int64, error) {
+	var count int64
+	err := cr.inRange(shortenedURLID, from, to).Distinct("client_ip").Count(&count).Error
+	return count, err
+}
+
+func (cr *ClickEventRepository) CountByReferrer(shortenedURLID int, from, to time.Time, limit int) ([]entity.ClickCount, error) {
+	return cr.countBy("referrer", shortenedURLID, from, to, limit)
+}
+
+func (cr *ClickEventRepository) CountByUserAgent(shortenedURLID int, from, to time.Time) ([]entity.ClickCount, error) {
+	return cr.countBy("user_agent", shortenedURLID, from, to, -1)
+}
+
+func (cr *ClickEventRepository) countBy(column string, shortenedURLID int, from, to time.Time, limit int) ([]entity.ClickCount, error) {
+	var counts []entity.ClickCount
+	err := cr.inRange(shortenedURLID, from, to).
+		Select(column + " AS value, COUNT(*) AS count").
+		Group(column).
+		Order("count DESC").
+		Order(column).
+		Limit(limit).
+		Scan(&counts).Error
+	if err != nil {
+		return nil, err
+	}
+	return counts, nil
+}
+
+func (cr *ClickEventRepository) inRange(shortenedURLID int, from, to time.Time) *gorm.DB {
+	return cr.DB.Model(&entity.ClickEvent{}).
+		Where("shortened_url_id = ? AND clicked_at >= ? AND clicked_at < ?", shortenedURLID, from, to)
+}
@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lucasfarolfi/hire.me/internal/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClickEventRepositoryIntegration_Create(t *testing.T) {
+	t.Run("Given a valid click event, when the create method is called, then it should store the click event in database", func(t *testing.T) {
+		db := loadDB(t)
+		repository := NewClickEventRepository(db)
+
+		event := &entity.ClickEvent{
+			ShortenedURLID: 1,
+			Alias:          "abc123",
+			ClickedAt:      time.Now().UTC().Truncate(time.Second),
+			Referrer:       "http://www.google.com",
+			UserAgent:      "Mozilla/5.0",
+			ClientIP:       "192.168.0.10",
+			AcceptLanguage: "pt-BR,pt;q=0.9",
+		}
+
+		err := repository.Create(event)
+		assert.NoError(t, err)
+
+		var stored entity.ClickEvent
+		err = db.First(&stored, "alias = ?", "abc123").Error
+		assert.NoError(t, err)
+		assert.True(t, event.ClickedAt.Equal(stored.ClickedAt), "The click date should be stored")
+		stored.ClickedAt = event.ClickedAt
+		assert.Equal(t, *event, stored, "The stored click event should match the input click event")
+	})
+}
+
+func TestClickEventRepositoryIntegration_Aggregations(t *testing.T) {
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(24 * time.Hour)
+
+	seed := func(t *testing.T, repository *ClickEventRepository) {
+		events := []*entity.ClickEvent{
+			{ShortenedURLID: 1, ClickedAt: from.Add(1 * time.Hour), Referrer: "http://www.google.com", UserAgent: "curl/8.0", ClientIP: "10.0.0.1"},
+			{ShortenedURLID: 1, ClickedAt: from.Add(2 * time.Hour), Referrer: "http://www.google.com", UserAgent: "curl/8.0", ClientIP: "10.0.0.1"},
+			{ShortenedURLID: 1, ClickedAt: from.Add(3 * time.Hour), Referrer: "", UserAgent: "Mozilla/5.0", ClientIP: "10.0.0.2"},
+			{ShortenedURLID: 1, ClickedAt: from.Add(-1 * time.Hour), Referrer: "http://www.bing.com", UserAgent: "Mozilla/5.0", ClientIP: "10.0.0.3"},
+			{ShortenedURLID: 1, ClickedAt: to, Referrer: "http://www.bing.com", UserAgent: "Mozilla/5.0", ClientIP: "10.0.0.4"},
+			{ShortenedURLID: 2, ClickedAt: from.Add(1 * time.Hour), Referrer: "http://www.bing.com", UserAgent: "Mozilla/5.0", ClientIP: "10.0.0.5"},
+		}
+		for _, event := range events {
+			assert.NoError(t, repository.Create(event))
+		}
+	}
+
+	t.Run("Given stored click events, when CountByBucket is called, then it should count the clicks of the shortened URL within the range per bucket", func(t *testing.T) {
+		repository := NewClickEventRepository(loadDB(t))
+		seed(t, repository)
+
+		hourly, err := repository.CountByBucket(1, from, to, from, time.Hour)
+		assert.NoError(t, err)
+		assert.Equal(t, map[int]int64{1: 1, 2: 1, 3: 1}, hourly, "Only the clicks of the shortened URL within the range should be counted")
+
+		daily, err := repository.CountByBucket(1, from, to, from.Add(-12*time.Hour), 24*time.Hour)
+		assert.NoError(t, err)
+		assert.Equal(t, map[int]int64{0: 3}, daily)
+	})
+
+	t.Run("Given stored click events, when CountUniqueVisitors is called, then it should count the distinct client IPs within the range", func(t *testing.T) {
+		repository := NewClickEventRepository(loadDB(t))
+		seed(t, repository)
+
+		count, err := repository.CountUniqueVisitors(1, from, to)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), count)
+	})
+
+	t.Run("Given stored click events, when CountByReferrer is called, then it should return the referrers ordered by clicks", func(t *testing.T) {
+		repository := NewClickEventRepository(loadDB(t))
+		seed(t, repository)
+
+		counts, err := repository.CountByReferrer(1, from, to, 10)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []entity.ClickCount{{Value: "http://www.google.com", Count: 2}, {Value: "", Count: 1}}, counts)
+	})
+
+	t.Run("Given stored click events, when CountByUserAgent is called, then it should return the user agents ordered by clicks", func(t *testing.T) {
+		repository := NewClickEventRepository(loadDB(t))
+		seed(t, repository)
+
+		counts, err := repository.CountByUserAgent(1, from, to)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []entity.ClickCount{{Value: "curl/8.0", Count: 2}, {Value: "Mozilla/5.0", Count: 1}}, counts)
+	})
+}
@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"github.com/lucasfarolfi/hire.me/internal/entity"
+	"gorm.io/gorm"
+)
+
+type DomainRepository struct {
+	DB *gorm.DB
+}
+
+func NewDomainRepository(db *gorm.DB) *DomainRepository {
+	return &DomainRepository{DB: db}
+}
+
+func (dr *DomainRepository) Create(domain *entity.Domain) error {
+	return dr.DB.Create(domain).Error
+}
+
+func (dr *DomainRepository) FindAll() ([]entity.Domain, error) {
+	var domains []entity.Domain
+	err := dr.DB.Order("id").Find(&domains).Error
+	if err != nil {
+		return nil, err
+	}
+	return domains, nil
+}
+
+// CreateCanonical creates the domain as the canonical domain of its tenant, unsetting the previous one in
+// the same transaction. A duplicate host returns gorm.ErrDuplicatedKey and leaves the previous one in place.
+func (dr *DomainRepository) CreateCanonical(domain *entity.Domain) error {
+	return dr.DB.Transaction(func(tx *gorm.DB) error {
+		var count int64
+		if err := tx.Model(&entity.Domain{}).Where("host = ?", domain.Host).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return gorm.ErrDuplicatedKey
+		}
+		err := tx.Model(&entity.Domain{}).Where("tenant_id = ? AND canonical = ?", domain.TenantID, true).
+			UpdateColumn("canonical", false).Error
+		if err != nil {
+			return err
+		}
+		domain.Canonical = true
+		return tx.Create(domain).Error
+	})
+}
+
+func (dr *DomainRepository) DeleteByHost(host string) error {
+	result := dr.DB.Where("host = ?", host).Delete(&entity.Domain{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lucasfarolfi/hire.me/internal/entity"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestDomainRepositoryIntegration(t *testing.T) {
+	t.Run("Given created domains, when a canonical domain of a tenant is created and a domain is deleted, then only the others should remain", func(t *testing.T) {
+		repository := NewDomainRepository(loadDB(t))
+		assert.NoError(t, repository.Create(&entity.Domain{Host: "go.acme.io", TenantID: 3, Scheme: "https", Canonical: true, CreatedAt: time.Now().UTC()}))
+		assert.NoError(t, repository.Create(&entity.Domain{Host: "go.other.io", TenantID: 4, Scheme: "https", Canonical: true, CreatedAt: time.Now().UTC()}))
+		assert.ErrorIs(t, repository.Create(&entity.Domain{Host: "go.acme.io", TenantID: 4}), gorm.ErrDuplicatedKey)
+
+		assert.NoError(t, repository.CreateCanonical(&entity.Domain{Host: "acme.link", TenantID: 3, Scheme: "https", CreatedAt: time.Now().UTC()}))
+		assert.NoError(t, repository.DeleteByHost("go.other.io"))
+
+		domains, err := repository.FindAll()
+		assert.NoError(t, err)
+		assert.Len(t, domains, 2)
+		assert.Equal(t, "go.acme.io", domains[0].Host)
+		assert.False(t, domains[0].Canonical)
+		assert.Equal(t, "acme.link", domains[1].Host)
+		assert.True(t, domains[1].Canonical)
+		assert.ErrorIs(t, repository.DeleteByHost("go.other.io"), gorm.ErrRecordNotFound)
+	})
+
+	t.Run("Given a canonical domain, when a duplicate host is created as canonical, then the previous one should remain canonical", func(t *testing.T) {
+		repository := NewDomainRepository(loadDB(t))
+		assert.NoError(t, repository.Create(&entity.Domain{Host: "go.acme.io", TenantID: 3, Scheme: "https", Canonical: true, CreatedAt: time.Now().UTC()}))
+		assert.NoError(t, repository.Create(&entity.Domain{Host: "acme.link", TenantID: 4, Scheme: "https", CreatedAt: time.Now().UTC()}))
+
+		err := repository.CreateCanonical(&entity.Domain{Host: "acme.link", TenantID: 3, Scheme: "https", CreatedAt: time.Now().UTC()})
+
+		assert.ErrorIs(t, err, gorm.ErrDuplicatedKey)
+		domains, err := repository.FindAll()
+		assert.NoError(t, err)
+		assert.Len(t, domains, 2)
+		assert.True(t, domains[0].Canonical)
+	})
+}
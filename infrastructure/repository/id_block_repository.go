@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/lucasfarolfi/hire.me/internal/entity"
+	"gorm.io/gorm"
+)
+
+const maxLeaseAttempts = 10
+
+type IDBlockRepository struct {
+	DB *gorm.DB
+}
+
+func NewIDBlockRepository(db *gorm.DB) *IDBlockRepository {
+	return &IDBlockRepository{DB: db}
+}
+
+// LeaseBlock reserves the IDs [start, start+size) of the sequence, which starts at 1. Concurrent leases
+// are resolved with a compare-and-swap on next_value, retried a bounded number of times.
+func (ir *IDBlockRepository) LeaseBlock(sequence string, size int64) (int64, error) {
+	for attempt := 0; attempt < maxLeaseAttempts; attempt++ {
+		var block entity.IDBlock
+		err := ir.DB.Where("name = ?", sequence).First(&block).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			err = ir.DB.Create(&entity.IDBlock{Name: sequence, NextValue: 1 + size}).Error
+			if errors.Is(err, gorm.ErrDuplicatedKey) {
+				continue
+			}
+			if err != nil {
+				return 0, err
+			}
+			return 1, nil
+		}
+		if err != nil {
+			return 0, err
+		}
+
+		result := ir.DB.Model(&entity.IDBlock{}).
+			Where("name = ? AND next_value = ?", sequence, block.NextValue).
+			UpdateColumn("next_value", block.NextValue+size)
+		if result.Error != nil {
+			return 0, result.Error
+		}
+		if result.RowsAffected == 1 {
+			return block.NextValue, nil
+		}
+	}
+	return 0, fmt.Errorf("could not lease a block of sequence %s after %d attempts", sequence, maxLeaseAttempts)
+}
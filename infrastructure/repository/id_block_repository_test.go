@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIDBlockRepositoryIntegration_LeaseBlock(t *testing.T) {
+	t.Run("Given a new sequence, when blocks are leased, then they should be contiguous and disjoint starting at 1", func(t *testing.T) {
+		repository := NewIDBlockRepository(loadDB(t))
+
+		first, err := repository.LeaseBlock("alias", 100)
+		assert.NoError(t, err)
+		second, err := repository.LeaseBlock("alias", 50)
+		assert.NoError(t, err)
+		third, err := repository.LeaseBlock("alias", 100)
+		assert.NoError(t, err)
+
+		assert.Equal(t, []int64{1, 101, 151}, []int64{first, second, third})
+	})
+
+	t.Run("Given two sequences, when blocks are leased, then each sequence should advance independently", func(t *testing.T) {
+		repository := NewIDBlockRepository(loadDB(t))
+
+		_, err := repository.LeaseBlock("alias", 100)
+		assert.NoError(t, err)
+		other, err := repository.LeaseBlock("other", 100)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), other)
+	})
+
+	t.Run("Given a lease made by another replica, when a block is leased, then it should start after the other lease", func(t *testing.T) {
+		db := loadDB(t)
+		replicaA := NewIDBlockRepository(db)
+		replicaB := NewIDBlockRepository(db)
+
+		startA, err := replicaA.LeaseBlock("alias", 1000)
+		assert.NoError(t, err)
+		startB, err := replicaB.LeaseBlock("alias", 1000)
+		assert.NoError(t, err)
+
+		assert.Equal(t, startA+1000, startB)
+	})
+}
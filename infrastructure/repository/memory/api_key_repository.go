@@ -0,0 +1,75 @@
+package memory
+
+import (
+	"sync"
+	"time"
+
+	"github.com/lucasfarolfi/hire.me/internal/entity"
+	"gorm.io/gorm"
+)
+
+// APIKeyRepository is a thread-safe in-memory implementation of service.APIKeyRepository.
+type APIKeyRepository struct {
+	mu     sync.RWMutex
+	keys   []*entity.APIKey
+	nextID int
+}
+
+func NewAPIKeyRepository() *APIKeyRepository {
+	return &APIKeyRepository{nextID: 1}
+}
+
+func (ar *APIKeyRepository) Create(key *entity.APIKey) error {
+	ar.mu.Lock()
+	defer ar.mu.Unlock()
+
+	for _, stored := range ar.keys {
+		if stored.KeyHash == key.KeyHash {
+			return gorm.ErrDuplicatedKey
+		}
+	}
+	key.ID = ar.nextID
+	ar.nextID++
+	stored := *key
+	ar.keys = append(ar.keys, &stored)
+	return nil
+}
+
+func (ar *APIKeyRepository) FindByHash(keyHash string) (*entity.APIKey, error) {
+	ar.mu.RLock()
+	defer ar.mu.RUnlock()
+
+	for _, stored := range ar.keys {
+		if stored.KeyHash == keyHash {
+			key := *stored
+			return &key, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (ar *APIKeyRepository) FindAll() ([]entity.APIKey, error) {
+	ar.mu.RLock()
+	defer ar.mu.RUnlock()
+
+	keys := make([]entity.APIKey, 0, len(ar.keys))
+	for _, stored := range ar.keys {
+		keys = append(keys, *stored)
+	}
+	return keys, nil
+}
+
+func (ar *APIKeyRepository) Revoke(id int, revokedAt time.Time) error {
+	ar.mu.Lock()
+	defer ar.mu.Unlock()
+
+	for _, stored := range ar.keys {
+		if stored.ID == id {
+			if stored.RevokedAt == nil {
+				stored.RevokedAt = &revokedAt
+			}
+			return nil
+		}
+	}
+	return gorm.ErrRecordNotFound
+}
@@ -0,0 +1,126 @@
+package memory
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/lucasfarolfi/hire.me/internal/entity"
+)
+
+// ClickEventRepository is a thread-safe in-memory implementation of service.ClickEventRepository.
+type ClickEventRepository struct {
+	mu     sync.RWMutex
+	events []entity.ClickEvent
+	nextID int
+}
+
+func NewClickEventRepository() *ClickEventRepository {
+	return &ClickEventRepository{nextID: 1}
+}
+
+func (cr *ClickEventRepository) Create(event *entity.ClickEvent) error {
+	cr.mu.Lock()
+	defer cr.mu.Unlock()
+
+	event.ID = cr.nextID
+	cr.nextID++
+	cr.events = append(cr.events, *event)
+	return nil
+}
+
+func (cr *ClickEventRepository) CountByBucket(shortenedURLID int, from, to, bucketStart time.Time, step time.Duration) (map[int]int64, error) {
+	counts := make(map[int]int64)
+	cr.forEachInRange(shortenedURLID, from, to, func(event entity.ClickEvent) {
+		counts[int(event.ClickedAt.Sub(bucketStart)/step)]++
+	})
+	return counts, nil
+}
+
+func (cr *ClickEventRepository) CountUniqueVisitors(shortenedURLID int, from, to time.Time) (int64, error) {
+	visitors := make(map[string]struct{})
+	cr.forEachInRange(shortenedURLID, from, to, func(event entity.ClickEvent) {
+		visitors[event.ClientIP] = struct{}{}
+	})
+	return int64(len(visitors)), nil
+}
+
+func (cr *ClickEventRepository) CountByReferrer(shortenedURLID int, from, to time.Time, limit int) ([]entity.ClickCount, error) {
+	return cr.countBy(shortenedURLID, from, to, limit, func(event entity.ClickEvent) string {
+		return event.Referrer
+	}), nil
+}
+
+func (cr *ClickEventRepository) CountByUserAgent(shortenedURLID int, from, to time.Time) ([]entity.ClickCount, error) {
+	return cr.countBy(shortenedURLID, from, to, -1, func(event entity.ClickEvent) string {
+		return event.UserAgent
+	}), nil
+}
+
+// deleteByShortenedURL removes the click events of the given shortened URLs.
+func (cr *ClickEventRepository) deleteByShortenedURL(ids map[int]bool) {
+	cr.mu.Lock()
+	defer cr.mu.Unlock()
+
+	kept := cr.events[:0]
+	for _, event := range cr.events {
+		if !ids[event.ShortenedURLID] {
+			kept = append(kept, event)
+		}
+	}
+	cr.events = kept
+}
+
+// countByShortenedURL counts the click events of every shortened URL clicked between since and until,
+// any of which can be nil for an open window.
+func (cr *ClickEventRepository) countByShortenedURL(since, until *time.Time) map[int]int32 {
+	cr.mu.RLock()
+	defer cr.mu.RUnlock()
+
+	counts := make(map[int]int32)
+	for _, event := range cr.events {
+		if since != nil && event.ClickedAt.Before(*since) {
+			continue
+		}
+		if until != nil && !event.ClickedAt.Before(*until) {
+			continue
+		}
+		counts[event.ShortenedURLID]++
+	}
+	return counts
+}
+
+func (cr *ClickEventRepository) countBy(shortenedURLID int, from, to time.Time, limit int, value func(entity.ClickEvent) string) []entity.ClickCount {
+	positions := make(map[string]int)
+	counts := []entity.ClickCount{}
+	cr.forEachInRange(shortenedURLID, from, to, func(event entity.ClickEvent) {
+		v := value(event)
+		if i, ok := positions[v]; ok {
+			counts[i].Count++
+			return
+		}
+		positions[v] = len(counts)
+		counts = append(counts, entity.ClickCount{Value: v, Count: 1})
+	})
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Count != counts[j].Count {
+			return counts[i].Count > counts[j].Count
+		}
+		return counts[i].Value < counts[j].Value
+	})
+	if limit >= 0 && len(counts) > limit {
+		counts = counts[:limit]
+	}
+	return counts
+}
+
+func (cr *ClickEventRepository) forEachInRange(shortenedURLID int, from, to time.Time, fn func(event entity.ClickEvent)) {
+	cr.mu.RLock()
+	defer cr.mu.RUnlock()
+
+	for _, event := range cr.events {
+		if event.ShortenedURLID == shortenedURLID && !event.ClickedAt.Before(from) && event.ClickedAt.Before(to) {
+			fn(event)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package memory
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lucasfarolfi/hire.me/internal/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryClickEventRepository(t *testing.T) {
+	from := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(24 * time.Hour)
+
+	seed := func(t *testing.T) *ClickEventRepository {
+		repository := NewClickEventRepository()
+		events := []entity.ClickEvent{
+			{ShortenedURLID: 1, ClickedAt: from.Add(2 * time.Hour), Referrer: "https://google.com", UserAgent: "curl/8.0", ClientIP: "10.0.0.1"},
+			{ShortenedURLID: 1, ClickedAt: from.Add(time.Hour), Referrer: "https://google.com", UserAgent: "curl/8.0", ClientIP: "10.0.0.2"},
+			{ShortenedURLID: 1, ClickedAt: from.Add(3 * time.Hour), Referrer: "https://bing.com", UserAgent: "Firefox", ClientIP: "10.0.0.1"},
+			{ShortenedURLID: 1, ClickedAt: to, Referrer: "https://bing.com", UserAgent: "Firefox", ClientIP: "10.0.0.3"},
+			{ShortenedURLID: 2, ClickedAt: from.Add(time.Hour), Referrer: "https://bing.com", UserAgent: "Firefox", ClientIP: "10.0.0.4"},
+		}
+		for i := range events {
+			assert.NoError(t, repository.Create(&events[i]))
+		}
+		return repository
+	}
+
+	t.Run("Given stored clicks, when the CountByBucket method is called, then it should count the link clicks in the range per bucket", func(t *testing.T) {
+		repository := seed(t)
+
+		counts, err := repository.CountByBucket(1, from, to, from, 2*time.Hour)
+
+		assert.NoError(t, err)
+		assert.Equal(t, map[int]int64{0: 1, 1: 2}, counts)
+	})
+
+	t.Run("Given stored clicks, when the CountUniqueVisitors method is called, then it should count distinct client IPs in the range", func(t *testing.T) {
+		repository := seed(t)
+
+		visitors, err := repository.CountUniqueVisitors(1, from, to)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), visitors)
+	})
+
+	t.Run("Given stored clicks, when the CountByReferrer method is called, then it should return the most frequent referrers up to the limit", func(t *testing.T) {
+		repository := seed(t)
+
+		counts, err := repository.CountByReferrer(1, from, to, 1)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []entity.ClickCount{{Value: "https://google.com", Count: 2}}, counts)
+	})
+
+	t.Run("Given stored clicks, when the CountByUserAgent method is called, then it should count every user agent in the range", func(t *testing.T) {
+		repository := seed(t)
+
+		counts, err := repository.CountByUserAgent(1, from, to)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []entity.ClickCount{{Value: "curl/8.0", Count: 2}, {Value: "Firefox", Count: 1}}, counts)
+	})
+}
@@ -0,0 +1,88 @@
+package memory
+
+import (
+	"sync"
+
+	"github.com/lucasfarolfi/hire.me/internal/entity"
+	"gorm.io/gorm"
+)
+
+// DomainRepository is a thread-safe in-memory implementation of service.DomainRepository.
+type DomainRepository struct {
+	mu      sync.RWMutex
+	domains []*entity.Domain
+	nextID  int
+}
+
+func NewDomainRepository() *DomainRepository {
+	return &DomainRepository{nextID: 1}
+}
+
+func (dr *DomainRepository) Create(domain *entity.Domain) error {
+	dr.mu.Lock()
+	defer dr.mu.Unlock()
+
+	return dr.create(domain)
+}
+
+// CreateCanonical creates the domain as the canonical domain of its tenant, unsetting the previous one
+// under the same lock. A duplicate host leaves the previous one in place.
+func (dr *DomainRepository) CreateCanonical(domain *entity.Domain) error {
+	dr.mu.Lock()
+	defer dr.mu.Unlock()
+
+	if dr.exists(domain.Host) {
+		return gorm.ErrDuplicatedKey
+	}
+	for _, stored := range dr.domains {
+		if stored.TenantID == domain.TenantID {
+			stored.Canonical = false
+		}
+	}
+	domain.Canonical = true
+	return dr.create(domain)
+}
+
+func (dr *DomainRepository) create(domain *entity.Domain) error {
+	if dr.exists(domain.Host) {
+		return gorm.ErrDuplicatedKey
+	}
+	domain.ID = dr.nextID
+	dr.nextID++
+	stored := *domain
+	dr.domains = append(dr.domains, &stored)
+	return nil
+}
+
+func (dr *DomainRepository) FindAll() ([]entity.Domain, error) {
+	dr.mu.RLock()
+	defer dr.mu.RUnlock()
+
+	domains := make([]entity.Domain, 0, len(dr.domains))
+	for _, stored := range dr.domains {
+		domains = append(domains, *stored)
+	}
+	return domains, nil
+}
+
+func (dr *DomainRepository) exists(host string) bool {
+	for _, stored := range dr.domains {
+		if stored.Host == host {
+			return true
+		}
+	}
+	return false
+}
+
+func (dr *DomainRepository) DeleteByHost(host string) error {
+	dr.mu.Lock()
+	defer dr.mu.Unlock()
+
+	for i, stored := range dr.domains {
+		if stored.Host == host {
+			dr.domains = append(dr.domains[:i], dr.domains[i+1:]...)
+			return nil
+		}
+	}
+	return gorm.ErrRecordNotFound
+}
@@ -0,0 +1,25 @@
+package memory
+
+import "sync"
+
+// IDBlockRepository is a thread-safe in-memory implementation of service.IDBlockRepository.
+type IDBlockRepository struct {
+	mu         sync.Mutex
+	nextValues map[string]int64
+}
+
+func NewIDBlockRepository() *IDBlockRepository {
+	return &IDBlockRepository{nextValues: make(map[string]int64)}
+}
+
+func (ir *IDBlockRepository) LeaseBlock(sequence string, size int64) (int64, error) {
+	ir.mu.Lock()
+	defer ir.mu.Unlock()
+
+	start, ok := ir.nextValues[sequence]
+	if !ok {
+		start = 1
+	}
+	ir.nextValues[sequence] = start + size
+	return start, nil
+}
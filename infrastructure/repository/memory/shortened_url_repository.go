@@ -0,0 +1,287 @@
+package memory
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/lucasfarolfi/hire.me/internal/entity"
+	"gorm.io/gorm"
+)
+
+// ShortenedURLRepository is a thread-safe in-memory implementation of service.ShortenedURLRepository
+// with the same semantics as the database one: aliases unique per tenant, atomic increments and ordered rankings.
+// Rankings within a time window are computed from the given click event repository.
+type ShortenedURLRepository struct {
+	mu          sync.RWMutex
+	byID        map[int]*entity.ShortenedURL
+	idsByAlias  map[tenantAlias]int
+	nextID      int
+	clickEvents *ClickEventRepository
+}
+
+// tenantAlias is the unique key of a shortened URL: aliases are unique within a tenant.
+type tenantAlias struct {
+	tenantID int
+	alias    string
+}
+
+func aliasKeyOf(shortUrl *entity.ShortenedURL) tenantAlias {
+	return tenantAlias{tenantID: shortUrl.TenantID, alias: shortUrl.Alias}
+}
+
+func NewShortenedURLRepository(clickEvents *ClickEventRepository) *ShortenedURLRepository {
+	return &ShortenedURLRepository{
+		byID:        make(map[int]*entity.ShortenedURL),
+		idsByAlias:  make(map[tenantAlias]int),
+		nextID:      1,
+		clickEvents: clickEvents,
+	}
+}
+
+func (ur *ShortenedURLRepository) Create(shortUrl *entity.ShortenedURL) error {
+	ur.mu.Lock()
+	defer ur.mu.Unlock()
+
+	if _, exists := ur.idsByAlias[aliasKeyOf(shortUrl)]; exists {
+		return gorm.ErrDuplicatedKey
+	}
+	if shortUrl.ID == 0 {
+		shortUrl.ID = ur.nextID
+	} else if _, exists := ur.byID[shortUrl.ID]; exists {
+		return gorm.ErrDuplicatedKey
+	}
+	if shortUrl.ID >= ur.nextID {
+		ur.nextID = shortUrl.ID + 1
+	}
+	if shortUrl.CreatedAt.IsZero() {
+		shortUrl.CreatedAt = time.Now().UTC()
+	}
+
+	stored := *shortUrl
+	ur.byID[stored.ID] = &stored
+	ur.idsByAlias[aliasKeyOf(&stored)] = stored.ID
+	return nil
+}
+
+func (ur *ShortenedURLRepository) FindByAlias(tenantID int, alias string) (*entity.ShortenedURL, error) {
+	ur.mu.RLock()
+	defer ur.mu.RUnlock()
+
+	id, ok := ur.idsByAlias[tenantAlias{tenantID: tenantID, alias: alias}]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	shortUrl := *ur.byID[id]
+	return &shortUrl, nil
+}
+
+func (ur *ShortenedURLRepository) FindReusableByURLHash(tenantID int, urlHash string, redirectType int, ownerID string) (*entity.ShortenedURL, error) {
+	ur.mu.RLock()
+	defer ur.mu.RUnlock()
+
+	var found *entity.ShortenedURL
+	for _, shortUrl := range ur.byID {
+		if shortUrl.TenantID != tenantID || shortUrl.URLHash != urlHash || shortUrl.CustomAlias ||
+			shortUrl.RedirectType != redirectType || shortUrl.OwnerID != ownerID ||
+			shortUrl.ExpiresAt != nil || shortUrl.MaxAccessTimes != nil || shortUrl.IsDeleted() {
+			continue
+		}
+		if found == nil || shortUrl.ID < found.ID {
+			found = shortUrl
+		}
+	}
+	if found == nil {
+		return nil, gorm.ErrRecordNotFound
+	}
+	shortUrl := *found
+	return &shortUrl, nil
+}
+
+func (ur *ShortenedURLRepository) ExistsByAlias(tenantID int, alias string) bool {
+	ur.mu.RLock()
+	defer ur.mu.RUnlock()
+
+	_, ok := ur.idsByAlias[tenantAlias{tenantID: tenantID, alias: alias}]
+	return ok
+}
+
+func (ur *ShortenedURLRepository) Update(shortUrl *entity.ShortenedURL) error {
+	ur.mu.Lock()
+	defer ur.mu.Unlock()
+
+	stored, ok := ur.byID[shortUrl.ID]
+	if !ok {
+		return gorm.ErrRecordNotFound
+	}
+	stored.Url = shortUrl.Url
+	stored.URLHash = shortUrl.URLHash
+	stored.URLHost = shortUrl.URLHost
+	stored.ExpiresAt = shortUrl.ExpiresAt
+	stored.RedirectType = shortUrl.RedirectType
+	return nil
+}
+
+func (ur *ShortenedURLRepository) Replace(shortUrl *entity.ShortenedURL) error {
+	ur.mu.Lock()
+	defer ur.mu.Unlock()
+
+	stored, ok := ur.byID[shortUrl.ID]
+	if !ok {
+		return gorm.ErrRecordNotFound
+	}
+	if aliasKeyOf(stored) != aliasKeyOf(shortUrl) {
+		if _, exists := ur.idsByAlias[aliasKeyOf(shortUrl)]; exists {
+			return gorm.ErrDuplicatedKey
+		}
+		delete(ur.idsByAlias, aliasKeyOf(stored))
+		ur.idsByAlias[aliasKeyOf(shortUrl)] = shortUrl.ID
+	}
+	*stored = *shortUrl
+	return nil
+}
+
+func (ur *ShortenedURLRepository) SoftDelete(shortUrl *entity.ShortenedURL) error {
+	ur.mu.Lock()
+	defer ur.mu.Unlock()
+
+	stored, ok := ur.byID[shortUrl.ID]
+	if !ok {
+		return gorm.ErrRecordNotFound
+	}
+	stored.DeletedAt = shortUrl.DeletedAt
+	return nil
+}
+
+func (ur *ShortenedURLRepository) UpdateFlag(shortUrl *entity.ShortenedURL) error {
+	ur.mu.Lock()
+	defer ur.mu.Unlock()
+
+	stored, ok := ur.byID[shortUrl.ID]
+	if !ok {
+		return gorm.ErrRecordNotFound
+	}
+	stored.FlaggedAt = shortUrl.FlaggedAt
+	stored.FlagReason = shortUrl.FlagReason
+	return nil
+}
+
+func (ur *ShortenedURLRepository) IncrementAccessTimesByID(id int) (bool, error) {
+	ur.mu.Lock()
+	defer ur.mu.Unlock()
+
+	shortUrl, ok := ur.byID[id]
+	if !ok {
+		return false, nil
+	}
+	if shortUrl.MaxAccessTimes != nil && shortUrl.AccessTimes >= *shortUrl.MaxAccessTimes {
+		return false, nil
+	}
+	shortUrl.AccessTimes++
+	return true, nil
+}
+
+func (ur *ShortenedURLRepository) AddAccessTimes(increments map[int]int32) error {
+	ur.mu.Lock()
+	defer ur.mu.Unlock()
+
+	for id, increment := range increments {
+		if shortUrl, ok := ur.byID[id]; ok {
+			shortUrl.AccessTimes += increment
+		}
+	}
+	return nil
+}
+
+func (ur *ShortenedURLRepository) CountLinks(tenantID int) (int64, error) {
+	ur.mu.RLock()
+	defer ur.mu.RUnlock()
+
+	var count int64
+	for _, shortUrl := range ur.byID {
+		if shortUrl.TenantID == tenantID && !shortUrl.IsDeleted() {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (ur *ShortenedURLRepository) FindLinks(query entity.LinkQuery) ([]entity.ShortenedURL, error) {
+	ur.mu.RLock()
+	links := make([]entity.ShortenedURL, 0)
+	for _, shortUrl := range ur.byID {
+		if !query.Matches(shortUrl) || (query.After != nil && !query.IsAfter(shortUrl, query.After)) {
+			continue
+		}
+		links = append(links, *shortUrl)
+	}
+	ur.mu.RUnlock()
+
+	sort.Slice(links, func(i, j int) bool {
+		return query.IsAfter(&links[j], query.CursorOf(&links[i]))
+	})
+	if len(links) > query.Limit {
+		links = links[:query.Limit]
+	}
+	return links, nil
+}
+
+func (ur *ShortenedURLRepository) FindMostAcessedUrls(tenantID, limit, offset int, since, until *time.Time) ([]entity.ShortenedURL, error) {
+	var windowAccessTimes map[int]int32
+	if since != nil || until != nil {
+		windowAccessTimes = make(map[int]int32)
+		if ur.clickEvents != nil {
+			windowAccessTimes = ur.clickEvents.countByShortenedURL(since, until)
+		}
+	}
+
+	ur.mu.RLock()
+	ranking := make([]entity.ShortenedURL, 0, len(ur.byID))
+	for _, shortUrl := range ur.byID {
+		if shortUrl.TenantID != tenantID || shortUrl.IsDeleted() {
+			continue
+		}
+		ranked := *shortUrl
+		if windowAccessTimes != nil {
+			if windowAccessTimes[ranked.ID] == 0 {
+				continue
+			}
+			ranked.AccessTimes = windowAccessTimes[ranked.ID]
+		}
+		ranking = append(ranking, ranked)
+	}
+	ur.mu.RUnlock()
+
+	sort.Slice(ranking, func(i, j int) bool {
+		if ranking[i].AccessTimes != ranking[j].AccessTimes {
+			return ranking[i].AccessTimes > ranking[j].AccessTimes
+		}
+		return ranking[i].ID < ranking[j].ID
+	})
+	if offset >= len(ranking) {
+		return []entity.ShortenedURL{}, nil
+	}
+	ranking = ranking[offset:]
+	if len(ranking) > limit {
+		ranking = ranking[:limit]
+	}
+	return ranking, nil
+}
+
+func (ur *ShortenedURLRepository) DeleteExpiredBefore(before time.Time) (int64, error) {
+	ur.mu.Lock()
+	defer ur.mu.Unlock()
+
+	expired := make(map[int]bool)
+	for id, shortUrl := range ur.byID {
+		if shortUrl.ExpiresAt != nil && shortUrl.ExpiresAt.Before(before) && !shortUrl.IsDeleted() {
+			delete(ur.byID, id)
+			delete(ur.idsByAlias, aliasKeyOf(shortUrl))
+			expired[id] = true
+		}
+	}
+	if ur.clickEvents != nil && len(expired) > 0 {
+		ur.clickEvents.deleteByShortenedURL(expired)
+	}
+	return int64(len(expired)), nil
+}
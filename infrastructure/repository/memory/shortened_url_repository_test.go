@@ -0,0 +1,291 @@
+package memory
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/lucasfarolfi/hire.me/internal/entity"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestMemoryShortenedURLRepository_Create(t *testing.T) {
+	t.Run("Given a valid shortened url, when the create method is called, then it should store it with an ID and creation date", func(t *testing.T) {
+		repository := NewShortenedURLRepository(NewClickEventRepository())
+
+		shortUrl := entity.NewShortenedURL("abc123", "http://www.bemobi.com.br")
+		err := repository.Create(shortUrl)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, shortUrl.ID)
+		assert.False(t, shortUrl.CreatedAt.IsZero())
+		stored, err := repository.FindByAlias(0, "abc123")
+		assert.NoError(t, err)
+		assert.Equal(t, shortUrl, stored)
+	})
+
+	t.Run("Given a duplicate alias, when the Create method is called, then it should return a duplicated key error", func(t *testing.T) {
+		repository := NewShortenedURLRepository(NewClickEventRepository())
+		assert.NoError(t, repository.Create(entity.NewShortenedURL("abc123", "http://www.bemobi.com.br")))
+
+		err := repository.Create(entity.NewShortenedURL("abc123", "http://www.google.com"))
+
+		assert.ErrorIs(t, err, gorm.ErrDuplicatedKey)
+	})
+
+	t.Run("Given a stored shortened url, when the returned record is modified, then the stored one should not change", func(t *testing.T) {
+		repository := NewShortenedURLRepository(NewClickEventRepository())
+		assert.NoError(t, repository.Create(entity.NewShortenedURL("abc123", "http://www.bemobi.com.br")))
+
+		found, _ := repository.FindByAlias(0, "abc123")
+		found.Url = "http://www.google.com"
+
+		stored, _ := repository.FindByAlias(0, "abc123")
+		assert.Equal(t, "http://www.bemobi.com.br", stored.Url)
+	})
+}
+
+func TestMemoryShortenedURLRepository_FindByAlias(t *testing.T) {
+	t.Run("Given an unknown alias, when the FindByAlias method is called, then it should return a record not found error", func(t *testing.T) {
+		repository := NewShortenedURLRepository(NewClickEventRepository())
+
+		shortUrl, err := repository.FindByAlias(0, "unknown")
+
+		assert.Nil(t, shortUrl)
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+		assert.False(t, repository.ExistsByAlias(0, "unknown"))
+	})
+}
+
+func TestMemoryShortenedURLRepository_FindReusableByURLHash(t *testing.T) {
+	t.Run("Given shortened urls of the same destination, when FindReusableByURLHash is called, then it should skip custom and limited ones", func(t *testing.T) {
+		repository := NewShortenedURLRepository(NewClickEventRepository())
+		destination := "http://www.bemobi.com.br"
+		max := int32(3)
+		custom := entity.NewShortenedURL("custom", destination)
+		custom.CustomAlias = true
+		limited := entity.NewShortenedURL("limited", destination)
+		limited.MaxAccessTimes = &max
+		for _, shortUrl := range []*entity.ShortenedURL{custom, limited, entity.NewShortenedURL("plain", destination), entity.NewShortenedURL("plain-again", destination)} {
+			assert.NoError(t, repository.Create(shortUrl))
+		}
+
+		found, err := repository.FindReusableByURLHash(0, entity.HashURL(destination), 0, "")
+		assert.NoError(t, err)
+		assert.Equal(t, "plain", found.Alias)
+
+		found, err = repository.FindReusableByURLHash(0, entity.HashURL(destination), 301, "")
+		assert.Nil(t, found)
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	})
+}
+
+func TestMemoryShortenedURLRepository_IncrementAccessTimesByID(t *testing.T) {
+	t.Run("Given a shortened url with an access limit, when it is incremented concurrently, then it should never exceed the limit", func(t *testing.T) {
+		repository := NewShortenedURLRepository(NewClickEventRepository())
+		max := int32(10)
+		shortUrl := entity.NewShortenedURL("abc123", "http://www.bemobi.com.br")
+		shortUrl.MaxAccessTimes = &max
+		assert.NoError(t, repository.Create(shortUrl))
+
+		var wg sync.WaitGroup
+		var mu sync.Mutex
+		granted := 0
+		for i := 0; i < 50; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				ok, err := repository.IncrementAccessTimesByID(shortUrl.ID)
+				assert.NoError(t, err)
+				if ok {
+					mu.Lock()
+					granted++
+					mu.Unlock()
+				}
+			}()
+		}
+		wg.Wait()
+
+		stored, _ := repository.FindByAlias(0, "abc123")
+		assert.Equal(t, 10, granted)
+		assert.Equal(t, int32(10), stored.AccessTimes)
+	})
+
+	t.Run("Given an unknown id, when the IncrementAccessTimesByID method is called, then it should report no increment", func(t *testing.T) {
+		repository := NewShortenedURLRepository(NewClickEventRepository())
+
+		ok, err := repository.IncrementAccessTimesByID(42)
+
+		assert.NoError(t, err)
+		assert.False(t, ok)
+	})
+}
+
+func TestMemoryShortenedURLRepository_AddAccessTimes(t *testing.T) {
+	t.Run("Given batched increments, when the AddAccessTimes method is called, then it should add them to the stored counters", func(t *testing.T) {
+		repository := NewShortenedURLRepository(NewClickEventRepository())
+		shortUrl := entity.NewShortenedURL("abc123", "http://www.bemobi.com.br")
+		assert.NoError(t, repository.Create(shortUrl))
+
+		err := repository.AddAccessTimes(map[int]int32{shortUrl.ID: 5, 99: 3})
+
+		assert.NoError(t, err)
+		stored, _ := repository.FindByAlias(0, "abc123")
+		assert.Equal(t, int32(5), stored.AccessTimes)
+	})
+}
+
+func TestMemoryShortenedURLRepository_FindMostAcessedUrls(t *testing.T) {
+	t.Run("Given several shortened urls, when the FindMostAcessedUrls method is called, then it should rank them by access times and id", func(t *testing.T) {
+		repository := NewShortenedURLRepository(NewClickEventRepository())
+		for i, accessTimes := range []int32{3, 7, 3, 1} {
+			shortUrl := entity.NewShortenedURL(string(rune('a'+i)), "http://www.bemobi.com.br")
+			shortUrl.AccessTimes = accessTimes
+			assert.NoError(t, repository.Create(shortUrl))
+		}
+
+		ranking, err := repository.FindMostAcessedUrls(0, 2, 1, nil, nil)
+
+		assert.NoError(t, err)
+		assert.Len(t, ranking, 2)
+		assert.Equal(t, "a", ranking[0].Alias)
+		assert.Equal(t, "c", ranking[1].Alias)
+	})
+
+	t.Run("Given clicks inside and outside a window, when the FindMostAcessedUrls method is called with since and until, then it should rank by clicks in the window", func(t *testing.T) {
+		clickEvents := NewClickEventRepository()
+		repository := NewShortenedURLRepository(clickEvents)
+		popular := entity.NewShortenedURL("popular", "http://www.bemobi.com.br")
+		popular.AccessTimes = 100
+		recent := entity.NewShortenedURL("recent", "http://www.google.com")
+		assert.NoError(t, repository.Create(popular))
+		assert.NoError(t, repository.Create(recent))
+
+		since := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+		until := since.Add(24 * time.Hour)
+		for _, click := range []struct {
+			id int
+			at time.Time
+		}{
+			{popular.ID, since.Add(-time.Hour)},
+			{popular.ID, since.Add(time.Hour)},
+			{recent.ID, since.Add(2 * time.Hour)},
+			{recent.ID, since.Add(3 * time.Hour)},
+		} {
+			assert.NoError(t, clickEvents.Create(&entity.ClickEvent{ShortenedURLID: click.id, ClickedAt: click.at}))
+		}
+
+		ranking, err := repository.FindMostAcessedUrls(0, 10, 0, &since, &until)
+
+		assert.NoError(t, err)
+		assert.Len(t, ranking, 2)
+		assert.Equal(t, "recent", ranking[0].Alias)
+		assert.Equal(t, int32(2), ranking[0].AccessTimes)
+		assert.Equal(t, "popular", ranking[1].Alias)
+		assert.Equal(t, int32(1), ranking[1].AccessTimes)
+	})
+}
+
+func TestMemoryShortenedURLRepository_DeleteExpiredBefore(t *testing.T) {
+	t.Run("Given expired and active shortened urls, when the DeleteExpiredBefore method is called, then it should only delete the expired ones", func(t *testing.T) {
+		repository := NewShortenedURLRepository(NewClickEventRepository())
+		now := time.Now().UTC()
+		expiredAt := now.Add(-time.Hour)
+		expiresAt := now.Add(time.Hour)
+		expired := entity.NewShortenedURL("expired", "http://www.bemobi.com.br")
+		expired.ExpiresAt = &expiredAt
+		active := entity.NewShortenedURL("active", "http://www.bemobi.com.br")
+		active.ExpiresAt = &expiresAt
+		assert.NoError(t, repository.Create(expired))
+		assert.NoError(t, repository.Create(active))
+		assert.NoError(t, repository.Create(entity.NewShortenedURL("forever", "http://www.bemobi.com.br")))
+
+		deleted, err := repository.DeleteExpiredBefore(now)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), deleted)
+		assert.False(t, repository.ExistsByAlias(0, "expired"))
+		assert.True(t, repository.ExistsByAlias(0, "active"))
+		assert.True(t, repository.ExistsByAlias(0, "forever"))
+	})
+
+	t.Run("Given clicks on an expired shortened url, when the DeleteExpiredBefore method is called, then it should stop ranking them in windows", func(t *testing.T) {
+		clickEvents := NewClickEventRepository()
+		repository := NewShortenedURLRepository(clickEvents)
+		now := time.Now().UTC()
+		expiredAt := now.Add(-time.Hour)
+		expired := entity.NewShortenedURL("expired", "http://www.bemobi.com.br")
+		expired.ExpiresAt = &expiredAt
+		active := entity.NewShortenedURL("active", "http://www.google.com")
+		assert.NoError(t, repository.Create(expired))
+		assert.NoError(t, repository.Create(active))
+		for _, id := range []int{expired.ID, expired.ID, active.ID} {
+			assert.NoError(t, clickEvents.Create(&entity.ClickEvent{ShortenedURLID: id, ClickedAt: now}))
+		}
+
+		_, err := repository.DeleteExpiredBefore(now)
+		assert.NoError(t, err)
+
+		since := now.Add(-time.Minute)
+		assert.Equal(t, map[int]int32{active.ID: 1}, clickEvents.countByShortenedURL(&since, nil))
+	})
+}
+
+func TestMemoryShortenedURLRepository_FindLinks(t *testing.T) {
+	t.Run("Given filters, a sort and a cursor, when FindLinks is called, then it should page through the matching links in order", func(t *testing.T) {
+		repository := NewShortenedURLRepository(NewClickEventRepository())
+		for _, link := range []struct {
+			alias       string
+			url         string
+			accessTimes int32
+		}{
+			{"promo-a", "http://www.bemobi.com.br/a", 5},
+			{"promo-b", "http://bemobi.com.br/b", 5},
+			{"promo-c", "http://shop.example.com/c", 9},
+			{"promo-d", "http://notbemobi.com.br/d", 1},
+			{"other", "http://www.bemobi.com.br/e", 7},
+		} {
+			shortUrl := entity.NewShortenedURL(link.alias, link.url)
+			shortUrl.AccessTimes = link.accessTimes
+			assert.NoError(t, repository.Create(shortUrl))
+		}
+
+		query := entity.LinkQuery{AliasPrefix: "promo-", SortBy: entity.LinkSortAccessTimes, Descending: true, Limit: 2}
+		links, err := repository.FindLinks(query)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"promo-c", "promo-b"}, []string{links[0].Alias, links[1].Alias})
+
+		query.After = query.CursorOf(&links[1])
+		links, err = repository.FindLinks(query)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"promo-a", "promo-d"}, []string{links[0].Alias, links[1].Alias})
+
+		links, err = repository.FindLinks(entity.LinkQuery{Domain: "bemobi.com.br", Limit: 10})
+		assert.NoError(t, err)
+		assert.Len(t, links, 3)
+	})
+}
+
+func TestMemoryShortenedURLRepository_Tenants(t *testing.T) {
+	t.Run("Given the same alias in two tenants, when they are queried, then each tenant should only see its own shortened urls", func(t *testing.T) {
+		repository := NewShortenedURLRepository(NewClickEventRepository())
+		other := entity.NewShortenedURL("abc123", "http://www.google.com")
+		other.TenantID = 7
+		other.AccessTimes = 5
+		assert.NoError(t, repository.Create(entity.NewShortenedURL("abc123", "http://www.bemobi.com.br")))
+		assert.NoError(t, repository.Create(other))
+
+		found, err := repository.FindByAlias(7, "abc123")
+		assert.NoError(t, err)
+		assert.Equal(t, "http://www.google.com", found.Url)
+		assert.False(t, repository.ExistsByAlias(8, "abc123"))
+		count, err := repository.CountLinks(7)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), count)
+		ranking, err := repository.FindMostAcessedUrls(0, 10, 0, nil, nil)
+		assert.NoError(t, err)
+		assert.Len(t, ranking, 1)
+		assert.Equal(t, "http://www.bemobi.com.br", ranking[0].Url)
+	})
+}
@@ -0,0 +1,80 @@
+package memory
+
+import (
+	"sync"
+
+	"github.com/lucasfarolfi/hire.me/internal/entity"
+	"gorm.io/gorm"
+)
+
+// TenantRepository is a thread-safe in-memory implementation of service.TenantRepository.
+type TenantRepository struct {
+	mu      sync.RWMutex
+	tenants []*entity.Tenant
+	nextID  int
+}
+
+func NewTenantRepository() *TenantRepository {
+	return &TenantRepository{nextID: 1}
+}
+
+func (tr *TenantRepository) Create(tenant *entity.Tenant) error {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+
+	for _, stored := range tr.tenants {
+		if stored.Slug == tenant.Slug {
+			return gorm.ErrDuplicatedKey
+		}
+	}
+	tenant.ID = tr.nextID
+	tr.nextID++
+	stored := *tenant
+	tr.tenants = append(tr.tenants, &stored)
+	return nil
+}
+
+func (tr *TenantRepository) FindByID(id int) (*entity.Tenant, error) {
+	return tr.find(func(tenant *entity.Tenant) bool { return tenant.ID == id })
+}
+
+func (tr *TenantRepository) FindBySlug(slug string) (*entity.Tenant, error) {
+	return tr.find(func(tenant *entity.Tenant) bool { return tenant.Slug == slug })
+}
+
+func (tr *TenantRepository) find(matches func(tenant *entity.Tenant) bool) (*entity.Tenant, error) {
+	tr.mu.RLock()
+	defer tr.mu.RUnlock()
+
+	for _, stored := range tr.tenants {
+		if matches(stored) {
+			tenant := *stored
+			return &tenant, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (tr *TenantRepository) FindAll() ([]entity.Tenant, error) {
+	tr.mu.RLock()
+	defer tr.mu.RUnlock()
+
+	tenants := make([]entity.Tenant, 0, len(tr.tenants))
+	for _, stored := range tr.tenants {
+		tenants = append(tenants, *stored)
+	}
+	return tenants, nil
+}
+
+func (tr *TenantRepository) UpdateMaxLinks(id int, maxLinks *int) error {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+
+	for _, stored := range tr.tenants {
+		if stored.ID == id {
+			stored.MaxLinks = maxLinks
+			return nil
+		}
+	}
+	return gorm.ErrRecordNotFound
+}
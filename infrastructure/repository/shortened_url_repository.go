@@ -1,6 +1,9 @@
 package repository
 
 import (
+	"strings"
+	"time"
+
 	"github.com/lucasfarolfi/hire.me/internal/entity"
 	"gorm.io/gorm"
 )
@@ -13,38 +16,243 @@ func NewShortenedURLRepository(db *gorm.DB) *ShortenedURLRepository {
 	return &ShortenedURLRepository{DB: db}
 }
 
+// Create inserts the shortened URL. Inside WithTransaction it runs in a savepoint, so a rejected insert
+// does not abort the enclosing transaction.
 func (ur *ShortenedURLRepository) Create(shortUrl *entity.ShortenedURL) error {
-	return ur.DB.Create(shortUrl).Error
+	return ur.DB.Transaction(func(tx *gorm.DB) error {
+		return tx.Create(shortUrl).Error
+	})
+}
+
+// WithTransaction runs fn with a repository bound to a single transaction.
+func (ur *ShortenedURLRepository) WithTransaction(fn func(tx *ShortenedURLRepository) error) error {
+	return ur.DB.Transaction(func(tx *gorm.DB) error {
+		return fn(NewShortenedURLRepository(tx))
+	})
+}
+
+func (ur *ShortenedURLRepository) FindByAlias(tenantID int, alias string) (*entity.ShortenedURL, error) {
+	var shortUrl entity.ShortenedURL
+	err := ur.DB.Where("tenant_id = ? AND alias = ?", tenantID, alias).First(&shortUrl).Error
+	if err != nil {
+		return nil, err
+	}
+	return &shortUrl, nil
 }
 
-func (ur *ShortenedURLRepository) FindByAlias(alias string) (*entity.ShortenedURL, error) {
+// FindReusableByURLHash returns the oldest shortened URL of the tenant and owner with a generated alias, no
+// expiration and no access limit that redirects to the URL of the given hash with the given redirect type.
+func (ur *ShortenedURLRepository) FindReusableByURLHash(tenantID int, urlHash string, redirectType int, ownerID string) (*entity.ShortenedURL, error) {
 	var shortUrl entity.ShortenedURL
-	err := ur.DB.Where("alias = ?", alias).First(&shortUrl).Error
+	err := ur.DB.
+		Where("tenant_id = ? AND url_hash = ? AND custom_alias = ?", tenantID, urlHash, false).
+		Where("redirect_type = ? AND owner_id = ?", redirectType, ownerID).
+		Where("expires_at IS NULL AND max_access_times IS NULL AND deleted_at IS NULL").
+		Order("id").
+		First(&shortUrl).Error
 	if err != nil {
 		return nil, err
 	}
 	return &shortUrl, nil
 }
 
-func (ur *ShortenedURLRepository) ExistsByAlias(alias string) bool {
+// ExistsByAlias reports whether the alias is taken in the tenant, including by deleted shortened URLs.
+func (ur *ShortenedURLRepository) ExistsByAlias(tenantID int, alias string) bool {
 	var count int64
-	err := ur.DB.Model(&entity.ShortenedURL{}).Where("alias = ?", alias).Count(&count).Error
+	err := ur.DB.Model(&entity.ShortenedURL{}).Where("tenant_id = ? AND alias = ?", tenantID, alias).Count(&count).Error
 	if err != nil || count == 0 {
 		return false
 	}
 	return true
 }
 
-func (ur *ShortenedURLRepository) Get10MostAcessedUrls() ([]entity.ShortenedURL, error) {
+// Update saves the destination, expiration and redirect type of the shortened URL.
+func (ur *ShortenedURLRepository) Update(shortUrl *entity.ShortenedURL) error {
+	return ur.DB.Model(shortUrl).
+		Select("url", "url_hash", "url_host", "expires_at", "redirect_type").
+		Updates(shortUrl).Error
+}
+
+// Replace saves every column of the shortened URL over the stored one with the same ID.
+func (ur *ShortenedURLRepository) Replace(shortUrl *entity.ShortenedURL) error {
+	return ur.DB.Model(shortUrl).Select("*").Omit("id").Updates(shortUrl).Error
+}
+
+// SoftDelete marks the shortened URL as deleted at its DeletedAt, keeping the row to reserve the alias.
+func (ur *ShortenedURLRepository) SoftDelete(shortUrl *entity.ShortenedURL) error {
+	return ur.DB.Model(shortUrl).UpdateColumn("deleted_at", shortUrl.DeletedAt).Error
+}
+
+// UpdateFlag stores the FlaggedAt and FlagReason of the shortened URL.
+func (ur *ShortenedURLRepository) UpdateFlag(shortUrl *entity.ShortenedURL) error {
+	return ur.DB.Model(shortUrl).UpdateColumns(map[string]interface{}{
+		"flagged_at":  shortUrl.FlaggedAt,
+		"flag_reason": shortUrl.FlagReason,
+	}).Error
+}
+
+// CountLinks counts the shortened URLs of the tenant that were not deleted.
+func (ur *ShortenedURLRepository) CountLinks(tenantID int) (int64, error) {
+	var count int64
+	err := ur.DB.Model(&entity.ShortenedURL{}).Where("tenant_id = ? AND deleted_at IS NULL", tenantID).Count(&count).Error
+	return count, err
+}
+
+// FindLinks returns up to query.Limit shortened URLs matching the query, after its cursor when given.
+func (ur *ShortenedURLRepository) FindLinks(query entity.LinkQuery) ([]entity.ShortenedURL, error) {
+	db := ur.DB.Where("tenant_id = ?", query.TenantID)
+	if !query.IncludeDeleted {
+		db = db.Where("deleted_at IS NULL")
+	}
+	if query.AliasPrefix != "" {
+		db = db.Where("alias LIKE ? ESCAPE '!'", escapeLike(query.AliasPrefix)+"%")
+	}
+	if query.Domain != "" {
+		db = db.Where("(url_host = ? OR url_host LIKE ? ESCAPE '!')", query.Domain, "%."+escapeLike(query.Domain))
+	}
+	if query.OwnerID != "" {
+		db = db.Where("owner_id = ?", query.OwnerID)
+	}
+	if query.CreatedFrom != nil {
+		db = db.Where("created_at >= ?", *query.CreatedFrom)
+	}
+	if query.CreatedTo != nil {
+		db = db.Where("created_at < ?", *query.CreatedTo)
+	}
+	if query.MinAccessTimes > 0 {
+		db = db.Where("access_times >= ?", query.MinAccessTimes)
+	}
+	if query.Flagged {
+		db = db.Where("flagged_at IS NOT NULL")
+	}
+
+	direction, comparison := "ASC", ">"
+	if query.Descending {
+		direction, comparison = "DESC", "<"
+	}
+	var sortColumn string
+	var sortValue interface{}
+	switch query.SortBy {
+	case entity.LinkSortAccessTimes:
+		sortColumn = "access_times"
+		if query.After != nil {
+			sortValue = query.After.AccessTimes
+		}
+	case entity.LinkSortAlias:
+		sortColumn = "alias"
+		if query.After != nil {
+			sortValue = query.After.Alias
+		}
+	}
+	if query.After != nil {
+		if sortColumn == "" {
+			db = db.Where("id "+comparison+" ?", query.After.ID)
+		} else {
+			db = db.Where("("+sortColumn+" "+comparison+" ? OR ("+sortColumn+" = ? AND id "+comparison+" ?))",
+				sortValue, sortValue, query.After.ID)
+		}
+	}
+	if sortColumn != "" {
+		db = db.Order(sortColumn + " " + direction)
+	}
+
 	var shortUrls []entity.ShortenedURL
-	err := ur.DB.Order("access_times DESC").Limit(10).Find(&shortUrls).Error
+	err := db.Order("id " + direction).Limit(query.Limit).Find(&shortUrls).Error
 	if err != nil {
 		return nil, err
 	}
 	return shortUrls, nil
 }
 
-func (ur *ShortenedURLRepository) IncrementAccessTimesByID(id int) error {
-	return ur.DB.Model(&entity.ShortenedURL{}).Where("id = ?", id).
-		UpdateColumn("access_times", gorm.Expr("access_times + ?", 1)).Error
+// escapeLike escapes the LIKE wildcards of value, using ! as the escape character.
+func escapeLike(value string) string {
+	return strings.NewReplacer("!", "!!", "%", "!%", "_", "!_").Replace(value)
+}
+
+// FindMostAcessedUrls ranks the shortened URLs of the tenant that were not deleted by access times. When
+// since or until is given, only the accesses within that window are ranked and AccessTimes holds the
+// number of accesses in the window.
+func (ur *ShortenedURLRepository) FindMostAcessedUrls(tenantID, limit, offset int, since, until *time.Time) ([]entity.ShortenedURL, error) {
+	if since == nil && until == nil {
+		var shortUrls []entity.ShortenedURL
+		err := ur.DB.Where("tenant_id = ? AND deleted_at IS NULL", tenantID).Order("access_times DESC").Order("id").Limit(limit).Offset(offset).Find(&shortUrls).Error
+		if err != nil {
+			return nil, err
+		}
+		return shortUrls, nil
+	}
+
+	query := ur.DB.Model(&entity.ShortenedURL{}).
+		Select("shortened_urls.*, COUNT(click_events.id) AS window_access_times").
+		Joins("JOIN click_events ON click_events.shortened_url_id = shortened_urls.id").
+		Where("shortened_urls.tenant_id = ? AND shortened_urls.deleted_at IS NULL", tenantID)
+	if since != nil {
+		query = query.Where("click_events.clicked_at >= ?", *since)
+	}
+	if until != nil {
+		query = query.Where("click_events.clicked_at < ?", *until)
+	}
+
+	var ranked []struct {
+		entity.ShortenedURL
+		WindowAccessTimes int32 `gorm:"column:window_access_times"`
+	}
+	err := query.Group("shortened_urls.id").
+		Order("window_access_times DESC").
+		Order("shortened_urls.id").
+		Limit(limit).
+		Offset(offset).
+		Scan(&ranked).Error
+	if err != nil {
+		return nil, err
+	}
+
+	shortUrls := make([]entity.ShortenedURL, 0, len(ranked))
+	for _, r := range ranked {
+		r.ShortenedURL.AccessTimes = r.WindowAccessTimes
+		shortUrls = append(shortUrls, r.ShortenedURL)
+	}
+	return shortUrls, nil
+}
+
+// IncrementAccessTimesByID atomically increments the access times of the shortened URL unless it
+// already reached its max access times, reporting whether the increment was applied.
+func (ur *ShortenedURLRepository) IncrementAccessTimesByID(id int) (bool, error) {
+	result := ur.DB.Model(&entity.ShortenedURL{}).
+		Where("id = ? AND (max_access_times IS NULL OR access_times < max_access_times)", id).
+		UpdateColumn("access_times", gorm.Expr("access_times + ?", 1))
+	return result.RowsAffected > 0, result.Error
+}
+
+// DeleteExpiredBefore removes the shortened URLs expired before the given time. Deleted shortened URLs
+// are kept, so their aliases stay reserved. The click events of the expired shortened URLs are deleted
+// in the same transaction, so they no longer count towards the rankings.
+func (ur *ShortenedURLRepository) DeleteExpiredBefore(before time.Time) (int64, error) {
+	var deleted int64
+	err := ur.DB.Transaction(func(tx *gorm.DB) error {
+		expired := tx.Model(&entity.ShortenedURL{}).Select("id").
+			Where("expires_at IS NOT NULL AND expires_at < ? AND deleted_at IS NULL", before)
+		if err := tx.Where("shortened_url_id IN (?)", expired).Delete(&entity.ClickEvent{}).Error; err != nil {
+			return err
+		}
+		result := tx.Where("expires_at IS NOT NULL AND expires_at < ? AND deleted_at IS NULL", before).Delete(&entity.ShortenedURL{})
+		deleted = result.RowsAffected
+		return result.Error
+	})
+	return deleted, err
+}
+
+// AddAccessTimes adds each increment to the access times of the shortened URL with the matching ID
+// in a single transaction.
+func (ur *ShortenedURLRepository) AddAccessTimes(increments map[int]int32) error {
+	return ur.DB.Transaction(func(tx *gorm.DB) error {
+		for id, increment := range increments {
+			err := tx.Model(&entity.ShortenedURL{}).Where("id = ?", id).
+				UpdateColumn("access_times", gorm.Expr("access_times + ?", increment)).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
 }
@@ -3,6 +3,7 @@ package repository
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/lucasfarolfi/hire.me/internal/entity"
 	"github.com/stretchr/testify/assert"
@@ -45,9 +46,9 @@ func TestShortenerUrlRepositoryIntegration_Create(t *testing.T) {
 		err := repository.Create(shortUrl)
 		assert.NoError(t, err)
 
-		err = repository.Create(shortUrl)
+		err = repository.Create(&entity.ShortenedURL{Alias: "abc123", Url: "http://www.google.com"})
 
-		assert.Error(t, err, "An error should be returned when trying to create a duplicate alias")
+		assert.ErrorIs(t, err, gorm.ErrDuplicatedKey, "An error should be returned when trying to create a duplicate alias")
 	})
 }
 
@@ -65,7 +66,7 @@ func TestShortenerUrlRepositoryIntegration_FindByAlias(t *testing.T) {
 		err := db.Create(shortUrl).Error
 		assert.NoError(t, err)
 
-		retrievedShortUrl, err := repository.FindByAlias(shortUrl.Alias)
+		retrievedShortUrl, err := repository.FindByAlias(0, shortUrl.Alias)
 
 		assert.NoError(t, err)
 		expected := shortUrl
@@ -78,7 +79,7 @@ func TestShortenerUrlRepositoryIntegration_FindByAlias(t *testing.T) {
 
 		nonExistentAlias := "nonexistent"
 
-		retrievedShortUrl, err := repository.FindByAlias(nonExistentAlias)
+		retrievedShortUrl, err := repository.FindByAlias(0, nonExistentAlias)
 
 		assert.Error(t, err, "An error should be returned when the alias does not exist in the database")
 		assert.Nil(t, retrievedShortUrl, "The retrieved short URL should be nil when the alias does not exist")
@@ -98,7 +99,7 @@ func TestShortenedURLRepository_ExistsByAlias(t *testing.T) {
 		err := db.Create(shortUrl).Error
 		assert.NoError(t, err)
 
-		exists := repository.ExistsByAlias("abc123")
+		exists := repository.ExistsByAlias(0, "abc123")
 		assert.True(t, exists, "ExistsByAlias should return true for an existing alias")
 	})
 
@@ -106,7 +107,7 @@ func TestShortenedURLRepository_ExistsByAlias(t *testing.T) {
 		db := loadDB(t)
 		repository := NewShortenedURLRepository(db)
 
-		exists := repository.ExistsByAlias("nonexistent")
+		exists := repository.ExistsByAlias(0, "nonexistent")
 		assert.False(t, exists, "ExistsByAlias should return false for a non-existing alias")
 	})
 }
@@ -127,21 +128,69 @@ func TestShortenedURLRepository_IncrementAccessTimes(t *testing.T) {
 		err = db.Where("alias = ?", "abc123").First(&shortUrl).Error
 		assert.NoError(t, err)
 
-		err = repository.IncrementAccessTimesByID(shortUrl.ID)
+		incremented, err := repository.IncrementAccessTimesByID(shortUrl.ID)
 		assert.NoError(t, err)
+		assert.True(t, incremented, "The increment should be applied")
 
 		var updatedShortUrl entity.ShortenedURL
 		err = db.First(&updatedShortUrl, "alias = ?", "abc123").Error
 		assert.NoError(t, err)
 		assert.Equal(t, int32(1), updatedShortUrl.AccessTimes, "AccessTimes should be incremented to 1")
 	})
+
+	t.Run("Given an alias that reached its max access times, when IncrementAccessTimes is called, then it should not increment the access times", func(t *testing.T) {
+		db := loadDB(t)
+		repository := NewShortenedURLRepository(db)
+
+		maxAccessTimes := int32(2)
+		shortUrl := &entity.ShortenedURL{
+			Alias:          "abc123",
+			Url:            "http://www.bemobi.com.br",
+			AccessTimes:    0,
+			MaxAccessTimes: &maxAccessTimes,
+		}
+		err := db.Create(shortUrl).Error
+		assert.NoError(t, err)
+
+		results := make([]bool, 0, 3)
+		for i := 0; i < 3; i++ {
+			incremented, err := repository.IncrementAccessTimesByID(shortUrl.ID)
+			assert.NoError(t, err)
+			results = append(results, incremented)
+		}
+		assert.Equal(t, []bool{true, true, false}, results, "Only the accesses within the limit should be counted")
+
+		var updatedShortUrl entity.ShortenedURL
+		err = db.First(&updatedShortUrl, "alias = ?", "abc123").Error
+		assert.NoError(t, err)
+		assert.Equal(t, int32(2), updatedShortUrl.AccessTimes, "AccessTimes should stop at the max access times")
+	})
 }
 
-func TestShortenedURLRepository_Get10MostAcessedUrls(t *testing.T) {
-	t.Run("When Get10MostAcessedUrls is called, then it should return the top 10 most accessed URLs", func(t *testing.T) {
+func TestShortenedURLRepository_AddAccessTimes(t *testing.T) {
+	t.Run("Given stored shortened urls, when AddAccessTimes is called with a batch of increments, then it should add each increment to its shortened URL", func(t *testing.T) {
 		db := loadDB(t)
 		repository := NewShortenedURLRepository(db)
 
+		first := &entity.ShortenedURL{Alias: "first", Url: "http://www.example1.com", AccessTimes: 10}
+		second := &entity.ShortenedURL{Alias: "second", Url: "http://www.example2.com"}
+		assert.NoError(t, db.Create(first).Error)
+		assert.NoError(t, db.Create(second).Error)
+
+		err := repository.AddAccessTimes(map[int]int32{first.ID: 5, second.ID: 3})
+		assert.NoError(t, err)
+
+		updatedFirst, err := repository.FindByAlias(0, "first")
+		assert.NoError(t, err)
+		assert.Equal(t, int32(15), updatedFirst.AccessTimes)
+		updatedSecond, err := repository.FindByAlias(0, "second")
+		assert.NoError(t, err)
+		assert.Equal(t, int32(3), updatedSecond.AccessTimes)
+	})
+}
+
+func TestShortenedURLRepository_FindMostAcessedUrls(t *testing.T) {
+	seed := func(t *testing.T, db *gorm.DB) {
 		for i := 1; i <= 15; i++ {
 			shortUrl := &entity.ShortenedURL{
 				Alias:       fmt.Sprintf("alias%d", i),
@@ -151,8 +200,14 @@ func TestShortenedURLRepository_Get10MostAcessedUrls(t *testing.T) {
 			err := db.Create(shortUrl).Error
 			assert.NoError(t, err)
 		}
+	}
+
+	t.Run("When FindMostAcessedUrls is called with a limit of 10, then it should return the top 10 most accessed URLs", func(t *testing.T) {
+		db := loadDB(t)
+		repository := NewShortenedURLRepository(db)
+		seed(t, db)
 
-		mostAccessedUrls, err := repository.Get10MostAcessedUrls()
+		mostAccessedUrls, err := repository.FindMostAcessedUrls(0, 10, 0, nil, nil)
 		assert.NoError(t, err)
 		assert.Len(t, mostAccessedUrls, 10, "Should return exactly 10 most accessed URLs")
 
@@ -162,12 +217,408 @@ func TestShortenedURLRepository_Get10MostAcessedUrls(t *testing.T) {
 				"URLs should be ordered by AccessTimes in descending order")
 		}
 	})
+
+	t.Run("When FindMostAcessedUrls is called with an offset, then it should return the next page of the ranking", func(t *testing.T) {
+		db := loadDB(t)
+		repository := NewShortenedURLRepository(db)
+		seed(t, db)
+
+		mostAccessedUrls, err := repository.FindMostAcessedUrls(0, 3, 12, nil, nil)
+		assert.NoError(t, err)
+
+		aliases := make([]string, 0, len(mostAccessedUrls))
+		for _, shortUrl := range mostAccessedUrls {
+			aliases = append(aliases, shortUrl.Alias)
+		}
+		assert.Equal(t, []string{"alias3", "alias2", "alias1"}, aliases, "Should return the last page of the ranking")
+	})
+
+	t.Run("Given click events in and out of a time window, when FindMostAcessedUrls is called with since and until, then it should rank only the accesses within the window", func(t *testing.T) {
+		db := loadDB(t)
+		repository := NewShortenedURLRepository(db)
+		seed(t, db)
+
+		since := time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC)
+		until := since.AddDate(0, 0, 7)
+		clicks := map[int][]time.Time{
+			1:  {since, since.Add(time.Hour), since.Add(48 * time.Hour)},
+			2:  {since.Add(time.Hour), until.Add(-time.Second)},
+			15: {since.Add(-time.Second), until, until.Add(time.Hour)},
+		}
+		for id, clickTimes := range clicks {
+			for _, clickedAt := range clickTimes {
+				assert.NoError(t, db.Create(&entity.ClickEvent{ShortenedURLID: id, ClickedAt: clickedAt}).Error)
+			}
+		}
+
+		mostAccessedUrls, err := repository.FindMostAcessedUrls(0, 10, 0, &since, &until)
+		assert.NoError(t, err)
+		assert.Len(t, mostAccessedUrls, 2, "Only the URLs accessed within the window should be ranked")
+		assert.Equal(t, "alias1", mostAccessedUrls[0].Alias)
+		assert.Equal(t, int32(3), mostAccessedUrls[0].AccessTimes, "AccessTimes should count only the accesses within the window")
+		assert.Equal(t, "alias2", mostAccessedUrls[1].Alias)
+		assert.Equal(t, int32(2), mostAccessedUrls[1].AccessTimes, "AccessTimes should count only the accesses within the window")
+	})
+}
+
+func TestShortenedURLRepository_DeleteExpiredBefore(t *testing.T) {
+	t.Run("Given expired and valid shortened urls, when DeleteExpiredBefore is called, then it should delete only the urls expired before the given date", func(t *testing.T) {
+		db := loadDB(t)
+		repository := NewShortenedURLRepository(db)
+
+		now := time.Now().UTC()
+		longExpired := now.Add(-48 * time.Hour)
+		recentlyExpired := now.Add(-time.Hour)
+		notExpired := now.Add(time.Hour)
+		shortUrls := []*entity.ShortenedURL{
+			{Alias: "long-expired", Url: "http://www.example1.com", ExpiresAt: &longExpired},
+			{Alias: "recently-expired", Url: "http://www.example2.com", ExpiresAt: &recentlyExpired},
+			{Alias: "not-expired", Url: "http://www.example3.com", ExpiresAt: &notExpired},
+			{Alias: "never-expires", Url: "http://www.example4.com"},
+		}
+		for _, shortUrl := range shortUrls {
+			assert.NoError(t, db.Create(shortUrl).Error)
+		}
+
+		deleted, err := repository.DeleteExpiredBefore(now.Add(-24 * time.Hour))
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), deleted, "Only the url expired before the given date should be deleted")
+
+		assert.False(t, repository.ExistsByAlias(0, "long-expired"))
+		assert.True(t, repository.ExistsByAlias(0, "recently-expired"))
+		assert.True(t, repository.ExistsByAlias(0, "not-expired"))
+		assert.True(t, repository.ExistsByAlias(0, "never-expires"))
+	})
+
+	t.Run("Given click events of an expired shortened url, when DeleteExpiredBefore is called, then it should delete them along with the url", func(t *testing.T) {
+		db := loadDB(t)
+		repository := NewShortenedURLRepository(db)
+		now := time.Now().UTC()
+		expiredAt := now.Add(-time.Hour)
+		expired := &entity.ShortenedURL{Alias: "expired", Url: "http://www.example1.com", ExpiresAt: &expiredAt}
+		active := &entity.ShortenedURL{Alias: "active", Url: "http://www.example2.com"}
+		assert.NoError(t, db.Create(expired).Error)
+		assert.NoError(t, db.Create(active).Error)
+		for _, id := range []int{expired.ID, expired.ID, active.ID} {
+			assert.NoError(t, db.Create(&entity.ClickEvent{ShortenedURLID: id, ClickedAt: now}).Error)
+		}
+
+		deleted, err := repository.DeleteExpiredBefore(now)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), deleted)
+
+		var events []entity.ClickEvent
+		assert.NoError(t, db.Find(&events).Error)
+		assert.Len(t, events, 1, "Only the click events of the url that was not deleted should be kept")
+		assert.Equal(t, active.ID, events[0].ShortenedURLID)
+	})
+}
+
+func TestShortenerUrlRepositoryIntegration_FindReusableByURLHash(t *testing.T) {
+	t.Run("Given shortened urls of the same destination, when FindReusableByURLHash is called, then it should return the oldest plain one with a generated alias", func(t *testing.T) {
+		db := loadDB(t)
+		repository := NewShortenedURLRepository(db)
+		destination := "http://www.bemobi.com.br"
+		expiresAt := time.Now().UTC().Add(time.Hour)
+		max := int32(3)
+
+		custom := entity.NewShortenedURL("custom", destination)
+		custom.CustomAlias = true
+		expiring := entity.NewShortenedURL("expiring", destination)
+		expiring.ExpiresAt = &expiresAt
+		limited := entity.NewShortenedURL("limited", destination)
+		limited.MaxAccessTimes = &max
+		permanent := entity.NewShortenedURL("permanent", destination)
+		permanent.RedirectType = 301
+		for _, shortUrl := range []*entity.ShortenedURL{
+			custom, expiring, limited, permanent,
+			entity.NewShortenedURL("other", "http://www.google.com"),
+			entity.NewShortenedURL("plain", destination),
+			entity.NewShortenedURL("plain-again", destination),
+		} {
+			assert.NoError(t, repository.Create(shortUrl))
+		}
+
+		found, err := repository.FindReusableByURLHash(0, entity.HashURL(destination), 0, "")
+		assert.NoError(t, err)
+		assert.Equal(t, "plain", found.Alias)
+
+		found, err = repository.FindReusableByURLHash(0, entity.HashURL(destination), 301, "")
+		assert.NoError(t, err)
+		assert.Equal(t, "permanent", found.Alias)
+	})
+
+	t.Run("Given no reusable shortened url, when FindReusableByURLHash is called, then it should return a record not found error", func(t *testing.T) {
+		repository := NewShortenedURLRepository(loadDB(t))
+
+		found, err := repository.FindReusableByURLHash(0, entity.HashURL("http://www.bemobi.com.br"), 0, "")
+
+		assert.Nil(t, found)
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	})
 }
 
 func loadDB(t *testing.T) *gorm.DB {
-	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{TranslateError: true, NowFunc: func() time.Time { return time.Now().UTC() }})
 	assert.NoError(t, err)
-	err = db.AutoMigrate(&entity.ShortenedURL{})
+	err = db.AutoMigrate(&entity.ShortenedURL{}, &entity.ClickEvent{}, &entity.IDBlock{}, &entity.APIKey{}, &entity.Tenant{}, &entity.Domain{})
 	assert.NoError(t, err)
 	return db
 }
+
+func TestShortenerUrlRepositoryIntegration_UpdateAndSoftDelete(t *testing.T) {
+	t.Run("Given a stored shortened url, when Update is called, then it should save only the editable fields", func(t *testing.T) {
+		db := loadDB(t)
+		repository := NewShortenedURLRepository(db)
+		shortUrl := entity.NewShortenedURL("abc123", "http://www.bemobi.com.br")
+		shortUrl.AccessTimes = 3
+		assert.NoError(t, repository.Create(shortUrl))
+
+		expiresAt := time.Now().UTC().Add(time.Hour).Truncate(time.Second)
+		shortUrl.Url = "http://www.bemobi.com.br/fixed"
+		shortUrl.URLHash = entity.HashURL(shortUrl.Url)
+		shortUrl.ExpiresAt = &expiresAt
+		shortUrl.RedirectType = 301
+		shortUrl.AccessTimes = 0
+		assert.NoError(t, repository.Update(shortUrl))
+
+		stored, err := repository.FindByAlias(0, "abc123")
+		assert.NoError(t, err)
+		assert.Equal(t, "http://www.bemobi.com.br/fixed", stored.Url)
+		assert.Equal(t, entity.HashURL("http://www.bemobi.com.br/fixed"), stored.URLHash)
+		assert.True(t, expiresAt.Equal(*stored.ExpiresAt))
+		assert.Equal(t, 301, stored.RedirectType)
+		assert.Equal(t, int32(3), stored.AccessTimes, "Access times should not be overwritten by Update")
+
+		shortUrl.ExpiresAt = nil
+		assert.NoError(t, repository.Update(shortUrl))
+		stored, err = repository.FindByAlias(0, "abc123")
+		assert.NoError(t, err)
+		assert.Nil(t, stored.ExpiresAt, "Update should be able to remove the expiration")
+	})
+
+	t.Run("Given a stored shortened url, when UpdateFlag is called, then it should set and clear the flag seen by FindLinks", func(t *testing.T) {
+		db := loadDB(t)
+		repository := NewShortenedURLRepository(db)
+		shortUrl := entity.NewShortenedURL("abc123", "http://evil.example.com")
+		assert.NoError(t, repository.Create(shortUrl))
+		assert.NoError(t, repository.Create(entity.NewShortenedURL("clean", "http://www.bemobi.com.br")))
+
+		flaggedAt := time.Now().UTC().Truncate(time.Second)
+		shortUrl.FlaggedAt, shortUrl.FlagReason = &flaggedAt, "evil.example.com"
+		assert.NoError(t, repository.UpdateFlag(shortUrl))
+
+		links, err := repository.FindLinks(entity.LinkQuery{Flagged: true, Limit: 10})
+		assert.NoError(t, err)
+		assert.Len(t, links, 1)
+		assert.Equal(t, "abc123", links[0].Alias)
+		assert.True(t, flaggedAt.Equal(*links[0].FlaggedAt))
+		assert.Equal(t, "evil.example.com", links[0].FlagReason)
+
+		shortUrl.FlaggedAt, shortUrl.FlagReason = nil, ""
+		assert.NoError(t, repository.UpdateFlag(shortUrl))
+		links, err = repository.FindLinks(entity.LinkQuery{Flagged: true, Limit: 10})
+		assert.NoError(t, err)
+		assert.Empty(t, links)
+	})
+
+	t.Run("Given a soft deleted shortened url, when it is queried, then it should stay reserved but leave rankings, reuse and the sweeper", func(t *testing.T) {
+		db := loadDB(t)
+		repository := NewShortenedURLRepository(db)
+		now := time.Now().UTC()
+		expired := now.Add(-48 * time.Hour)
+		deleted := entity.NewShortenedURL("deleted", "http://www.bemobi.com.br")
+		deleted.AccessTimes = 10
+		deleted.ExpiresAt = &expired
+		kept := entity.NewShortenedURL("kept", "http://www.example.com")
+		kept.AccessTimes = 1
+		reusable := entity.NewShortenedURL("reusable", "http://www.example.com/reusable")
+		for _, shortUrl := range []*entity.ShortenedURL{deleted, kept, reusable} {
+			assert.NoError(t, repository.Create(shortUrl))
+		}
+
+		deleted.DeletedAt = &now
+		reusable.DeletedAt = &now
+		assert.NoError(t, repository.SoftDelete(deleted))
+		assert.NoError(t, repository.SoftDelete(reusable))
+
+		stored, err := repository.FindByAlias(0, "deleted")
+		assert.NoError(t, err)
+		assert.True(t, stored.IsDeleted())
+		assert.True(t, repository.ExistsByAlias(0, "deleted"))
+		assert.ErrorIs(t, repository.Create(entity.NewShortenedURL("deleted", "http://www.other.com")), gorm.ErrDuplicatedKey)
+
+		ranking, err := repository.FindMostAcessedUrls(0, 10, 0, nil, nil)
+		assert.NoError(t, err)
+		assert.Len(t, ranking, 1)
+		assert.Equal(t, "kept", ranking[0].Alias)
+
+		_, err = repository.FindReusableByURLHash(0, reusable.URLHash, 0, "")
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+		swept, err := repository.DeleteExpiredBefore(now)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(0), swept)
+		assert.True(t, repository.ExistsByAlias(0, "deleted"))
+	})
+}
+
+func TestShortenerUrlRepositoryIntegration_Replace(t *testing.T) {
+	t.Run("Given a stored shortened url, when Replace is called, then it should save every field over the stored row", func(t *testing.T) {
+		db := loadDB(t)
+		repository := NewShortenedURLRepository(db)
+		shortUrl := entity.NewShortenedURL("abc123", "http://www.bemobi.com.br")
+		shortUrl.AccessTimes = 3
+		assert.NoError(t, repository.Create(shortUrl))
+
+		createdAt := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+		maxAccessTimes := int32(10)
+		replacement := entity.NewShortenedURL("abc123", "http://www.example.com")
+		replacement.ID = shortUrl.ID
+		replacement.CreatedAt = createdAt
+		replacement.MaxAccessTimes = &maxAccessTimes
+		replacement.CustomAlias = true
+		assert.NoError(t, repository.Replace(replacement))
+
+		stored, err := repository.FindByAlias(0, "abc123")
+		assert.NoError(t, err)
+		assert.Equal(t, shortUrl.ID, stored.ID)
+		assert.Equal(t, "http://www.example.com", stored.Url)
+		assert.Equal(t, "www.example.com", stored.URLHost)
+		assert.Equal(t, int32(0), stored.AccessTimes, "Replace should overwrite zero values as well")
+		assert.True(t, createdAt.Equal(stored.CreatedAt))
+		assert.Equal(t, &maxAccessTimes, stored.MaxAccessTimes)
+		assert.True(t, stored.CustomAlias)
+	})
+}
+
+func TestShortenerUrlRepositoryIntegration_WithTransaction(t *testing.T) {
+	t.Run("Given a transaction with a rejected insert, when it commits, then the other inserts should be kept", func(t *testing.T) {
+		db := loadDB(t)
+		repository := NewShortenedURLRepository(db)
+
+		err := repository.WithTransaction(func(tx *ShortenedURLRepository) error {
+			assert.NoError(t, tx.Create(entity.NewShortenedURL("first", "http://www.bemobi.com.br")))
+			assert.ErrorIs(t, tx.Create(entity.NewShortenedURL("first", "http://www.example.com")), gorm.ErrDuplicatedKey)
+			assert.NoError(t, tx.Create(entity.NewShortenedURL("second", "http://www.example.com")))
+			return nil
+		})
+
+		assert.NoError(t, err)
+		assert.True(t, repository.ExistsByAlias(0, "first"))
+		assert.True(t, repository.ExistsByAlias(0, "second"))
+	})
+
+	t.Run("Given a transaction that fails, when it returns, then its inserts should be rolled back", func(t *testing.T) {
+		db := loadDB(t)
+		repository := NewShortenedURLRepository(db)
+		failure := fmt.Errorf("failure")
+
+		err := repository.WithTransaction(func(tx *ShortenedURLRepository) error {
+			assert.NoError(t, tx.Create(entity.NewShortenedURL("first", "http://www.bemobi.com.br")))
+			return failure
+		})
+
+		assert.ErrorIs(t, err, failure)
+		assert.False(t, repository.ExistsByAlias(0, "first"))
+	})
+}
+
+func TestShortenerUrlRepositoryIntegration_FindLinks(t *testing.T) {
+	db := loadDB(t)
+	repository := NewShortenedURLRepository(db)
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i, link := range []struct {
+		alias       string
+		url         string
+		accessTimes int32
+	}{
+		{"promo_a", "http://www.bemobi.com.br/a", 5},
+		{"promo%b", "http://bemobi.com.br/b", 5},
+		{"promoXc", "http://shop.example.com/c", 9},
+		{"other", "http://notbemobi.com.br/d", 1},
+		{"deleted", "http://www.bemobi.com.br/e", 50},
+	} {
+		shortUrl := entity.NewShortenedURL(link.alias, link.url)
+		shortUrl.AccessTimes = link.accessTimes
+		shortUrl.CreatedAt = base.Add(time.Duration(i) * time.Hour)
+		assert.NoError(t, repository.Create(shortUrl))
+	}
+	now := time.Now().UTC()
+	assert.NoError(t, db.Model(&entity.ShortenedURL{}).Where("alias = ?", "deleted").Update("deleted_at", now).Error)
+
+	aliases := func(links []entity.ShortenedURL) []string {
+		result := []string{}
+		for _, link := range links {
+			result = append(result, link.Alias)
+		}
+		return result
+	}
+
+	t.Run("Given filters, when FindLinks is called, then it should return only the matching links that were not deleted", func(t *testing.T) {
+		from, to := base.Add(time.Hour), base.Add(3*time.Hour)
+		for name, test := range map[string]struct {
+			query    entity.LinkQuery
+			expected []string
+		}{
+			"alias prefix with wildcards": {entity.LinkQuery{AliasPrefix: "promo_"}, []string{"promo_a"}},
+			"domain and subdomains":       {entity.LinkQuery{Domain: "bemobi.com.br"}, []string{"promo_a", "promo%b"}},
+			"created range":               {entity.LinkQuery{CreatedFrom: &from, CreatedTo: &to}, []string{"promo%b", "promoXc"}},
+			"min clicks":                  {entity.LinkQuery{MinAccessTimes: 6}, []string{"promoXc"}},
+		} {
+			test.query.Limit = 10
+			links, err := repository.FindLinks(test.query)
+
+			assert.NoError(t, err, name)
+			assert.Equal(t, test.expected, aliases(links), name)
+		}
+	})
+
+	t.Run("Given a sort and a cursor, when FindLinks is called, then it should return the links after the cursor in order", func(t *testing.T) {
+		query := entity.LinkQuery{SortBy: entity.LinkSortAccessTimes, Descending: true, Limit: 2}
+		links, err := repository.FindLinks(query)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"promoXc", "promo%b"}, aliases(links))
+
+		query.After = query.CursorOf(&links[1])
+		links, err = repository.FindLinks(query)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"promo_a", "other"}, aliases(links))
+
+		query = entity.LinkQuery{SortBy: entity.LinkSortAlias, Limit: 10, After: &entity.LinkCursor{ID: 3, Alias: "promo%b"}}
+		links, err = repository.FindLinks(query)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"promoXc", "promo_a"}, aliases(links))
+	})
+}
+
+func TestShortenerUrlRepositoryIntegration_Tenants(t *testing.T) {
+	t.Run("Given the same alias in two tenants, when they are queried, then each tenant should only see its own shortened urls", func(t *testing.T) {
+		repository := NewShortenedURLRepository(loadDB(t))
+		other := entity.NewShortenedURL("abc123", "http://www.google.com")
+		other.TenantID = 7
+		deleted := entity.NewShortenedURL("def456", "http://www.google.com")
+		deleted.TenantID = 7
+		deletedAt := time.Now().UTC()
+		deleted.DeletedAt = &deletedAt
+		assert.NoError(t, repository.Create(entity.NewShortenedURL("abc123", "http://www.bemobi.com.br")))
+		assert.NoError(t, repository.Create(other))
+		assert.NoError(t, repository.Create(deleted))
+
+		found, err := repository.FindByAlias(7, "abc123")
+		assert.NoError(t, err)
+		assert.Equal(t, "http://www.google.com", found.Url)
+		assert.False(t, repository.ExistsByAlias(8, "abc123"))
+		assert.ErrorIs(t, repository.Create(entity.NewShortenedURL("abc123", "http://www.bemobi.com.br")), gorm.ErrDuplicatedKey)
+		count, err := repository.CountLinks(7)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), count)
+		links, err := repository.FindLinks(entity.LinkQuery{TenantID: 7, SortBy: entity.LinkSortAlias, Limit: 10})
+		assert.NoError(t, err)
+		assert.Len(t, links, 1)
+		ranking, err := repository.FindMostAcessedUrls(0, 10, 0, nil, nil)
+		assert.NoError(t, err)
+		assert.Len(t, ranking, 1)
+		assert.Equal(t, "http://www.bemobi.com.br", ranking[0].Url)
+	})
+}
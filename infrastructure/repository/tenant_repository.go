@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"github.com/lucasfarolfi/hire.me/internal/entity"
+	"gorm.io/gorm"
+)
+
+type TenantRepository struct {
+	DB *gorm.DB
+}
+
+func NewTenantRepository(db *gorm.DB) *TenantRepository {
+	return &TenantRepository{DB: db}
+}
+
+func (tr *TenantRepository) Create(tenant *entity.Tenant) error {
+	return tr.DB.Create(tenant).Error
+}
+
+func (tr *TenantRepository) FindByID(id int) (*entity.Tenant, error) {
+	var tenant entity.Tenant
+	err := tr.DB.First(&tenant, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &tenant, nil
+}
+
+func (tr *TenantRepository) FindBySlug(slug string) (*entity.Tenant, error) {
+	var tenant entity.Tenant
+	err := tr.DB.Where("slug = ?", slug).First(&tenant).Error
+	if err != nil {
+		return nil, err
+	}
+	return &tenant, nil
+}
+
+func (tr *TenantRepository) FindAll() ([]entity.Tenant, error) {
+	var tenants []entity.Tenant
+	err := tr.DB.Order("id").Find(&tenants).Error
+	if err != nil {
+		return nil, err
+	}
+	return tenants, nil
+}
+
+func (tr *TenantRepository) UpdateMaxLinks(id int, maxLinks *int) error {
+	var tenant entity.Tenant
+	if err := tr.DB.First(&tenant, id).Error; err != nil {
+		return err
+	}
+	return tr.DB.Model(&entity.Tenant{}).Where("id = ?", id).UpdateColumn("max_links", maxLinks).Error
+}
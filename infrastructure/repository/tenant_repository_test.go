@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lucasfarolfi/hire.me/internal/entity"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestTenantRepositoryIntegration(t *testing.T) {
+	t.Run("Given a created tenant, when it is found and its quota is changed, then it should keep the new quota", func(t *testing.T) {
+		repository := NewTenantRepository(loadDB(t))
+		tenant := &entity.Tenant{Slug: "marketing", Name: "Marketing", CreatedAt: time.Now().UTC()}
+		assert.NoError(t, repository.Create(tenant))
+		assert.ErrorIs(t, repository.Create(&entity.Tenant{Slug: "marketing"}), gorm.ErrDuplicatedKey)
+
+		maxLinks := 10
+		assert.NoError(t, repository.UpdateMaxLinks(tenant.ID, &maxLinks))
+
+		found, err := repository.FindBySlug("marketing")
+		assert.NoError(t, err)
+		assert.Equal(t, 10, *found.MaxLinks)
+		found, err = repository.FindByID(tenant.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, "Marketing", found.Name)
+		tenants, err := repository.FindAll()
+		assert.NoError(t, err)
+		assert.Len(t, tenants, 1)
+
+		_, err = repository.FindBySlug("unknown")
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+		assert.ErrorIs(t, repository.UpdateMaxLinks(tenant.ID+1, nil), gorm.ErrRecordNotFound)
+	})
+}
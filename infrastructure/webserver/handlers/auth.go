@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/lucasfarolfi/hire.me/internal/entity"
+	"github.com/lucasfarolfi/hire.me/internal/service"
+)
+
+var errAdminRequired = errors.New("route requires an admin api key")
+
+type apiKeyContextKey struct{}
+
+// Authenticator guards routes with the API keys of the APIKeyService. Keys are sent in the X-API-Key
+// header or as a bearer token in the Authorization header.
+type Authenticator struct {
+	keys *service.APIKeyService
+}
+
+func NewAuthenticator(keys *service.APIKeyService) *Authenticator {
+	return &Authenticator{keys: keys}
+}
+
+// Authenticate rejects requests without a valid API key and passes the key to next through the request
+// context, where the handlers use it to check the ownership of links.
+func (a *Authenticator) Authenticate(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		key, err := a.keys.Authenticate(requestAPIKey(r))
+		if err != nil {
+			if errors.Is(err, service.ErrInvalidAPIKey) {
+				w.Header().Set("WWW-Authenticate", `Bearer realm="api"`)
+				writeErrorResponse(w, err, "")
+				return
+			}
+			log.Println("Failed to authenticate API key:", err)
+			http.Error(w, "failed to authenticate", http.StatusInternalServerError)
+			return
+		}
+		next(w, r.WithContext(context.WithValue(r.Context(), apiKeyContextKey{}, key)))
+	}
+}
+
+// RequireAdmin works like Authenticate but only lets admin keys through.
+func (a *Authenticator) RequireAdmin(next http.HandlerFunc) http.HandlerFunc {
+	return a.Authenticate(func(w http.ResponseWriter, r *http.Request) {
+		if !apiKeyFromContext(r.Context()).Admin {
+			writeErrorResponse(w, errAdminRequired, "")
+			return
+		}
+		next(w, r)
+	})
+}
+
+func requestAPIKey(r *http.Request) string {
+	if key := r.Header.Get("X-API-Key"); key != "" {
+		return key
+	}
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if ok && strings.EqualFold(scheme, "Bearer") {
+		return strings.TrimSpace(token)
+	}
+	return ""
+}
+
+// apiKeyFromContext returns the API key of an authenticated request, or nil when the route is not
+// guarded by an Authenticator.
+func apiKeyFromContext(ctx context.Context) *entity.APIKey {
+	key, _ := ctx.Value(apiKeyContextKey{}).(*entity.APIKey)
+	return key
+}
+
+// authorize checks that the API key of the request, if any, may manage the link of the alias in the tenant
+// of svc. On failure it writes the error response and returns false.
+func (h *URLShortenerHandler) authorize(w http.ResponseWriter, r *http.Request, svc *service.URLShortenerService, alias string) bool {
+	key := apiKeyFromContext(r.Context())
+	if key == nil {
+		return true
+	}
+	err := svc.Authorize(key, alias)
+	if err == nil {
+		return true
+	}
+	if !writeErrorResponse(w, err, alias) {
+		log.Println("Failed to authorize alias", alias, ":", err)
+		http.Error(w, "failed to authorize", http.StatusInternalServerError)
+	}
+	return false
+}
+
+// ownerOptions records the owner of the API key of the request, if any, on created links.
+func ownerOptions(r *http.Request) []service.CreateOption {
+	if key := apiKeyFromContext(r.Context()); key != nil {
+		return []service.CreateOption{service.WithOwner(key.OwnerID)}
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/lucasfarolfi/hire.me/internal/dto"
+	"github.com/stretchr/testify/assert"
+)
+
+func sendAuthenticatedRequest(t *testing.T, server *httptest.Server, key, method, path, body string) *http.Response {
+	req, err := http.NewRequest(method, server.URL+path, strings.NewReader(body))
+	assert.NoError(t, err)
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if key != "" {
+		req.Header.Set("Authorization", "Bearer "+key)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp
+}
+
+func TestAuthenticatorIntegration(t *testing.T) {
+	t.Run("Given no key or an invalid one, when a guarded route is requested, then it should return an unauthorized error", func(t *testing.T) {
+		server := newTestApp(t).server
+
+		for _, key := range []string{"", "hk_invalid"} {
+			resp := sendAuthenticatedRequest(t, server, key, http.MethodPost, "/api/v1/links", `{"url": "http://www.bemobi.com.br"}`)
+			assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+			assert.NotEmpty(t, resp.Header.Get("WWW-Authenticate"))
+			assert.Equal(t, "028", decodeErrCode(t, resp))
+		}
+		resp := sendAuthenticatedRequest(t, server, "", http.MethodPost, "/?url=http://www.bemobi.com.br", "")
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	})
+
+	t.Run("Given a link created with a key, when other keys manage it, then only its owner and admins should be allowed", func(t *testing.T) {
+		app := newTestApp(t)
+		server, alice, bob, admin := app.server, app.issueKey(t, 0, "alice", false), app.issueKey(t, 0, "bob", false), app.issueKey(t, 0, "ops", true)
+		resp := sendAuthenticatedRequest(t, server, alice, http.MethodPost, "/api/v1/links", `{"url": "http://www.bemobi.com.br", "alias": "bemobi"}`)
+		assert.Equal(t, http.StatusCreated, resp.StatusCode)
+
+		for _, request := range []struct{ method, path, body string }{
+			{http.MethodGet, "/api/v1/links/bemobi", ""},
+			{http.MethodPatch, "/api/v1/links/bemobi", `{"url": "http://www.example.com"}`},
+			{http.MethodDelete, "/api/v1/links/bemobi", ""},
+			{http.MethodGet, "/u/bemobi/stats", ""},
+		} {
+			resp = sendAuthenticatedRequest(t, server, bob, request.method, request.path, request.body)
+			assert.Equal(t, http.StatusForbidden, resp.StatusCode, "%s %s", request.method, request.path)
+			assert.Equal(t, "029", decodeErrCode(t, resp))
+		}
+
+		resp = sendAuthenticatedRequest(t, server, alice, http.MethodGet, "/u/bemobi/stats", "")
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		resp = sendAuthenticatedRequest(t, server, admin, http.MethodPatch, "/api/v1/links/bemobi", `{"url": "http://www.example.com"}`)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		resp = sendAuthenticatedRequest(t, server, alice, http.MethodDelete, "/api/v1/links/bemobi", "")
+		assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	})
+
+	t.Run("Given links of several owners, when they are listed, then keys should only see their own links unless admin", func(t *testing.T) {
+		app := newTestApp(t)
+		server, alice, bob, admin := app.server, app.issueKey(t, 0, "alice", false), app.issueKey(t, 0, "bob", false), app.issueKey(t, 0, "ops", true)
+		sendAuthenticatedRequest(t, server, alice, http.MethodPost, "/api/v1/links", `{"url": "http://www.bemobi.com.br", "alias": "alice1"}`)
+		sendAuthenticatedRequest(t, server, bob, http.MethodPost, "/api/v1/links", `{"url": "http://www.bemobi.com.br", "alias": "bob1"}`)
+
+		for key, expected := range map[string][]string{alice: {"alice1"}, bob: {"bob1"}, admin: {"bob1", "alice1"}} {
+			resp := sendAuthenticatedRequest(t, server, key, http.MethodGet, "/api/v1/links", "")
+			var page dto.LinkPageDTO
+			assert.NoError(t, json.NewDecoder(resp.Body).Decode(&page))
+			var aliases []string
+			for _, link := range page.Links {
+				aliases = append(aliases, link.Alias)
+			}
+			assert.Equal(t, expected, aliases)
+		}
+	})
+
+	t.Run("Given admin routes and redirects, when they are requested, then admin routes should need an admin key and redirects none", func(t *testing.T) {
+		app := newTestApp(t)
+		server, alice, admin := app.server, app.issueKey(t, 0, "alice", false), app.issueKey(t, 0, "ops", true)
+		resp := sendAuthenticatedRequest(t, server, alice, http.MethodPost, "/?url=http://www.bemobi.com.br&alias=bemobi", "")
+		assert.Equal(t, http.StatusCreated, resp.StatusCode)
+
+		resp = sendAuthenticatedRequest(t, server, alice, http.MethodGet, "/api/v1/admin/links/export", "")
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+		assert.Equal(t, "029", decodeErrCode(t, resp))
+		resp = sendAuthenticatedRequest(t, server, admin, http.MethodGet, "/api/v1/admin/links/export", "")
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }}
+		resp, err := client.Get(server.URL + "/u/bemobi")
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusFound, resp.StatusCode)
+	})
+}
+
+func TestAuthenticatorUnit_RequestAPIKey(t *testing.T) {
+	t.Run("Given keys in either header, when the request key is read, then it should prefer X-API-Key", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.Header.Set("Authorization", "bearer hk_token")
+		assert.Equal(t, "hk_token", requestAPIKey(r))
+
+		r.Header.Set("X-API-Key", "hk_header")
+		assert.Equal(t, "hk_header", requestAPIKey(r))
+
+		r = httptest.NewRequest(http.MethodGet, "/", nil)
+		r.Header.Set("Authorization", "Basic dXNlcjpwYXNz")
+		assert.Equal(t, "", requestAPIKey(r))
+	})
+}
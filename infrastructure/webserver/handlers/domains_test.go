@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/lucasfarolfi/hire.me/internal/dto"
+	"github.com/stretchr/testify/assert"
+)
+
+// addAcmeDomains creates the acme tenant of the app, with go.acme.io as its canonical domain and acme.link
+// as another domain of it, returning a key of the acme tenant.
+func addAcmeDomains(t *testing.T, app *testApp) (acmeKey string) {
+	acme, err := app.tenants.Create("acme", "", nil)
+	assert.NoError(t, err)
+	_, err = app.domains.Add("go.acme.io", acme.ID, "https", true)
+	assert.NoError(t, err)
+	_, err = app.domains.Add("acme.link", acme.ID, "https", false)
+	assert.NoError(t, err)
+	return app.issueKey(t, acme.ID, "alice", false)
+}
+
+func TestDomainsIntegration(t *testing.T) {
+	t.Run("Given a tenant with a canonical domain, when a link is created on another host, then the short url should use the canonical domain", func(t *testing.T) {
+		app := newTestApp(t)
+		server, acmeKey := app.server, addAcmeDomains(t, app)
+
+		resp := sendAuthenticatedRequest(t, server, acmeKey, http.MethodPost, "/api/v1/links", `{"url": "http://www.google.com", "alias": "promo"}`)
+
+		assert.Equal(t, http.StatusCreated, resp.StatusCode)
+		var link dto.LinkDTO
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&link))
+		assert.Equal(t, "https://go.acme.io/u/promo", link.ShortURL)
+	})
+
+	t.Run("Given links with the same alias in two tenants, when the alias is requested on each host, then the host should select the tenant", func(t *testing.T) {
+		app := newTestApp(t)
+		server, acmeKey := app.server, addAcmeDomains(t, app)
+		resp := sendAuthenticatedRequest(t, server, acmeKey, http.MethodPost, "/api/v1/links", `{"url": "http://www.google.com", "alias": "promo"}`)
+		assert.Equal(t, http.StatusCreated, resp.StatusCode)
+
+		for host, location := range map[string]string{"go.acme.io": "http://www.google.com", "ACME.link:8080": "http://www.google.com"} {
+			assert.Equal(t, location, redirectLocationOnHost(t, server, host, "/u/promo"), host)
+		}
+		req, err := http.NewRequest(http.MethodGet, server.URL+"/u/promo", nil)
+		assert.NoError(t, err)
+		resp, err = http.DefaultClient.Do(req)
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+}
+
+func redirectLocationOnHost(t *testing.T, server *httptest.Server, host, path string) string {
+	req, err := http.NewRequest(http.MethodGet, server.URL+path, nil)
+	assert.NoError(t, err)
+	req.Host = host
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }}
+	resp, err := client.Do(req)
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusFound, resp.StatusCode)
+	return resp.Header.Get("Location")
+}
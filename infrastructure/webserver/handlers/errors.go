@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/lucasfarolfi/hire.me/internal/service"
+	"gorm.io/gorm"
+)
+
+// errorResponse is the status and HttpResponseErrorBody content sent for a known error.
+type errorResponse struct {
+	err         error
+	statusCode  int
+	errCode     string
+	description string
+}
+
+var errorResponses = []errorResponse{
+	{service.ErrAliasAlreadyExists, http.StatusBadRequest, "001", "CUSTOM ALIAS ALREADY EXISTS"},
+	{gorm.ErrRecordNotFound, http.StatusNotFound, "002", "SHORTENED URL NOT FOUND"},
+	{service.ErrLinkExpired, http.StatusGone, "003", "LINK EXPIRED"},
+	{service.ErrInvalidExpiration, http.StatusBadRequest, "004", "INVALID EXPIRATION"},
+	{service.ErrAccessLimitReached, http.StatusGone, "005", "ACCESS LIMIT REACHED"},
+	{service.ErrInvalidMaxAccessTimes, http.StatusBadRequest, "006", "INVALID MAX ACCESS TIMES"},
+	{service.ErrInvalidStatsQuery, http.StatusBadRequest, "007", "INVALID STATS QUERY"},
+	{service.ErrInvalidRankingQuery, http.StatusBadRequest, "008", "INVALID RANKING QUERY"},
+	{service.ErrAliasGenerationFailed, http.StatusServiceUnavailable, "009", "ALIAS GENERATION FAILED"},
+	{service.ErrInvalidURL, http.StatusBadRequest, "010", "INVALID URL"},
+	{service.ErrURLSchemeNotAllowed, http.StatusBadRequest, "011", "URL SCHEME NOT ALLOWED"},
+	{service.ErrURLHostRequired, http.StatusBadRequest, "012", "URL HOST REQUIRED"},
+	{service.ErrURLTooLong, http.StatusBadRequest, "013", "URL TOO LONG"},
+	{service.ErrInvalidURLHost, http.StatusBadRequest, "014", "INVALID URL HOST"},
+	{service.ErrInvalidAlias, http.StatusBadRequest, "015", "INVALID CUSTOM ALIAS"},
+	{service.ErrReservedAlias, http.StatusBadRequest, "016", "RESERVED CUSTOM ALIAS"},
+	{errUnsupportedMediaType, http.StatusUnsupportedMediaType, "017", "UNSUPPORTED MEDIA TYPE"},
+	{errRequestBodyTooLarge, http.StatusRequestEntityTooLarge, "018", "REQUEST BODY TOO LARGE"},
+	{errInvalidRequestBody, http.StatusBadRequest, "019", "INVALID REQUEST BODY"},
+	{errNotAcceptable, http.StatusNotAcceptable, "020", "NOT ACCEPTABLE"},
+	{service.ErrLinkDeleted, http.StatusGone, "021", "LINK DELETED"},
+	{service.ErrInvalidLinkQuery, http.StatusBadRequest, "022", "INVALID LINK QUERY"},
+	{service.ErrInvalidRedirectType, http.StatusBadRequest, "023", "INVALID REDIRECT TYPE"},
+	{service.ErrInvalidTransferFormat, http.StatusBadRequest, "024", "INVALID TRANSFER FORMAT"},
+	{service.ErrInvalidConflictPolicy, http.StatusBadRequest, "025", "INVALID CONFLICT POLICY"},
+	{service.ErrInvalidImportRecord, http.StatusBadRequest, "026", "INVALID IMPORT RECORD"},
+	{service.ErrImportConflict, http.StatusConflict, "027", "IMPORT CONFLICT"},
+	{service.ErrInvalidAPIKey, http.StatusUnauthorized, "028", "UNAUTHORIZED"},
+	{service.ErrLinkNotOwned, http.StatusForbidden, "029", "FORBIDDEN"},
+	{errAdminRequired, http.StatusForbidden, "029", "FORBIDDEN"},
+	{service.ErrTenantQuotaExceeded, http.StatusForbidden, "030", "TENANT QUOTA EXCEEDED"},
+	{service.ErrTenantNotFound, http.StatusNotFound, "031", "TENANT NOT FOUND"},
+	{errRateLimited, http.StatusTooManyRequests, "032", "RATE LIMIT EXCEEDED"},
+	{service.ErrURLBlocked, http.StatusBadRequest, "033", "URL BLOCKED"},
+	{service.ErrLinkFlagged, http.StatusForbidden, "034", "LINK FLAGGED"},
+}
+
+// writeErrorResponse writes the error body mapped to err, reporting whether err is a known error.
+func writeErrorResponse(w http.ResponseWriter, err error, alias string) bool {
+	response, ok := lookupErrorResponse(err)
+	if ok {
+		retrieveErrorResponseBody(w, response.statusCode, response.errCode, response.description, alias)
+	}
+	return ok
+}
+
+func lookupErrorResponse(err error) (errorResponse, bool) {
+	for _, response := range errorResponses {
+		if errors.Is(err, response.err) {
+			return response, true
+		}
+	}
+	return errorResponse{}, false
+}
@@ -2,7 +2,11 @@ package handlers
 
 import (
 	"encoding/json"
+	"mime"
+	"net"
 	"net/http"
+	"strconv"
+	"strings"
 )
 
 type HttpResponseErrorBody struct {
@@ -18,3 +22,31 @@ func retrieveErrorResponseBody(w http.ResponseWriter, statusCode int, errCode, d
 		http.Error(w, "failed to encode error body", http.StatusInternalServerError)
 	}
 }
+
+// wantsJSON reports whether the client asked for the JSON view instead of being redirected,
+// either through the format query parameter or an Accept header listing application/json.
+func wantsJSON(r *http.Request) bool {
+	if r.URL.Query().Get("format") == "json" {
+		return true
+	}
+	for _, accepted := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(accepted))
+		if err != nil || mediaType != "application/json" {
+			continue
+		}
+		if q, err := strconv.ParseFloat(params["q"], 64); err == nil && q == 0 {
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+// clientIP returns the address of the peer that sent the request, without the port.
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/lucasfarolfi/hire.me/internal/dto"
+	"github.com/lucasfarolfi/hire.me/internal/service"
+)
+
+const defaultStatsRange = 7 * 24 * time.Hour
+
+func (h *URLShortenerHandler) GetStatsByAlias(w http.ResponseWriter, r *http.Request) {
+	alias := r.PathValue("alias")
+	if alias == "" {
+		http.Error(w, "alias is required", http.StatusBadRequest)
+		return
+	}
+	svc, ok := h.scope(w, r)
+	if !ok || !h.authorize(w, r, svc, alias) {
+		return
+	}
+
+	from, to, err := parseStatsRange(r.URL.Query().Get("from"), r.URL.Query().Get("to"))
+	if err != nil {
+		writeErrorResponse(w, service.ErrInvalidStatsQuery, alias)
+		return
+	}
+	interval := r.URL.Query().Get("interval")
+	if interval == "" {
+		interval = service.StatsIntervalDay
+	}
+
+	stats, err := svc.GetStatsByAlias(alias, from, to, interval)
+	if err != nil {
+		if !writeErrorResponse(w, err, alias) {
+			http.Error(w, "failed to retrieve shortened URL statistics", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err = json.NewEncoder(w).Encode(dto.NewLinkStatsDTO(stats)); err != nil {
+		http.Error(w, "failed to encode statistics response", http.StatusInternalServerError)
+	}
+}
+
+// parseStatsRange reads the optional from and to (RFC 3339) parameters, defaulting to the last 7 days.
+func parseStatsRange(fromParam, toParam string) (time.Time, time.Time, error) {
+	to := time.Now()
+	if toParam != "" {
+		parsed, err := time.Parse(time.RFC3339, toParam)
+		if err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+		to = parsed
+	}
+	from := to.Add(-defaultStatsRange)
+	if fromParam != "" {
+		parsed, err := time.Parse(time.RFC3339, fromParam)
+		if err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+		from = parsed
+	}
+	return from, to, nil
+}
@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/lucasfarolfi/hire.me/infrastructure/repository"
+	"github.com/lucasfarolfi/hire.me/internal/dto"
+	"github.com/lucasfarolfi/hire.me/internal/entity"
+	"github.com/lucasfarolfi/hire.me/internal/service"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLinkStatsHandlerIntegration_GetStatsByAlias(t *testing.T) {
+	t.Run("Given an alias that was accessed, when the API receives the GET stats request, then it should return the link statistics", func(t *testing.T) {
+		db := loadDB(t)
+		service := service.NewURLShortenerService(repository.NewShortenedURLRepository(db),
+			service.WithClickEventRepository(repository.NewClickEventRepository(db)))
+		handler := NewURLShortenerHandler(service)
+
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET /u/{alias}", handler.RetrieveByAlias)
+		mux.HandleFunc("GET /u/{alias}/stats", handler.GetStatsByAlias)
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		alias := "abc123"
+		db.Create(&entity.ShortenedURL{Alias: alias, Url: "http://www.bemobi.com.br"})
+		for i := 0; i < 3; i++ {
+			req, err := http.NewRequest(http.MethodGet, server.URL+"/u/"+alias, nil)
+			assert.NoError(t, err)
+			req.Header.Set("Referer", "http://www.google.com")
+			req.Header.Set("User-Agent", "Mozilla/5.0 (X11; Linux x86_64; rv:121.0) Gecko/20100101 Firefox/121.0")
+			resp, err := noRedirectClient().Do(req)
+			assert.NoError(t, err)
+			resp.Body.Close()
+		}
+
+		resp, err := http.Get(server.URL + "/u/" + alias + "/stats?interval=hour&from=" + time.Now().Add(-2*time.Hour).UTC().Format(time.RFC3339))
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var response dto.LinkStatsDTO
+		err = json.NewDecoder(resp.Body).Decode(&response)
+		assert.NoError(t, err)
+
+		assert.Equal(t, alias, response.Alias)
+		assert.Equal(t, int64(3), response.TotalClicks)
+		assert.Equal(t, int64(3), response.RangeClicks)
+		assert.Equal(t, int64(1), response.UniqueVisitors)
+		assert.Equal(t, "hour", response.Interval)
+		assert.Len(t, response.Clicks, 3, "There should be one bucket per hour of the range")
+		assert.Equal(t, int64(3), response.Clicks[len(response.Clicks)-1].Clicks, "All clicks should be in the current hour")
+		assert.Equal(t, []dto.ClickCountDTO{{Value: "http://www.google.com", Count: 3}}, response.TopReferrers)
+		assert.Equal(t, []dto.ClickCountDTO{{Value: "Firefox", Count: 3}}, response.UserAgentFamilies)
+	})
+
+	t.Run("Given a non-existing alias, when the API receives the GET stats request, then it should return a custom error response", func(t *testing.T) {
+		db := loadDB(t)
+		service := service.NewURLShortenerService(repository.NewShortenedURLRepository(db),
+			service.WithClickEventRepository(repository.NewClickEventRepository(db)))
+		handler := NewURLShortenerHandler(service)
+
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET /u/{alias}/stats", handler.GetStatsByAlias)
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		resp, err := http.Get(server.URL + "/u/non-existing/stats")
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+		var response HttpResponseErrorBody
+		err = json.NewDecoder(resp.Body).Decode(&response)
+		assert.NoError(t, err)
+		assert.Equal(t, "002", response.ErrCode)
+	})
+
+	t.Run("Given an invalid interval, when the API receives the GET stats request, then it should return a custom error response", func(t *testing.T) {
+		db := loadDB(t)
+		service := service.NewURLShortenerService(repository.NewShortenedURLRepository(db),
+			service.WithClickEventRepository(repository.NewClickEventRepository(db)))
+		handler := NewURLShortenerHandler(service)
+
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET /u/{alias}/stats", handler.GetStatsByAlias)
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		db.Create(&entity.ShortenedURL{Alias: "abc123", Url: "http://www.bemobi.com.br"})
+
+		resp, err := http.Get(server.URL + "/u/abc123/stats?interval=month")
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+		var response HttpResponseErrorBody
+		err = json.NewDecoder(resp.Body).Decode(&response)
+		assert.NoError(t, err)
+		assert.Equal(t, "007", response.ErrCode)
+		assert.Equal(t, "INVALID STATS QUERY", response.Description)
+	})
+}
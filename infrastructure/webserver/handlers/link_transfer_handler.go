@@ -0,0 +1,210 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+
+	"github.com/lucasfarolfi/hire.me/internal/dto"
+	"github.com/lucasfarolfi/hire.me/internal/service"
+)
+
+// DefaultMaxBulkBodyBytes is the default limit of the bodies of bulk creation and imports.
+const DefaultMaxBulkBodyBytes int64 = 10 << 20
+
+const (
+	contentTypeCSV    = "text/csv"
+	contentTypeNDJSON = "application/x-ndjson"
+)
+
+// WithMaxBulkBodyBytes limits the size of the bodies of bulk creation and imports.
+func WithMaxBulkBodyBytes(limit int64) HandlerOption {
+	return func(h *URLShortenerHandler) {
+		h.maxBulkBodyBytes = limit
+	}
+}
+
+// BulkCreateLinks handles POST /api/v1/links/bulk, creating the links of a JSON array or an NDJSON stream
+// of CreateLinkRequestDTO items. Every item gets its own result, so one invalid item does not fail the
+// others.
+func (h *URLShortenerHandler) BulkCreateLinks(w http.ResponseWriter, r *http.Request) {
+	if !acceptsJSON(r) {
+		writeErrorResponse(w, errNotAcceptable, "")
+		return
+	}
+	requests, err := h.decodeBulkBody(w, r)
+	if err != nil {
+		writeErrorResponse(w, err, "")
+		return
+	}
+	if !chargeItems(w, r, len(requests)) {
+		return
+	}
+
+	owner := ownerOptions(r)
+	res := dto.BulkLinksDTO{Results: make([]dto.BulkLinkResultDTO, len(requests))}
+	items := make([]service.BulkItem, 0, len(requests))
+	indexes := make([]int, 0, len(requests))
+	for i, request := range requests {
+		res.Results[i] = dto.BulkLinkResultDTO{Index: i, Alias: request.Alias}
+		opts, err := createOptions(&request)
+		if err != nil {
+			setBulkError(&res.Results[i], err)
+			continue
+		}
+		items = append(items, service.BulkItem{Alias: request.Alias, URL: request.URL, Options: append(opts, owner...)})
+		indexes = append(indexes, i)
+	}
+
+	svc, ok := h.scope(w, r)
+	if !ok {
+		return
+	}
+	for i, result := range svc.BulkCreate(items) {
+		itemResult := &res.Results[indexes[i]]
+		if result.Err != nil {
+			setBulkError(itemResult, result.Err)
+			continue
+		}
+		itemResult.Alias = result.ShortenedURL.Alias
+		itemResult.ShortURL = h.shortURL(r, svc.Tenant(), result.ShortenedURL.Alias)
+		itemResult.Reused = result.Reused
+	}
+	for _, result := range res.Results {
+		switch {
+		case result.ErrCode != "":
+			res.Failed++
+		case result.Reused:
+			res.Reused++
+		default:
+			res.Created++
+		}
+	}
+	writeJSON(w, http.StatusOK, res)
+}
+
+func setBulkError(result *dto.BulkLinkResultDTO, err error) {
+	response, ok := lookupErrorResponse(err)
+	if !ok {
+		log.Println("Failed to create shortened URL in bulk:", err)
+		response = errorResponse{errCode: "500", description: "INTERNAL ERROR"}
+	}
+	result.ErrCode = response.errCode
+	result.Description = response.description
+}
+
+// decodeBulkBody reads the items of a JSON array or NDJSON body of at most maxBulkBodyBytes.
+func (h *URLShortenerHandler) decodeBulkBody(w http.ResponseWriter, r *http.Request) ([]dto.CreateLinkRequestDTO, error) {
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil || (mediaType != "application/json" && mediaType != contentTypeNDJSON) {
+		return nil, errUnsupportedMediaType
+	}
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, h.maxBulkBodyBytes))
+	decoder.DisallowUnknownFields()
+
+	var requests []dto.CreateLinkRequestDTO
+	if mediaType == "application/json" {
+		err = decoder.Decode(&requests)
+		if err == nil {
+			if _, err = decoder.Token(); errors.Is(err, io.EOF) {
+				err = nil
+			} else {
+				err = errInvalidRequestBody
+			}
+		}
+	} else {
+		for {
+			var request dto.CreateLinkRequestDTO
+			if err = decoder.Decode(&request); err != nil {
+				if errors.Is(err, io.EOF) {
+					err = nil
+				}
+				break
+			}
+			requests = append(requests, request)
+		}
+	}
+	return requests, bodyError(err)
+}
+
+// ExportLinks handles GET /api/v1/admin/links/export, streaming every link as CSV or NDJSON (the
+// default) according to the format parameter.
+func (h *URLShortenerHandler) ExportLinks(w http.ResponseWriter, r *http.Request) {
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = service.TransferFormatNDJSON
+	}
+	contentType := contentTypeNDJSON
+	switch format {
+	case service.TransferFormatCSV:
+		contentType = contentTypeCSV
+	case service.TransferFormatNDJSON:
+	default:
+		writeErrorResponse(w, service.ErrInvalidTransferFormat, "")
+		return
+	}
+
+	svc, ok := h.scope(w, r)
+	if !ok {
+		return
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", `attachment; filename="links.`+format+`"`)
+	if _, err := svc.ExportLinks(w, format); err != nil {
+		// The status line was already sent, so the truncated body is all the client can see.
+		log.Println("Failed to export shortened URLs:", err)
+	}
+}
+
+// ImportLinks handles POST /api/v1/admin/links/import, storing the links of a CSV or NDJSON body with
+// the conflict policy of the on_conflict parameter (fail by default).
+func (h *URLShortenerHandler) ImportLinks(w http.ResponseWriter, r *http.Request) {
+	if !acceptsJSON(r) {
+		writeErrorResponse(w, errNotAcceptable, "")
+		return
+	}
+	var format string
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	switch mediaType {
+	case contentTypeCSV:
+		format = service.TransferFormatCSV
+	case contentTypeNDJSON:
+		format = service.TransferFormatNDJSON
+	default:
+		writeErrorResponse(w, errUnsupportedMediaType, "")
+		return
+	}
+	onConflict := r.URL.Query().Get("on_conflict")
+	if onConflict == "" {
+		onConflict = service.ConflictFail
+	}
+
+	svc, ok := h.scope(w, r)
+	if !ok {
+		return
+	}
+	summary, err := svc.ImportLinks(http.MaxBytesReader(w, r.Body, h.maxBulkBodyBytes), format, onConflict)
+	if err != nil {
+		var alias string
+		var importErr *service.ImportError
+		if errors.As(err, &importErr) {
+			alias = importErr.Alias
+		}
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			err = errRequestBodyTooLarge
+		}
+		if !writeErrorResponse(w, err, alias) {
+			http.Error(w, "failed to import shortened URLs", http.StatusInternalServerError)
+		}
+		return
+	}
+	writeJSON(w, http.StatusOK, dto.ImportSummaryDTO{
+		Created:     summary.Created,
+		Overwritten: summary.Overwritten,
+		Skipped:     summary.Skipped,
+	})
+}
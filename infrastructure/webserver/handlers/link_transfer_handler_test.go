@@ -0,0 +1,152 @@
+package handlers
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/lucasfarolfi/hire.me/internal/dto"
+	"github.com/lucasfarolfi/hire.me/internal/service"
+	"github.com/stretchr/testify/assert"
+)
+
+// sendTransferRequest sends a bulk, export or import request with the admin key of the app.
+func sendTransferRequest(t *testing.T, app *testApp, method, path, contentType, body string) *http.Response {
+	req, err := http.NewRequest(method, app.server.URL+path, strings.NewReader(body))
+	assert.NoError(t, err)
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	req.Header.Set("Authorization", "Bearer "+app.adminKey)
+	resp, err := http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp
+}
+
+func TestLinksHandlerIntegration_BulkCreateLinks(t *testing.T) {
+	for _, tc := range []struct{ contentType, body string }{
+		{"application/json", `[{"url": "http://www.bemobi.com.br", "alias": "bemobi"}, {"url": "not a url"}, {"url": "http://www.example.com", "alias": "bemobi"}, {"url": "http://www.example.com", "ttl": "soon"}, {"url": "http://www.example.com"}]`},
+		{"application/x-ndjson", "{\"url\": \"http://www.bemobi.com.br\", \"alias\": \"bemobi\"}\n{\"url\": \"not a url\"}\n{\"url\": \"http://www.example.com\", \"alias\": \"bemobi\"}\n{\"url\": \"http://www.example.com\", \"ttl\": \"soon\"}\n{\"url\": \"http://www.example.com\"}\n"},
+	} {
+		t.Run("Given a "+tc.contentType+" body, when the API receives the request, then it should create the valid links and report every failed one", func(t *testing.T) {
+			app := newTestApp(t)
+
+			resp := sendTransferRequest(t, app, http.MethodPost, "/api/v1/links/bulk", tc.contentType, tc.body)
+
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+			var response dto.BulkLinksDTO
+			assert.NoError(t, json.NewDecoder(resp.Body).Decode(&response))
+			assert.Equal(t, 2, response.Created)
+			assert.Equal(t, 0, response.Reused)
+			assert.Equal(t, 3, response.Failed)
+			assert.Len(t, response.Results, 5)
+			assert.Equal(t, app.server.URL+"/u/bemobi", response.Results[0].ShortURL)
+			assert.Equal(t, "011", response.Results[1].ErrCode)
+			assert.Equal(t, "001", response.Results[2].ErrCode)
+			assert.Equal(t, "004", response.Results[3].ErrCode)
+			assert.Equal(t, 4, response.Results[4].Index)
+			assert.NotEmpty(t, response.Results[4].Alias)
+			assert.Empty(t, response.Results[4].ErrCode)
+		})
+	}
+
+	t.Run("Given a malformed body, when the API receives the request, then it should create nothing and return an invalid body error", func(t *testing.T) {
+		app := newTestApp(t)
+
+		resp := sendTransferRequest(t, app, http.MethodPost, "/api/v1/links/bulk", "application/x-ndjson",
+			"{\"url\": \"http://www.bemobi.com.br\", \"alias\": \"bemobi\"}\n{\"url\": ")
+
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		assert.Equal(t, "019", decodeErrCode(t, resp))
+		resp = sendLinkRequest(t, app, http.MethodGet, "bemobi", "")
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+
+	t.Run("Given deduplication and an already shortened URL, when the API receives a bulk request for it, then it should count the item as reused instead of created", func(t *testing.T) {
+		app := newTestApp(t, withServiceOptions(service.WithDeduplication()))
+
+		resp := sendTransferRequest(t, app, http.MethodPost, "/api/v1/links/bulk", "application/json",
+			`[{"url": "http://www.bemobi.com.br"}, {"url": "http://www.bemobi.com.br"}, {"url": "http://www.example.com"}]`)
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		var response dto.BulkLinksDTO
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&response))
+		assert.Equal(t, 2, response.Created)
+		assert.Equal(t, 1, response.Reused)
+		assert.Equal(t, 0, response.Failed)
+		assert.True(t, response.Results[1].Reused)
+		assert.Equal(t, response.Results[0].Alias, response.Results[1].Alias)
+	})
+
+	t.Run("Given a body above the bulk limit, when the API receives the request, then it should return a body too large error", func(t *testing.T) {
+		app := newTestApp(t, withHandlerOptions(WithMaxBulkBodyBytes(16)))
+
+		resp := sendTransferRequest(t, app, http.MethodPost, "/api/v1/links/bulk", "application/json", `[{"url": "http://www.bemobi.com.br"}]`)
+
+		assert.Equal(t, http.StatusRequestEntityTooLarge, resp.StatusCode)
+		assert.Equal(t, "018", decodeErrCode(t, resp))
+	})
+}
+
+func TestLinksHandlerIntegration_ExportAndImportLinks(t *testing.T) {
+	t.Run("Given stored links, when they are exported and imported into another server, then the other server should redirect them", func(t *testing.T) {
+		source := newTestApp(t)
+		postLink(t, source, "application/json", `{"url": "http://www.bemobi.com.br", "alias": "bemobi"}`)
+
+		resp := sendTransferRequest(t, source, http.MethodGet, "/api/v1/admin/links/export?format=csv", "", "")
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "text/csv", resp.Header.Get("Content-Type"))
+		exported, err := io.ReadAll(resp.Body)
+		assert.NoError(t, err)
+		assert.True(t, strings.HasPrefix(string(exported), "alias,url,"))
+
+		target := newTestApp(t)
+		resp = sendTransferRequest(t, target, http.MethodPost, "/api/v1/admin/links/import", "text/csv", string(exported))
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		var summary dto.ImportSummaryDTO
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&summary))
+		assert.Equal(t, dto.ImportSummaryDTO{Created: 1}, summary)
+
+		resp = sendLinkRequest(t, target, http.MethodGet, "bemobi", "")
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+
+	t.Run("Given an existing alias, when it is imported without a conflict policy, then it should return an import conflict error", func(t *testing.T) {
+		app := newTestApp(t)
+		postLink(t, app, "application/json", `{"url": "http://www.bemobi.com.br", "alias": "bemobi"}`)
+		record := `{"alias": "bemobi", "url": "http://www.example.com"}`
+
+		resp := sendTransferRequest(t, app, http.MethodPost, "/api/v1/admin/links/import", "application/x-ndjson", record)
+		assert.Equal(t, http.StatusConflict, resp.StatusCode)
+		var response HttpResponseErrorBody
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&response))
+		assert.Equal(t, "027", response.ErrCode)
+		assert.Equal(t, "bemobi", response.Alias)
+
+		resp = sendTransferRequest(t, app, http.MethodPost, "/api/v1/admin/links/import?on_conflict=skip", "application/x-ndjson", record)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+
+	t.Run("Given invalid transfer parameters, when the API receives the request, then it should return the matching error", func(t *testing.T) {
+		app := newTestApp(t)
+
+		resp := sendTransferRequest(t, app, http.MethodGet, "/api/v1/admin/links/export?format=xml", "", "")
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		assert.Equal(t, "024", decodeErrCode(t, resp))
+
+		resp = sendTransferRequest(t, app, http.MethodPost, "/api/v1/admin/links/import?on_conflict=merge", "text/csv", "alias,url\n")
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		assert.Equal(t, "025", decodeErrCode(t, resp))
+
+		resp = sendTransferRequest(t, app, http.MethodPost, "/api/v1/admin/links/import", "application/json", "[]")
+		assert.Equal(t, http.StatusUnsupportedMediaType, resp.StatusCode)
+		assert.Equal(t, "017", decodeErrCode(t, resp))
+
+		resp = sendTransferRequest(t, app, http.MethodPost, "/api/v1/admin/links/import", "text/csv", "alias\nbemobi\n")
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		assert.Equal(t, "026", decodeErrCode(t, resp))
+	})
+}
@@ -0,0 +1,310 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lucasfarolfi/hire.me/internal/dto"
+	"github.com/lucasfarolfi/hire.me/internal/entity"
+	"github.com/lucasfarolfi/hire.me/internal/service"
+)
+
+// DefaultMaxBodyBytes is the default limit of the request bodies accepted by the API.
+const DefaultMaxBodyBytes int64 = 64 << 10
+
+var errUnsupportedMediaType = fmt.Errorf("request body must be application/json")
+var errRequestBodyTooLarge = fmt.Errorf("request body too large")
+var errInvalidRequestBody = fmt.Errorf("invalid request body")
+var errNotAcceptable = fmt.Errorf("response can only be application/json")
+
+// CreateLink handles POST /api/v1/links, creating a shortened URL from a JSON body.
+func (h *URLShortenerHandler) CreateLink(w http.ResponseWriter, r *http.Request) {
+	if !acceptsJSON(r) {
+		writeErrorResponse(w, errNotAcceptable, "")
+		return
+	}
+	var request dto.CreateLinkRequestDTO
+	if err := h.decodeJSONBody(w, r, &request); err != nil {
+		writeErrorResponse(w, err, "")
+		return
+	}
+	if request.URL == "" {
+		writeErrorResponse(w, errInvalidRequestBody, request.Alias)
+		return
+	}
+
+	svc, ok := h.scope(w, r)
+	if !ok {
+		return
+	}
+	created, reused, ok := h.createShortenedURL(w, r, svc, &request)
+	if !ok {
+		return
+	}
+	res := dto.NewLinkDTO(created, h.shortURL(r, svc.Tenant(), created.Alias))
+	res.Reused = reused
+
+	status := http.StatusCreated
+	if reused {
+		status = http.StatusOK
+	}
+	w.Header().Set("Location", "/api/v1/links/"+created.Alias)
+	writeJSON(w, status, res)
+}
+
+// ListLinks handles GET /api/v1/links, browsing the shortened URLs with filters, a sort and cursor
+// pagination. Keys that are not admin only see the links of their owner.
+func (h *URLShortenerHandler) ListLinks(w http.ResponseWriter, r *http.Request) {
+	h.listLinks(w, r, (*service.URLShortenerService).ListLinks)
+}
+
+// ListFlaggedLinks handles GET /api/v1/admin/links/flagged, listing for review the shortened URLs whose
+// destination was found on the malicious URL blocklist, with the parameters of ListLinks.
+func (h *URLShortenerHandler) ListFlaggedLinks(w http.ResponseWriter, r *http.Request) {
+	h.listLinks(w, r, (*service.URLShortenerService).ListFlaggedLinks)
+}
+
+func (h *URLShortenerHandler) listLinks(w http.ResponseWriter, r *http.Request,
+	list func(svc *service.URLShortenerService, query entity.LinkQuery, cursor string) (*service.LinkPage, error)) {
+	if !acceptsJSON(r) {
+		writeErrorResponse(w, errNotAcceptable, "")
+		return
+	}
+	linkQuery, err := parseLinkQuery(r)
+	if err != nil {
+		writeErrorResponse(w, service.ErrInvalidLinkQuery, "")
+		return
+	}
+	if key := apiKeyFromContext(r.Context()); key != nil && !key.Admin {
+		linkQuery.OwnerID = key.OwnerID
+	}
+
+	svc, ok := h.scope(w, r)
+	if !ok {
+		return
+	}
+	page, err := list(svc, linkQuery, r.URL.Query().Get("cursor"))
+	if err != nil {
+		if !writeErrorResponse(w, err, "") {
+			http.Error(w, "failed to list shortened URLs", http.StatusInternalServerError)
+		}
+		return
+	}
+	res := dto.LinkPageDTO{Links: make([]dto.LinkDTO, 0, len(page.Links)), NextCursor: page.NextCursor}
+	for i := range page.Links {
+		res.Links = append(res.Links, *dto.NewLinkDTO(&page.Links[i], h.shortURL(r, svc.Tenant(), page.Links[i].Alias)))
+	}
+	writeJSON(w, http.StatusOK, res)
+}
+
+// parseLinkQuery reads the alias_prefix, domain, created_from and created_to (RFC 3339), min_clicks,
+// limit and sort parameters. The sort is a field name, prefixed with - for descending order.
+func parseLinkQuery(r *http.Request) (entity.LinkQuery, error) {
+	query := r.URL.Query()
+	linkQuery := entity.LinkQuery{
+		AliasPrefix: query.Get("alias_prefix"),
+		Domain:      query.Get("domain"),
+	}
+	if sort := query.Get("sort"); sort != "" {
+		linkQuery.SortBy, linkQuery.Descending = strings.CutPrefix(sort, "-")
+	}
+	if value := query.Get("created_from"); value != "" {
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return linkQuery, err
+		}
+		linkQuery.CreatedFrom = &t
+	}
+	if value := query.Get("created_to"); value != "" {
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return linkQuery, err
+		}
+		linkQuery.CreatedTo = &t
+	}
+	if value := query.Get("min_clicks"); value != "" {
+		minClicks, err := strconv.ParseInt(value, 10, 32)
+		if err != nil {
+			return linkQuery, err
+		}
+		linkQuery.MinAccessTimes = int32(minClicks)
+	}
+	if value := query.Get("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil {
+			return linkQuery, err
+		}
+		if limit <= 0 {
+			return linkQuery, service.ErrInvalidLinkQuery
+		}
+		linkQuery.Limit = limit
+	}
+	return linkQuery, nil
+}
+
+// GetLink handles GET /api/v1/links/{alias}, describing the shortened URL without counting an access.
+func (h *URLShortenerHandler) GetLink(w http.ResponseWriter, r *http.Request) {
+	alias := r.PathValue("alias")
+	if !acceptsJSON(r) {
+		writeErrorResponse(w, errNotAcceptable, alias)
+		return
+	}
+	svc, ok := h.scope(w, r)
+	if !ok || !h.authorize(w, r, svc, alias) {
+		return
+	}
+	shortUrl, err := svc.GetByAlias(alias)
+	if err != nil {
+		if !writeErrorResponse(w, err, alias) {
+			http.Error(w, "failed to retrieve shortened URL", http.StatusInternalServerError)
+		}
+		return
+	}
+	writeJSON(w, http.StatusOK, dto.NewLinkDTO(shortUrl, h.shortURL(r, svc.Tenant(), shortUrl.Alias)))
+}
+
+// UpdateLink handles PATCH /api/v1/links/{alias}, changing the destination, expiration or redirect
+// type of the shortened URL. An empty expires_at removes the expiration.
+func (h *URLShortenerHandler) UpdateLink(w http.ResponseWriter, r *http.Request) {
+	alias := r.PathValue("alias")
+	if !acceptsJSON(r) {
+		writeErrorResponse(w, errNotAcceptable, alias)
+		return
+	}
+	svc, ok := h.scope(w, r)
+	if !ok || !h.authorize(w, r, svc, alias) {
+		return
+	}
+	var request dto.UpdateLinkRequestDTO
+	if err := h.decodeJSONBody(w, r, &request); err != nil {
+		writeErrorResponse(w, err, alias)
+		return
+	}
+	if request.URL == nil && request.RedirectType == nil && request.ExpiresAt == nil && request.TTL == nil {
+		writeErrorResponse(w, errInvalidRequestBody, alias)
+		return
+	}
+
+	var url string
+	if request.URL != nil {
+		if *request.URL == "" {
+			writeErrorResponse(w, errInvalidRequestBody, alias)
+			return
+		}
+		url = *request.URL
+	}
+	var opts []service.CreateOption
+	if request.RedirectType != nil {
+		opts = append(opts, service.WithRedirectType(*request.RedirectType))
+	}
+	if request.ExpiresAt != nil && *request.ExpiresAt == "" && request.TTL == nil {
+		opts = append(opts, service.WithoutExpiration())
+	} else if request.ExpiresAt != nil || request.TTL != nil {
+		expiresAt, err := parseExpiration(valueOrEmpty(request.ExpiresAt), valueOrEmpty(request.TTL))
+		if err != nil || expiresAt == nil {
+			writeErrorResponse(w, service.ErrInvalidExpiration, alias)
+			return
+		}
+		opts = append(opts, service.WithExpiresAt(*expiresAt))
+	}
+
+	updated, err := svc.Update(alias, url, opts...)
+	if err != nil {
+		if !writeErrorResponse(w, err, alias) {
+			http.Error(w, "failed to update shortened URL", http.StatusInternalServerError)
+		}
+		return
+	}
+	writeJSON(w, http.StatusOK, dto.NewLinkDTO(updated, h.shortURL(r, svc.Tenant(), updated.Alias)))
+}
+
+// DeleteLink handles DELETE /api/v1/links/{alias}, soft deleting the shortened URL.
+func (h *URLShortenerHandler) DeleteLink(w http.ResponseWriter, r *http.Request) {
+	alias := r.PathValue("alias")
+	svc, ok := h.scope(w, r)
+	if !ok || !h.authorize(w, r, svc, alias) {
+		return
+	}
+	if err := svc.Delete(alias); err != nil {
+		if !writeErrorResponse(w, err, alias) {
+			http.Error(w, "failed to delete shortened URL", http.StatusInternalServerError)
+		}
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// decodeJSONBody decodes a single JSON value from an application/json body of at most maxBodyBytes,
+// rejecting unknown fields.
+func (h *URLShortenerHandler) decodeJSONBody(w http.ResponseWriter, r *http.Request, v any) error {
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil || mediaType != "application/json" {
+		return errUnsupportedMediaType
+	}
+
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, h.maxBodyBytes))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		return bodyError(err)
+	}
+	if _, err := decoder.Token(); !errors.Is(err, io.EOF) {
+		return errInvalidRequestBody
+	}
+	return nil
+}
+
+// bodyError maps an error reading a request body to errRequestBodyTooLarge or errInvalidRequestBody.
+func bodyError(err error) error {
+	if err == nil {
+		return nil
+	}
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		return errRequestBodyTooLarge
+	}
+	return errInvalidRequestBody
+}
+
+// acceptsJSON reports whether the Accept header, when present, allows an application/json response.
+func acceptsJSON(r *http.Request) bool {
+	accept := r.Header.Get("Accept")
+	if accept == "" {
+		return true
+	}
+	for _, accepted := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(accepted))
+		if err != nil {
+			continue
+		}
+		if mediaType != "application/json" && mediaType != "application/*" && mediaType != "*/*" {
+			continue
+		}
+		if q, err := strconv.ParseFloat(params["q"], 64); err == nil && q == 0 {
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+func valueOrEmpty(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		http.Error(w, "failed to encode response", http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,264 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/lucasfarolfi/hire.me/internal/dto"
+	"github.com/stretchr/testify/assert"
+)
+
+// postLink creates a link through the JSON API with the admin key of the app.
+func postLink(t *testing.T, app *testApp, contentType, body string) *http.Response {
+	req, err := http.NewRequest(http.MethodPost, app.server.URL+"/api/v1/links", strings.NewReader(body))
+	assert.NoError(t, err)
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("Authorization", "Bearer "+app.adminKey)
+	resp, err := http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp
+}
+
+// sendLinkRequest sends a request for the link of the alias with the admin key of the app.
+func sendLinkRequest(t *testing.T, app *testApp, method, alias, body string) *http.Response {
+	return sendAuthenticatedRequest(t, app.server, app.adminKey, method, "/api/v1/links/"+alias, body)
+}
+
+func decodeErrCode(t *testing.T, resp *http.Response) string {
+	var response HttpResponseErrorBody
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&response))
+	return response.ErrCode
+}
+
+func TestLinksHandlerIntegration_CreateLink(t *testing.T) {
+	t.Run("Given a JSON body, when the API receives the request, then it should create the link and describe it", func(t *testing.T) {
+		app := newTestApp(t)
+
+		resp := postLink(t, app, "application/json; charset=utf-8",
+			`{"url": "http://www.bemobi.com.br", "alias": "bemobi", "redirect_type": 301, "ttl": "24h", "max_access_times": 5}`)
+
+		assert.Equal(t, http.StatusCreated, resp.StatusCode)
+		assert.Equal(t, "/api/v1/links/bemobi", resp.Header.Get("Location"))
+
+		var response dto.LinkDTO
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&response))
+		assert.Equal(t, "bemobi", response.Alias)
+		assert.Equal(t, "http://www.bemobi.com.br", response.URL)
+		assert.Equal(t, app.server.URL+"/u/bemobi", response.ShortURL)
+		assert.Equal(t, http.StatusMovedPermanently, response.RedirectType)
+		assert.NotNil(t, response.ExpiresAt)
+		assert.Equal(t, int32(5), *response.MaxAccessTimes)
+	})
+
+	t.Run("Given a body that is not JSON, when the API receives the request, then it should return an unsupported media type error", func(t *testing.T) {
+		app := newTestApp(t)
+
+		resp := postLink(t, app, "application/x-www-form-urlencoded", "url=http://www.bemobi.com.br")
+
+		assert.Equal(t, http.StatusUnsupportedMediaType, resp.StatusCode)
+		assert.Equal(t, "017", decodeErrCode(t, resp))
+	})
+
+	t.Run("Given a body above the limit, when the API receives the request, then it should return a body too large error", func(t *testing.T) {
+		app := newTestApp(t, withHandlerOptions(WithMaxBodyBytes(64)))
+
+		resp := postLink(t, app, "application/json", `{"url": "http://www.bemobi.com.br/`+strings.Repeat("a", 64)+`"}`)
+
+		assert.Equal(t, http.StatusRequestEntityTooLarge, resp.StatusCode)
+		assert.Equal(t, "018", decodeErrCode(t, resp))
+	})
+
+	t.Run("Given malformed bodies, when the API receives the requests, then it should return an invalid body error", func(t *testing.T) {
+		app := newTestApp(t)
+
+		for _, body := range []string{
+			`{"url": `,
+			`{"url": "http://www.bemobi.com.br", "unknown": true}`,
+			`{"url": "http://www.bemobi.com.br"} {"url": "http://www.bemobi.com.br"}`,
+			`{"alias": "bemobi"}`,
+		} {
+			resp := postLink(t, app, "application/json", body)
+
+			assert.Equal(t, http.StatusBadRequest, resp.StatusCode, body)
+			assert.Equal(t, "019", decodeErrCode(t, resp), body)
+		}
+	})
+
+	t.Run("Given an invalid option, when the API receives the request, then it should return the same error code as the legacy endpoint", func(t *testing.T) {
+		app := newTestApp(t)
+
+		resp := postLink(t, app, "application/json", `{"url": "http://www.bemobi.com.br", "expires_at": "2000-01-01T00:00:00Z"}`)
+
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		assert.Equal(t, "004", decodeErrCode(t, resp))
+
+		resp = postLink(t, app, "application/json", `{"url": "http://www.bemobi.com.br", "redirect_type": 200}`)
+
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		assert.Equal(t, "023", decodeErrCode(t, resp))
+	})
+
+	t.Run("Given a client that does not accept JSON, when the API receives the request, then it should return a not acceptable error", func(t *testing.T) {
+		app := newTestApp(t)
+
+		req, err := http.NewRequest(http.MethodPost, app.server.URL+"/api/v1/links", strings.NewReader(`{"url": "http://www.bemobi.com.br"}`))
+		assert.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept", "text/html")
+		req.Header.Set("Authorization", "Bearer "+app.adminKey)
+		resp, err := http.DefaultClient.Do(req)
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusNotAcceptable, resp.StatusCode)
+		assert.Equal(t, "020", decodeErrCode(t, resp))
+	})
+}
+
+func TestLinksHandlerIntegration_GetLink(t *testing.T) {
+	t.Run("Given a link created through the API, when it is requested, then it should be described without counting an access", func(t *testing.T) {
+		app := newTestApp(t)
+		postLink(t, app, "application/json", `{"url": "http://www.bemobi.com.br", "alias": "bemobi"}`)
+
+		resp := sendLinkRequest(t, app, http.MethodGet, "bemobi", "")
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		var response dto.LinkDTO
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&response))
+		assert.Equal(t, "http://www.bemobi.com.br", response.URL)
+		assert.Equal(t, int32(0), response.AccessTimes)
+		assert.False(t, response.CreatedAt.IsZero())
+	})
+
+	t.Run("Given a non-existing alias, when it is requested, then it should return a not found error", func(t *testing.T) {
+		app := newTestApp(t)
+
+		resp := sendLinkRequest(t, app, http.MethodGet, "missing", "")
+
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+		assert.Equal(t, "002", decodeErrCode(t, resp))
+	})
+
+	t.Run("Given a link created through the API, when the legacy route is requested, then it should redirect to it", func(t *testing.T) {
+		app := newTestApp(t)
+		postLink(t, app, "application/json", `{"url": "http://www.bemobi.com.br", "alias": "bemobi"}`)
+
+		client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }}
+		resp, err := client.Get(app.server.URL + "/u/bemobi")
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusFound, resp.StatusCode)
+		assert.Equal(t, "http://www.bemobi.com.br", resp.Header.Get("Location"))
+	})
+}
+
+func TestLinksHandlerIntegration_UpdateLink(t *testing.T) {
+	t.Run("Given an existing link, when it is patched, then it should return the link with the changed fields", func(t *testing.T) {
+		app := newTestApp(t)
+		postLink(t, app, "application/json", `{"url": "http://www.bemobi.com.br", "alias": "bemobi", "ttl": "1h"}`)
+
+		resp := sendLinkRequest(t, app, http.MethodPatch, "bemobi", `{"url": "http://www.bemobi.com.br/fixed", "redirect_type": 308, "expires_at": ""}`)
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		var response dto.LinkDTO
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&response))
+		assert.Equal(t, "http://www.bemobi.com.br/fixed", response.URL)
+		assert.Equal(t, http.StatusPermanentRedirect, response.RedirectType)
+		assert.Nil(t, response.ExpiresAt)
+	})
+
+	t.Run("Given invalid patches, when the API receives them, then it should return custom error responses", func(t *testing.T) {
+		app := newTestApp(t)
+		postLink(t, app, "application/json", `{"url": "http://www.bemobi.com.br", "alias": "bemobi"}`)
+
+		for body, errCode := range map[string]string{
+			`{}`:                          "019",
+			`{"url": ""}`:                 "019",
+			`{"ttl": "-1h"}`:              "004",
+			`{"url": "ftp://bemobi.com"}`: "011",
+			`{"redirect_type": 200}`:      "023",
+		} {
+			resp := sendLinkRequest(t, app, http.MethodPatch, "bemobi", body)
+
+			assert.Equal(t, http.StatusBadRequest, resp.StatusCode, body)
+			assert.Equal(t, errCode, decodeErrCode(t, resp), body)
+		}
+
+		resp := sendLinkRequest(t, app, http.MethodPatch, "missing", `{"url": "http://www.bemobi.com.br"}`)
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+}
+
+func TestLinksHandlerIntegration_DeleteLink(t *testing.T) {
+	t.Run("Given an existing link, when it is deleted, then it should stop resolving and its alias should stay reserved", func(t *testing.T) {
+		app := newTestApp(t)
+		postLink(t, app, "application/json", `{"url": "http://www.bemobi.com.br", "alias": "bemobi"}`)
+
+		resp := sendLinkRequest(t, app, http.MethodDelete, "bemobi", "")
+		assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+		resp, err := http.Get(app.server.URL + "/u/bemobi")
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusGone, resp.StatusCode)
+		assert.Equal(t, "021", decodeErrCode(t, resp))
+
+		resp = sendLinkRequest(t, app, http.MethodDelete, "bemobi", "")
+		assert.Equal(t, http.StatusGone, resp.StatusCode)
+
+		resp = postLink(t, app, "application/json", `{"url": "http://www.example.com", "alias": "bemobi"}`)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		assert.Equal(t, "001", decodeErrCode(t, resp))
+	})
+}
+
+func TestLinksHandlerIntegration_ListLinks(t *testing.T) {
+	t.Run("Given several links, when they are listed with filters and a small limit, then it should page through the matches", func(t *testing.T) {
+		app := newTestApp(t)
+		for _, body := range []string{
+			`{"url": "http://www.bemobi.com.br/a", "alias": "bemobi-a"}`,
+			`{"url": "http://www.bemobi.com.br/b", "alias": "bemobi-b"}`,
+			`{"url": "http://www.bemobi.com.br/c", "alias": "bemobi-c"}`,
+			`{"url": "http://www.example.com", "alias": "example"}`,
+		} {
+			postLink(t, app, "application/json", body)
+		}
+
+		list := func(query string) dto.LinkPageDTO {
+			resp := sendAuthenticatedRequest(t, app.server, app.adminKey, http.MethodGet, "/api/v1/links?"+query, "")
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+			var page dto.LinkPageDTO
+			assert.NoError(t, json.NewDecoder(resp.Body).Decode(&page))
+			return page
+		}
+
+		first := list("domain=bemobi.com.br&sort=alias&limit=2")
+		assert.Len(t, first.Links, 2)
+		assert.Equal(t, "bemobi-a", first.Links[0].Alias)
+		assert.Equal(t, app.server.URL+"/u/bemobi-a", first.Links[0].ShortURL)
+		assert.NotEmpty(t, first.NextCursor)
+
+		second := list("domain=bemobi.com.br&sort=alias&limit=2&cursor=" + first.NextCursor)
+		assert.Len(t, second.Links, 1)
+		assert.Equal(t, "bemobi-c", second.Links[0].Alias)
+		assert.Empty(t, second.NextCursor)
+
+		newest := list("")
+		assert.Equal(t, "example", newest.Links[0].Alias, "Links should be listed newest first by default")
+	})
+
+	t.Run("Given invalid parameters, when links are listed, then it should return an invalid link query error", func(t *testing.T) {
+		app := newTestApp(t)
+
+		for _, query := range []string{"sort=url", "limit=0", "min_clicks=many", "created_from=yesterday", "cursor=abc"} {
+			resp := sendAuthenticatedRequest(t, app.server, app.adminKey, http.MethodGet, "/api/v1/links?"+query, "")
+
+			assert.Equal(t, http.StatusBadRequest, resp.StatusCode, query)
+			assert.Equal(t, "022", decodeErrCode(t, resp), query)
+		}
+	})
+}
@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/lucasfarolfi/hire.me/infrastructure/ratelimit"
+)
+
+var errRateLimited = errors.New("rate limit exceeded")
+
+// RateLimiter throttles routes with the token buckets of a ratelimit.Store: authenticated requests take
+// from the bucket of their API key and the others from the bucket of their client IP.
+type RateLimiter struct {
+	store ratelimit.Store
+}
+
+func NewRateLimiter(store ratelimit.Store) *RateLimiter {
+	return &RateLimiter{store: store}
+}
+
+// Limit returns a middleware applying limit to the routes it wraps. Routes wrapped with the same name share
+// the buckets of their clients. It must run after Authenticate to tell API keys apart.
+func (l *RateLimiter) Limit(name string, limit ratelimit.Limit) func(http.HandlerFunc) http.HandlerFunc {
+	return l.limit(limit, clientKey(name))
+}
+
+// LimitByIP works like Limit but always takes from the bucket of the client IP, so it can run before
+// Authenticate and throttle requests with missing or invalid API keys.
+func (l *RateLimiter) LimitByIP(name string, limit ratelimit.Limit) func(http.HandlerFunc) http.HandlerFunc {
+	return l.limit(limit, func(r *http.Request) string {
+		return name + ":ip:" + clientIP(r)
+	})
+}
+
+// LimitItems works like Limit but takes one token per item of the request instead of one per request.
+// The handlers of the routes it wraps charge the items with chargeItems once they have decoded them.
+func (l *RateLimiter) LimitItems(name string, limit ratelimit.Limit) func(http.HandlerFunc) http.HandlerFunc {
+	key := clientKey(name)
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			charge := func(w http.ResponseWriter, r *http.Request, n int) bool {
+				return l.take(w, r, key(r), limit, n)
+			}
+			next(w, r.WithContext(context.WithValue(r.Context(), itemChargeContextKey{}, charge)))
+		}
+	}
+}
+
+type itemChargeContextKey struct{}
+
+// chargeItems takes n tokens from the bucket of the route limited by LimitItems, writing the rate limit
+// error when they are not available. Routes without the limit are always allowed.
+func chargeItems(w http.ResponseWriter, r *http.Request, n int) bool {
+	charge, ok := r.Context().Value(itemChargeContextKey{}).(func(http.ResponseWriter, *http.Request, int) bool)
+	if !ok {
+		return true
+	}
+	return charge(w, r, n)
+}
+
+func (l *RateLimiter) limit(limit ratelimit.Limit, key func(r *http.Request) string) func(http.HandlerFunc) http.HandlerFunc {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			if l.take(w, r, key(r), limit, 1) {
+				next(w, r)
+			}
+		}
+	}
+}
+
+// take removes n tokens from the bucket of key, writing the rate limit error when they are not available.
+func (l *RateLimiter) take(w http.ResponseWriter, r *http.Request, key string, limit ratelimit.Limit, n int) bool {
+	allowed, retryAfter, err := l.store.Take(key, limit, n)
+	if err != nil {
+		// Failing open keeps the app serving when a shared store is unavailable.
+		log.Println("Failed to check the rate limit, letting the request through:", err)
+		return true
+	}
+	if !allowed {
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+		writeErrorResponse(w, errRateLimited, r.PathValue("alias"))
+		return false
+	}
+	return true
+}
+
+// clientKey keys the buckets of name by the API key of authenticated requests and by the client IP of
+// the others.
+func clientKey(name string) func(r *http.Request) string {
+	return func(r *http.Request) string {
+		if key := apiKeyFromContext(r.Context()); key != nil {
+			return name + ":key:" + strconv.Itoa(key.ID)
+		}
+		return name + ":ip:" + clientIP(r)
+	}
+}
@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/lucasfarolfi/hire.me/infrastructure/ratelimit"
+	"github.com/stretchr/testify/assert"
+)
+
+// testRateLimits limits the create and redirect routes of the app to two requests per minute and the
+// guarded routes to five requests per minute per client ip, with the buckets of the store.
+func testRateLimits(store ratelimit.Store) testAppOption {
+	return withRouterOptions(WithRateLimits(NewRateLimiter(store), RateLimits{
+		Create:   ratelimit.Limit{Requests: 2, Period: time.Minute},
+		Redirect: ratelimit.Limit{Requests: 2, Period: time.Minute},
+		API:      ratelimit.Limit{Requests: 5, Period: time.Minute},
+	}))
+}
+
+type failingRateLimitStore struct{}
+
+func (failingRateLimitStore) Take(string, ratelimit.Limit, int) (bool, time.Duration, error) {
+	return false, 0, errors.New("store unavailable")
+}
+
+func TestRateLimiterIntegration(t *testing.T) {
+	t.Run("Given a key over its limit, when it creates links on any create route, then it should return a rate limit error", func(t *testing.T) {
+		app := newTestApp(t, testRateLimits(ratelimit.NewMemoryStore(100)))
+		server, alice := app.server, app.issueKey(t, 0, "alice", false)
+		resp := sendAuthenticatedRequest(t, server, alice, http.MethodPost, "/?url=http://www.bemobi.com.br", "")
+		assert.Equal(t, http.StatusCreated, resp.StatusCode)
+		resp = sendAuthenticatedRequest(t, server, alice, http.MethodPost, "/api/v1/links", `{"url": "http://www.google.com"}`)
+		assert.Equal(t, http.StatusCreated, resp.StatusCode)
+
+		resp = sendAuthenticatedRequest(t, server, alice, http.MethodPost, "/api/v1/links", `{"url": "http://www.google.com"}`)
+
+		assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+		retryAfter, err := strconv.Atoi(resp.Header.Get("Retry-After"))
+		assert.NoError(t, err)
+		assert.Equal(t, 30, retryAfter)
+		assert.Equal(t, "032", decodeErrCode(t, resp))
+	})
+
+	t.Run("Given a bulk body with more links than the create limit, when it is created, then it should return a rate limit error", func(t *testing.T) {
+		app := newTestApp(t, testRateLimits(ratelimit.NewMemoryStore(100)))
+		server, alice := app.server, app.issueKey(t, 0, "alice", false)
+		body := `[{"url": "http://www.google.com"}, {"url": "http://www.bemobi.com.br"}, {"url": "http://www.github.com"}]`
+
+		resp := sendAuthenticatedRequest(t, server, alice, http.MethodPost, "/api/v1/links/bulk", body)
+
+		assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+		assert.NotEmpty(t, resp.Header.Get("Retry-After"))
+		assert.Equal(t, "032", decodeErrCode(t, resp))
+		resp = sendAuthenticatedRequest(t, server, alice, http.MethodPost, "/api/v1/links/bulk", `[{"url": "http://www.google.com"}, {"url": "http://www.bemobi.com.br"}]`)
+		assert.Equal(t, http.StatusOK, resp.StatusCode, "The rejected bulk should not take tokens")
+		resp = sendAuthenticatedRequest(t, server, alice, http.MethodPost, "/api/v1/links", `{"url": "http://www.google.com"}`)
+		assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode, "The bulk links should share the create limit")
+	})
+
+	t.Run("Given a key over its limit, when another key creates a link, then it should be allowed", func(t *testing.T) {
+		app := newTestApp(t, testRateLimits(ratelimit.NewMemoryStore(100)))
+		server, alice, bob := app.server, app.issueKey(t, 0, "alice", false), app.issueKey(t, 0, "bob", false)
+		for i := 0; i < 3; i++ {
+			sendAuthenticatedRequest(t, server, alice, http.MethodPost, "/api/v1/links", `{"url": "http://www.google.com"}`)
+		}
+
+		resp := sendAuthenticatedRequest(t, server, bob, http.MethodPost, "/api/v1/links", `{"url": "http://www.google.com"}`)
+
+		assert.Equal(t, http.StatusCreated, resp.StatusCode)
+	})
+
+	t.Run("Given a client ip over its redirect limit, when it is redirected again, then it should return a rate limit error", func(t *testing.T) {
+		app := newTestApp(t, testRateLimits(ratelimit.NewMemoryStore(100)))
+		server, alice := app.server, app.issueKey(t, 0, "alice", false)
+		resp := sendAuthenticatedRequest(t, server, alice, http.MethodPost, "/api/v1/links", `{"url": "http://www.google.com", "alias": "google"}`)
+		assert.Equal(t, http.StatusCreated, resp.StatusCode)
+		for i := 0; i < 2; i++ {
+			assert.Equal(t, "http://www.google.com", redirectLocation(t, server, "/u/google"))
+		}
+
+		resp = sendAuthenticatedRequest(t, server, "", http.MethodGet, "/u/google", "")
+
+		assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+		assert.NotEmpty(t, resp.Header.Get("Retry-After"))
+		assert.Equal(t, "032", decodeErrCode(t, resp))
+	})
+
+	t.Run("Given a client ip over its api limit, when it keeps sending invalid keys, then it should return a rate limit error instead of unauthorized", func(t *testing.T) {
+		server := newTestApp(t, testRateLimits(ratelimit.NewMemoryStore(100))).server
+		for i := 0; i < 5; i++ {
+			resp := sendAuthenticatedRequest(t, server, "hk_invalid", http.MethodPost, "/api/v1/links", `{"url": "http://www.google.com"}`)
+			assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+		}
+
+		resp := sendAuthenticatedRequest(t, server, "hk_invalid", http.MethodPost, "/api/v1/links", `{"url": "http://www.google.com"}`)
+
+		assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+		assert.NotEmpty(t, resp.Header.Get("Retry-After"))
+		assert.Equal(t, "032", decodeErrCode(t, resp))
+	})
+
+	t.Run("Given an unavailable store, when links are created, then the requests should be let through", func(t *testing.T) {
+		app := newTestApp(t, testRateLimits(failingRateLimitStore{}))
+		server, alice := app.server, app.issueKey(t, 0, "alice", false)
+
+		for i := 0; i < 3; i++ {
+			resp := sendAuthenticatedRequest(t, server, alice, http.MethodPost, "/api/v1/links", `{"url": "http://www.google.com"}`)
+			assert.Equal(t, http.StatusCreated, resp.StatusCode)
+		}
+	})
+}
@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/lucasfarolfi/hire.me/infrastructure/ratelimit"
+)
+
+// RouterOption customizes the middlewares of the routes served by NewRouter.
+type RouterOption func(r *router)
+
+type router struct {
+	authenticate  func(http.HandlerFunc) http.HandlerFunc
+	requireAdmin  func(http.HandlerFunc) http.HandlerFunc
+	limitCreate   func(http.HandlerFunc) http.HandlerFunc
+	limitBulk     func(http.HandlerFunc) http.HandlerFunc
+	limitRedirect func(http.HandlerFunc) http.HandlerFunc
+	limitAPI      func(http.HandlerFunc) http.HandlerFunc
+}
+
+// WithAuthenticator guards every route but the redirects and the rankings with the API keys of the
+// authenticator. Without it anyone can create and manage links.
+func WithAuthenticator(auth *Authenticator) RouterOption {
+	return func(r *router) {
+		r.authenticate, r.requireAdmin = auth.Authenticate, auth.RequireAdmin
+	}
+}
+
+// RateLimits are the limits applied to the routes by WithRateLimits.
+type RateLimits struct {
+	// Create is shared by every route creating links, per API key or client IP. Bulk creations take one
+	// token per link.
+	Create ratelimit.Limit
+	// Redirect limits the redirects per client IP.
+	Redirect ratelimit.Limit
+	// API limits every route guarded by the authenticator per client IP. It is checked before the API key,
+	// so requests with missing or invalid keys are throttled too.
+	API ratelimit.Limit
+}
+
+// WithRateLimits applies the limits with the buckets of the limiter.
+func WithRateLimits(limiter *RateLimiter, limits RateLimits) RouterOption {
+	return func(r *router) {
+		r.limitCreate = limiter.Limit("create", limits.Create)
+		r.limitBulk = limiter.LimitItems("create", limits.Create)
+		r.limitRedirect = limiter.Limit("redirect", limits.Redirect)
+		r.limitAPI = limiter.LimitByIP("api", limits.API)
+	}
+}
+
+// NewRouter serves the routes of the handler.
+func NewRouter(h *URLShortenerHandler, opts ...RouterOption) http.Handler {
+	r := &router{
+		authenticate:  passThrough,
+		requireAdmin:  passThrough,
+		limitCreate:   passThrough,
+		limitBulk:     passThrough,
+		limitRedirect: passThrough,
+		limitAPI:      passThrough,
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	// The API limit runs before authentication, so floods of invalid keys cannot skip it, and the create
+	// limit after it, so that requests with an API key use the bucket of the key.
+	authenticate := func(next http.HandlerFunc) http.HandlerFunc {
+		return r.limitAPI(r.authenticate(next))
+	}
+	requireAdmin := func(next http.HandlerFunc) http.HandlerFunc {
+		return r.limitAPI(r.requireAdmin(next))
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /", authenticate(r.limitCreate(h.Create)))
+	mux.HandleFunc("GET /u/{alias}", r.limitRedirect(h.RetrieveByAlias))
+	mux.HandleFunc("GET /u/{alias}/stats", authenticate(h.GetStatsByAlias))
+	mux.HandleFunc("GET /most_acessed", h.GetMostAcessedUrls)
+	mux.HandleFunc("GET /t/{tenant}/u/{alias}", r.limitRedirect(h.RetrieveByAlias))
+	mux.HandleFunc("GET /t/{tenant}/most_acessed", h.GetMostAcessedUrls)
+	mux.HandleFunc("POST /api/v1/links", authenticate(r.limitCreate(h.CreateLink)))
+	mux.HandleFunc("GET /api/v1/links", authenticate(h.ListLinks))
+	mux.HandleFunc("POST /api/v1/links/bulk", authenticate(r.limitBulk(h.BulkCreateLinks)))
+	mux.HandleFunc("GET /api/v1/links/{alias}", authenticate(h.GetLink))
+	mux.HandleFunc("PATCH /api/v1/links/{alias}", authenticate(h.UpdateLink))
+	mux.HandleFunc("DELETE /api/v1/links/{alias}", authenticate(h.DeleteLink))
+	mux.HandleFunc("GET /api/v1/links/{alias}/stats", authenticate(h.GetStatsByAlias))
+	mux.HandleFunc("GET /api/v1/admin/links/export", requireAdmin(h.ExportLinks))
+	mux.HandleFunc("POST /api/v1/admin/links/import", requireAdmin(h.ImportLinks))
+	mux.HandleFunc("GET /api/v1/admin/links/flagged", requireAdmin(h.ListFlaggedLinks))
+	return mux
+}
+
+// passThrough is the middleware of routes left unguarded or unlimited.
+func passThrough(next http.HandlerFunc) http.HandlerFunc {
+	return next
+}
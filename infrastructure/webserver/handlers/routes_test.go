@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/lucasfarolfi/hire.me/infrastructure/repository"
+	"github.com/lucasfarolfi/hire.me/internal/service"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+// testApp wires the application like main does, serving NewRouter with API key authentication, tenants
+// and domains over a fresh database. adminKey is an admin key of the default tenant.
+type testApp struct {
+	server   *httptest.Server
+	adminKey string
+	db       *gorm.DB
+	apiKeys  *service.APIKeyService
+	tenants  *service.TenantService
+	domains  *service.DomainService
+}
+
+type testAppOption func(c *testAppConfig)
+
+type testAppConfig struct {
+	serviceOpts []service.ServiceOption
+	handlerOpts []HandlerOption
+	routerOpts  []RouterOption
+}
+
+// withServiceOptions adds options to the URL shortener service of the app.
+func withServiceOptions(opts ...service.ServiceOption) testAppOption {
+	return func(c *testAppConfig) {
+		c.serviceOpts = append(c.serviceOpts, opts...)
+	}
+}
+
+// withHandlerOptions adds options to the handler of the app.
+func withHandlerOptions(opts ...HandlerOption) testAppOption {
+	return func(c *testAppConfig) {
+		c.handlerOpts = append(c.handlerOpts, opts...)
+	}
+}
+
+// withRouterOptions adds options to the router of the app, after the authenticator.
+func withRouterOptions(opts ...RouterOption) testAppOption {
+	return func(c *testAppConfig) {
+		c.routerOpts = append(c.routerOpts, opts...)
+	}
+}
+
+func newTestApp(t *testing.T, opts ...testAppOption) *testApp {
+	var config testAppConfig
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	db := loadDB(t)
+	app := &testApp{
+		db:      db,
+		apiKeys: service.NewAPIKeyService(repository.NewAPIKeyRepository(db)),
+		tenants: service.NewTenantService(repository.NewTenantRepository(db)),
+		domains: service.NewDomainService(repository.NewDomainRepository(db), service.DefaultDomainRefreshInterval),
+	}
+	serviceOpts := append([]service.ServiceOption{service.WithClickEventRepository(repository.NewClickEventRepository(db))},
+		config.serviceOpts...)
+	handlerOpts := append([]HandlerOption{WithTenants(app.tenants), WithDomains(app.domains)}, config.handlerOpts...)
+	handler := NewURLShortenerHandler(service.NewURLShortenerService(repository.NewShortenedURLRepository(db), serviceOpts...),
+		handlerOpts...)
+	routerOpts := append([]RouterOption{WithAuthenticator(NewAuthenticator(app.apiKeys))}, config.routerOpts...)
+	app.server = httptest.NewServer(NewRouter(handler, routerOpts...))
+	t.Cleanup(app.server.Close)
+	app.adminKey = app.issueKey(t, 0, "admin", true)
+	return app
+}
+
+// issueKey issues an API key of owner in the tenant, failing the test on errors.
+func (app *testApp) issueKey(t *testing.T, tenantID int, owner string, admin bool) string {
+	_, key, err := app.apiKeys.Issue(tenantID, owner, "", admin)
+	assert.NoError(t, err)
+	return key
+}
+
+func TestRouterIntegration(t *testing.T) {
+	t.Run("Given a router without an authenticator, when a guarded route is requested without a key, then it should let the request through", func(t *testing.T) {
+		db := loadDB(t)
+		handler := NewURLShortenerHandler(service.NewURLShortenerService(repository.NewShortenedURLRepository(db)))
+		server := httptest.NewServer(NewRouter(handler))
+		defer server.Close()
+
+		resp := sendAuthenticatedRequest(t, server, "", http.MethodPost, "/api/v1/links", `{"url": "http://www.bemobi.com.br", "alias": "bemobi"}`)
+		assert.Equal(t, http.StatusCreated, resp.StatusCode)
+		resp = sendAuthenticatedRequest(t, server, "", http.MethodGet, "/api/v1/admin/links/export", "")
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+}
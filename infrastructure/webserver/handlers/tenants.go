@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/lucasfarolfi/hire.me/internal/entity"
+	"github.com/lucasfarolfi/hire.me/internal/service"
+)
+
+// WithTenants resolves the tenants named by the public routes and the tenants of the API keys with the
+// given service. Without it, every request uses the default tenant.
+func WithTenants(tenants *service.TenantService) HandlerOption {
+	return func(h *URLShortenerHandler) {
+		h.tenants = tenants
+	}
+}
+
+// WithDomains maps the custom short domains of the registry to their tenants, both to resolve the Host
+// header of requests and to build short URLs on the canonical domain of each tenant.
+func WithDomains(domains *service.DomainService) HandlerOption {
+	return func(h *URLShortenerHandler) {
+		h.domains = domains
+	}
+}
+
+// scope returns the service scoped to the tenant of the request: the tenant whose slug is the {tenant}
+// path value of the public routes, else the tenant of the API key of the request, else the tenant of the
+// custom domain the request arrived on. On failure it writes the error response and returns false.
+func (h *URLShortenerHandler) scope(w http.ResponseWriter, r *http.Request) (*service.URLShortenerService, bool) {
+	tenant, err := h.requestTenant(r)
+	if err != nil {
+		if !writeErrorResponse(w, err, "") {
+			log.Println("Failed to resolve the tenant of the request:", err)
+			http.Error(w, "failed to resolve tenant", http.StatusInternalServerError)
+		}
+		return nil, false
+	}
+	return h.service.ForTenant(tenant), true
+}
+
+func (h *URLShortenerHandler) requestTenant(r *http.Request) (*entity.Tenant, error) {
+	if slug := r.PathValue("tenant"); slug != "" {
+		if h.tenants == nil {
+			return nil, service.ErrTenantNotFound
+		}
+		return h.tenants.FindBySlug(slug)
+	}
+	if key := apiKeyFromContext(r.Context()); key != nil {
+		return h.tenantByID(key.TenantID)
+	}
+	if h.domains != nil {
+		domain, err := h.domains.Resolve(r.Host)
+		if err != nil {
+			return nil, err
+		}
+		if domain != nil {
+			return h.tenantByID(domain.TenantID)
+		}
+	}
+	return nil, nil
+}
+
+func (h *URLShortenerHandler) tenantByID(id int) (*entity.Tenant, error) {
+	if id == entity.DefaultTenantID {
+		return nil, nil
+	}
+	if h.tenants == nil {
+		return nil, service.ErrTenantNotFound
+	}
+	return h.tenants.FindByID(id)
+}
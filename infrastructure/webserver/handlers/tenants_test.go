@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/lucasfarolfi/hire.me/internal/dto"
+	"github.com/stretchr/testify/assert"
+)
+
+// issueTenantKeys creates the marketing tenant of the app, which may have two links, returning the keys of
+// a default tenant owner and of an owner of the marketing tenant.
+func issueTenantKeys(t *testing.T, app *testApp) (defaultKey, marketingKey string) {
+	maxLinks := 2
+	marketing, err := app.tenants.Create("marketing", "Marketing", &maxLinks)
+	assert.NoError(t, err)
+	return app.issueKey(t, 0, "alice", false), app.issueKey(t, marketing.ID, "alice", false)
+}
+
+func TestTenantsIntegration(t *testing.T) {
+	t.Run("Given the same alias in two tenants, when they are resolved, then each tenant route should redirect to its own url", func(t *testing.T) {
+		app := newTestApp(t)
+		server := app.server
+		defaultKey, marketingKey := issueTenantKeys(t, app)
+		resp := sendAuthenticatedRequest(t, server, defaultKey, http.MethodPost, "/api/v1/links", `{"url": "http://www.bemobi.com.br", "alias": "promo"}`)
+		assert.Equal(t, http.StatusCreated, resp.StatusCode)
+
+		resp = sendAuthenticatedRequest(t, server, marketingKey, http.MethodPost, "/api/v1/links", `{"url": "http://www.google.com", "alias": "promo"}`)
+
+		assert.Equal(t, http.StatusCreated, resp.StatusCode)
+		var link dto.LinkDTO
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&link))
+		assert.Equal(t, server.URL+"/t/marketing/u/promo", link.ShortURL)
+		assert.Equal(t, "http://www.google.com", redirectLocation(t, server, "/t/marketing/u/promo"))
+		assert.Equal(t, "http://www.bemobi.com.br", redirectLocation(t, server, "/u/promo"))
+		resp = sendAuthenticatedRequest(t, server, marketingKey, http.MethodGet, "/t/marketing/most_acessed", "")
+		var ranking []dto.MostAcessedUrlDTO
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&ranking))
+		assert.Len(t, ranking, 1)
+		assert.Equal(t, "http://www.google.com", ranking[0].URL)
+	})
+
+	t.Run("Given a link of another tenant, when a key reads it, then it should not find it", func(t *testing.T) {
+		app := newTestApp(t)
+		server := app.server
+		defaultKey, marketingKey := issueTenantKeys(t, app)
+		resp := sendAuthenticatedRequest(t, server, marketingKey, http.MethodPost, "/api/v1/links", `{"url": "http://www.google.com", "alias": "promo"}`)
+		assert.Equal(t, http.StatusCreated, resp.StatusCode)
+
+		resp = sendAuthenticatedRequest(t, server, defaultKey, http.MethodGet, "/api/v1/links/promo", "")
+
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+		assert.Equal(t, "002", decodeErrCode(t, resp))
+	})
+
+	t.Run("Given a tenant at its quota, when another link is created, then it should return a quota exceeded error", func(t *testing.T) {
+		app := newTestApp(t)
+		server := app.server
+		_, marketingKey := issueTenantKeys(t, app)
+		for _, alias := range []string{"first", "second"} {
+			resp := sendAuthenticatedRequest(t, server, marketingKey, http.MethodPost, "/api/v1/links", `{"url": "http://www.google.com", "alias": "`+alias+`"}`)
+			assert.Equal(t, http.StatusCreated, resp.StatusCode)
+		}
+
+		resp := sendAuthenticatedRequest(t, server, marketingKey, http.MethodPost, "/api/v1/links", `{"url": "http://www.google.com", "alias": "third"}`)
+
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+		assert.Equal(t, "030", decodeErrCode(t, resp))
+	})
+
+	t.Run("Given an unknown tenant, when its route is requested, then it should return a tenant not found error", func(t *testing.T) {
+		server := newTestApp(t).server
+
+		resp := sendAuthenticatedRequest(t, server, "", http.MethodGet, "/t/sales/u/promo", "")
+
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+		assert.Equal(t, "031", decodeErrCode(t, resp))
+	})
+}
+
+func redirectLocation(t *testing.T, server *httptest.Server, path string) string {
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }}
+	resp, err := client.Get(server.URL + path)
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusFound, resp.StatusCode)
+	return resp.Header.Get("Location")
+}
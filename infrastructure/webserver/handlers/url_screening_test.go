@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/lucasfarolfi/hire.me/internal/dto"
+	"github.com/lucasfarolfi/hire.me/internal/service"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestURLScreeningIntegration(t *testing.T) {
+	t.Run("Given a blocked destination, when a link is created with it, then it should return an url blocked error", func(t *testing.T) {
+		blocklist := service.NewURLBlocklist()
+		assert.NoError(t, blocklist.Load(strings.NewReader("0.0.0.0 evil.example.com")))
+		app := newTestApp(t, withServiceOptions(service.WithURLScreener(service.NewURLScreener(blocklist))))
+		server, alice := app.server, app.issueKey(t, 0, "alice", false)
+
+		resp := sendAuthenticatedRequest(t, server, alice, http.MethodPost, "/api/v1/links", `{"url": "https://login.evil.example.com"}`)
+
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		assert.Equal(t, "033", decodeErrCode(t, resp))
+	})
+
+	t.Run("Given a link whose destination was blocked later, when it is resolved, then it should return a link flagged error and be listed for admins", func(t *testing.T) {
+		screener := service.NewURLScreener(nil)
+		app := newTestApp(t, withServiceOptions(service.WithURLScreener(screener)))
+		server, alice, admin := app.server, app.issueKey(t, 0, "alice", false), app.issueKey(t, 0, "ops", true)
+		resp := sendAuthenticatedRequest(t, server, alice, http.MethodPost, "/api/v1/links", `{"url": "http://evil.example.com/login", "alias": "promo"}`)
+		assert.Equal(t, http.StatusCreated, resp.StatusCode)
+		blocklist := service.NewURLBlocklist()
+		assert.NoError(t, blocklist.Load(strings.NewReader("evil.example.com/login")))
+		screener.Replace(blocklist)
+
+		resp = sendAuthenticatedRequest(t, server, "", http.MethodGet, "/u/promo", "")
+
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+		assert.Equal(t, "034", decodeErrCode(t, resp))
+		resp = sendAuthenticatedRequest(t, server, admin, http.MethodGet, "/api/v1/admin/links/flagged", "")
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		var page dto.LinkPageDTO
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&page))
+		assert.Len(t, page.Links, 1)
+		assert.Equal(t, "promo", page.Links[0].Alias)
+		assert.Equal(t, "evil.example.com/login", page.Links[0].FlagReason)
+		assert.NotNil(t, page.Links[0].FlaggedAt)
+		resp = sendAuthenticatedRequest(t, server, alice, http.MethodGet, "/api/v1/admin/links/flagged", "")
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	})
+
+	t.Run("Given a tenant link whose destination was blocked later, when it is resolved on the tenant route, then it should return a link flagged error", func(t *testing.T) {
+		screener := service.NewURLScreener(nil)
+		app := newTestApp(t, withServiceOptions(service.WithURLScreener(screener)))
+		_, marketingKey := issueTenantKeys(t, app)
+		resp := sendAuthenticatedRequest(t, app.server, marketingKey, http.MethodPost, "/?url=http://evil.example.com&alias=promo", "")
+		assert.Equal(t, http.StatusCreated, resp.StatusCode)
+		blocklist := service.NewURLBlocklist()
+		assert.NoError(t, blocklist.Load(strings.NewReader("evil.example.com")))
+		screener.Replace(blocklist)
+
+		resp = sendAuthenticatedRequest(t, app.server, "", http.MethodGet, "/t/marketing/u/promo", "")
+
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+		assert.Equal(t, "034", decodeErrCode(t, resp))
+		resp = sendAuthenticatedRequest(t, app.server, marketingKey, http.MethodPost, "/?url=http://evil.example.com/other", "")
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		assert.Equal(t, "033", decodeErrCode(t, resp))
+	})
+}
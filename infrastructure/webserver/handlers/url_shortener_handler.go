@@ -2,57 +2,180 @@ package handlers
 
 import (
 	"encoding/json"
-	"errors"
 	"fmt"
+	"log"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/lucasfarolfi/hire.me/internal/dto"
+	"github.com/lucasfarolfi/hire.me/internal/entity"
 	"github.com/lucasfarolfi/hire.me/internal/service"
-	"gorm.io/gorm"
 )
 
 type URLShortenerHandler struct {
-	service *service.URLShortenerService
+	service             *service.URLShortenerService
+	defaultRedirectType int
+	maxBodyBytes        int64
+	maxBulkBodyBytes    int64
+	tenants             *service.TenantService
+	domains             *service.DomainService
 }
 
-func NewURLShortenerHandler(service *service.URLShortenerService) *URLShortenerHandler {
-	return &URLShortenerHandler{service}
+// HandlerOption customizes an URLShortenerHandler.
+type HandlerOption func(h *URLShortenerHandler)
+
+// WithDefaultRedirectType sets the redirect status used for shortened URLs without their own redirect type.
+func WithDefaultRedirectType(code int) HandlerOption {
+	return func(h *URLShortenerHandler) {
+		h.defaultRedirectType = code
+	}
+}
+
+// WithMaxBodyBytes limits the size of the request bodies read by the handler.
+func WithMaxBodyBytes(limit int64) HandlerOption {
+	return func(h *URLShortenerHandler) {
+		h.maxBodyBytes = limit
+	}
+}
+
+func NewURLShortenerHandler(service *service.URLShortenerService, opts ...HandlerOption) *URLShortenerHandler {
+	h := &URLShortenerHandler{service: service, defaultRedirectType: http.StatusFound,
+		maxBodyBytes: DefaultMaxBodyBytes, maxBulkBodyBytes: DefaultMaxBulkBodyBytes}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
 }
 
 func (h *URLShortenerHandler) Create(w http.ResponseWriter, r *http.Request) {
 	startTime := time.Now()
 
-	url := r.URL.Query().Get("url")
-	alias := r.URL.Query().Get("alias")
-	if url == "" {
+	query := r.URL.Query()
+	request := &dto.CreateLinkRequestDTO{
+		URL:       query.Get("url"),
+		Alias:     query.Get("alias"),
+		ExpiresAt: query.Get("expires_at"),
+		TTL:       query.Get("ttl"),
+	}
+	if request.URL == "" {
 		http.Error(w, "url is required", http.StatusBadRequest)
 		return
 	}
+	if redirectType := query.Get("redirect_type"); redirectType != "" {
+		code, err := strconv.Atoi(redirectType)
+		if err != nil || !entity.IsValidRedirectType(code) {
+			writeErrorResponse(w, service.ErrInvalidRedirectType, request.Alias)
+			return
+		}
+		request.RedirectType = code
+	}
+	if maxAccessTimes := query.Get("max_access_times"); maxAccessTimes != "" {
+		max, err := strconv.ParseInt(maxAccessTimes, 10, 32)
+		if err != nil {
+			writeErrorResponse(w, service.ErrInvalidMaxAccessTimes, request.Alias)
+			return
+		}
+		limit := int32(max)
+		request.MaxAccessTimes = &limit
+	}
 
-	if alias == "" {
-		alias = h.service.GenerateRandomAlias()
-	} else if h.service.ExistsByAlias(alias) {
-		retrieveErrorResponseBody(w, http.StatusBadRequest, "001", "CUSTOM ALIAS ALREADY EXISTS", alias)
+	svc, ok := h.scope(w, r)
+	if !ok {
 		return
 	}
-
-	created, err := h.service.Create(alias, url)
-	if err != nil {
-		http.Error(w, "failed to create shortened URL", http.StatusInternalServerError)
+	created, reused, ok := h.createShortenedURL(w, r, svc, request)
+	if !ok {
 		return
 	}
-	shortenURL := fmt.Sprintf("%s/u/%s", h.getHost(r), created.Alias)
+	shortenURL := h.shortURL(r, svc.Tenant(), created.Alias)
 	durationStr := fmt.Sprintf("%.3fms", float64(time.Since(startTime).Nanoseconds())/1e6)
 	res := dto.NewCreatedShortenedURLDTO(created.Alias, shortenURL, durationStr)
+	res.ExpiresAt = created.ExpiresAt
+	res.MaxAccessTimes = created.MaxAccessTimes
+	res.Reused = reused
 
+	status := http.StatusCreated
+	if reused {
+		status = http.StatusOK
+	}
 	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusCreated)
-	if err = json.NewEncoder(w).Encode(res); err != nil {
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(res); err != nil {
 		http.Error(w, "failed to encode shortener response", http.StatusInternalServerError)
 	}
 }
 
+// createShortenedURL creates the shortened URL described by the request, shared by the legacy query
+// string endpoint and the JSON API. On failure it writes the error response and returns false.
+func (h *URLShortenerHandler) createShortenedURL(w http.ResponseWriter, r *http.Request, svc *service.URLShortenerService, request *dto.CreateLinkRequestDTO) (*entity.ShortenedURL, bool, bool) {
+	opts, err := createOptions(request)
+	if err != nil {
+		writeErrorResponse(w, err, request.Alias)
+		return nil, false, false
+	}
+	opts = append(opts, ownerOptions(r)...)
+
+	created, reused, err := svc.CreateOrReuse(request.Alias, request.URL, opts...)
+	if err != nil {
+		if !writeErrorResponse(w, err, request.Alias) {
+			http.Error(w, "failed to create shortened URL", http.StatusInternalServerError)
+		}
+		return nil, false, false
+	}
+	return created, reused, true
+}
+
+// createOptions converts the optional fields of the request into service options. An unparseable
+// expiration is reported as service.ErrInvalidExpiration.
+func createOptions(request *dto.CreateLinkRequestDTO) ([]service.CreateOption, error) {
+	var opts []service.CreateOption
+	if request.RedirectType != 0 {
+		opts = append(opts, service.WithRedirectType(request.RedirectType))
+	}
+	expiresAt, err := parseExpiration(request.ExpiresAt, request.TTL)
+	if err != nil {
+		return nil, service.ErrInvalidExpiration
+	}
+	if expiresAt != nil {
+		opts = append(opts, service.WithExpiresAt(*expiresAt))
+	}
+	if request.MaxAccessTimes != nil {
+		opts = append(opts, service.WithMaxAccessTimes(*request.MaxAccessTimes))
+	}
+	return opts, nil
+}
+
+// parseExpiration converts the optional expires_at (RFC 3339) or ttl (Go duration or seconds) parameters
+// into an absolute expiration date. Both parameters at once are rejected.
+func parseExpiration(expiresAt, ttl string) (*time.Time, error) {
+	switch {
+	case expiresAt != "" && ttl != "":
+		return nil, fmt.Errorf("expires_at and ttl are mutually exclusive")
+	case expiresAt != "":
+		t, err := time.Parse(time.RFC3339, expiresAt)
+		if err != nil {
+			return nil, err
+		}
+		return &t, nil
+	case ttl != "":
+		duration, err := time.ParseDuration(ttl)
+		if err != nil {
+			seconds, convErr := strconv.Atoi(ttl)
+			if convErr != nil {
+				return nil, err
+			}
+			duration = time.Duration(seconds) * time.Second
+		}
+		if duration <= 0 {
+			return nil, fmt.Errorf("ttl must be positive")
+		}
+		t := time.Now().Add(duration)
+		return &t, nil
+	}
+	return nil, nil
+}
+
 func (h *URLShortenerHandler) getHost(r *http.Request) string {
 	protocol := "http"
 	if r.TLS != nil {
@@ -62,19 +185,60 @@ func (h *URLShortenerHandler) getHost(r *http.Request) string {
 	return fmt.Sprintf("%s://%s", protocol, host)
 }
 
+// shortURL is the public address that redirects to the shortened URL of the alias. It is built on the
+// canonical domain of the tenant when one is registered, and otherwise on the host the request arrived
+// on, under the path prefix of the tenant unless it is the default one.
+func (h *URLShortenerHandler) shortURL(r *http.Request, tenant *entity.Tenant, alias string) string {
+	if domain := h.canonicalDomain(tenant); domain != nil {
+		return fmt.Sprintf("%s://%s/u/%s", domain.Scheme, domain.Host, alias)
+	}
+	if tenant != nil {
+		return fmt.Sprintf("%s/t/%s/u/%s", h.getHost(r), tenant.Slug, alias)
+	}
+	return fmt.Sprintf("%s/u/%s", h.getHost(r), alias)
+}
+
+func (h *URLShortenerHandler) canonicalDomain(tenant *entity.Tenant) *entity.Domain {
+	if h.domains == nil {
+		return nil
+	}
+	tenantID := entity.DefaultTenantID
+	if tenant != nil {
+		tenantID = tenant.ID
+	}
+	domain, err := h.domains.Canonical(tenantID)
+	if err != nil {
+		log.Println("Failed to find the canonical domain of tenant", tenantID, ":", err)
+		return nil
+	}
+	return domain
+}
+
 func (h *URLShortenerHandler) RetrieveByAlias(w http.ResponseWriter, r *http.Request) {
 	alias := r.PathValue("alias")
 	if alias == "" {
 		http.Error(w, "alias is required", http.StatusBadRequest)
 		return
 	}
-	shortUrl, err := h.service.RetrieveByAlias(alias)
+	svc, ok := h.scope(w, r)
+	if !ok {
+		return
+	}
+	click := entity.NewClickEvent(r.Referer(), r.UserAgent(), clientIP(r), r.Header.Get("Accept-Language"))
+	shortUrl, err := svc.RetrieveByAlias(alias, click)
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			retrieveErrorResponseBody(w, http.StatusNotFound, "002", "SHORTENED URL NOT FOUND", alias)
-			return
+		if !writeErrorResponse(w, err, alias) {
+			http.Error(w, "failed to create shortened URL", http.StatusInternalServerError)
 		}
-		http.Error(w, "failed to create shortened URL", http.StatusInternalServerError)
+		return
+	}
+
+	if !wantsJSON(r) {
+		redirectType := shortUrl.RedirectType
+		if redirectType == 0 {
+			redirectType = h.defaultRedirectType
+		}
+		http.Redirect(w, r, shortUrl.Url, redirectType)
 		return
 	}
 
@@ -87,9 +251,21 @@ func (h *URLShortenerHandler) RetrieveByAlias(w http.ResponseWriter, r *http.Req
 }
 
 func (h *URLShortenerHandler) GetMostAcessedUrls(w http.ResponseWriter, r *http.Request) {
-	urls, err := h.service.Get10MostAcessedUrls()
+	limit, offset, since, until, err := parseRankingQuery(r)
 	if err != nil {
-		http.Error(w, "failed to create shortened URL", http.StatusInternalServerError)
+		writeErrorResponse(w, service.ErrInvalidRankingQuery, "")
+		return
+	}
+
+	svc, ok := h.scope(w, r)
+	if !ok {
+		return
+	}
+	urls, err := svc.GetMostAcessedUrls(limit, offset, since, until)
+	if err != nil {
+		if !writeErrorResponse(w, err, "") {
+			http.Error(w, "failed to create shortened URL", http.StatusInternalServerError)
+		}
 		return
 	}
 
@@ -100,3 +276,34 @@ func (h *URLShortenerHandler) GetMostAcessedUrls(w http.ResponseWriter, r *http.
 		http.Error(w, "failed to encode shortener response", http.StatusInternalServerError)
 	}
 }
+
+// parseRankingQuery reads the optional limit (default 10), offset and since/until (RFC 3339) parameters.
+func parseRankingQuery(r *http.Request) (limit, offset int, since, until *time.Time, err error) {
+	query := r.URL.Query()
+	limit = 10
+	if value := query.Get("limit"); value != "" {
+		if limit, err = strconv.Atoi(value); err != nil {
+			return 0, 0, nil, nil, err
+		}
+	}
+	if value := query.Get("offset"); value != "" {
+		if offset, err = strconv.Atoi(value); err != nil {
+			return 0, 0, nil, nil, err
+		}
+	}
+	if value := query.Get("since"); value != "" {
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return 0, 0, nil, nil, err
+		}
+		since = &t
+	}
+	if value := query.Get("until"); value != "" {
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return 0, 0, nil, nil, err
+		}
+		until = &t
+	}
+	return limit, offset, since, until, nil
+}
@@ -7,6 +7,7 @@ import (
 	"net/http/httptest"
 	"net/url"
 	"testing"
+	"time"
 
 	"github.com/lucasfarolfi/hire.me/infrastructure/repository"
 	"github.com/lucasfarolfi/hire.me/internal/dto"
@@ -102,6 +103,216 @@ func TestShortenerHandlerIntegration_Create(t *testing.T) {
 		assert.Equal(t, "001", response.ErrCode, "ErrCode should be '001'")
 		assert.Equal(t, "CUSTOM ALIAS ALREADY EXISTS", response.Description, "Description should indicate the alias already exists")
 	})
+
+	t.Run("Given a valid URL and a redirect type, when the API receives the request, then it should store the redirect type with the shortened URL", func(t *testing.T) {
+		db := loadDB(t)
+		service := service.NewURLShortenerService(repository.NewShortenedURLRepository(db))
+		handler := NewURLShortenerHandler(service)
+
+		server := httptest.NewServer(http.HandlerFunc(handler.Create))
+		defer server.Close()
+
+		params := url.Values{}
+		params.Add("url", "http://www.bemobi.com.br")
+		params.Add("alias", "XYhakR")
+		params.Add("redirect_type", "308")
+		fullUrl := server.URL + "?" + params.Encode()
+
+		resp, err := http.Post(fullUrl, "application/json", nil)
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusCreated, resp.StatusCode)
+
+		var stored entity.ShortenedURL
+		err = db.First(&stored, "alias = ?", "XYhakR").Error
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusPermanentRedirect, stored.RedirectType, "The redirect type should be stored")
+	})
+
+	t.Run("Given a valid URL and a ttl, when the API receives the request, then it should create a shortened URL expiring after the ttl", func(t *testing.T) {
+		db := loadDB(t)
+		service := service.NewURLShortenerService(repository.NewShortenedURLRepository(db))
+		handler := NewURLShortenerHandler(service)
+
+		server := httptest.NewServer(http.HandlerFunc(handler.Create))
+		defer server.Close()
+
+		params := url.Values{}
+		params.Add("url", "http://www.bemobi.com.br")
+		params.Add("ttl", "2h")
+		fullUrl := server.URL + "?" + params.Encode()
+
+		before := time.Now()
+		resp, err := http.Post(fullUrl, "application/json", nil)
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusCreated, resp.StatusCode)
+
+		var response dto.CreatedShortenedURLDTO
+		err = json.NewDecoder(resp.Body).Decode(&response)
+		assert.NoError(t, err)
+
+		assert.NotNil(t, response.ExpiresAt, "The expiration should be returned")
+		assert.WithinDuration(t, before.Add(2*time.Hour), *response.ExpiresAt, time.Minute, "The expiration should be two hours from now")
+	})
+
+	t.Run("Given a valid URL and an invalid ttl, when the API receives the request, then it should return a custom error response", func(t *testing.T) {
+		db := loadDB(t)
+		service := service.NewURLShortenerService(repository.NewShortenedURLRepository(db))
+		handler := NewURLShortenerHandler(service)
+
+		server := httptest.NewServer(http.HandlerFunc(handler.Create))
+		defer server.Close()
+
+		params := url.Values{}
+		params.Add("url", "http://www.bemobi.com.br")
+		params.Add("ttl", "-5m")
+		fullUrl := server.URL + "?" + params.Encode()
+
+		resp, err := http.Post(fullUrl, "application/json", nil)
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+		var response HttpResponseErrorBody
+		err = json.NewDecoder(resp.Body).Decode(&response)
+		assert.NoError(t, err)
+
+		assert.Equal(t, "004", response.ErrCode, "ErrCode should be '004'")
+		assert.Equal(t, "INVALID EXPIRATION", response.Description, "Description should indicate the expiration is invalid")
+	})
+
+	t.Run("Given a valid URL and an expiration date in the past, when the API receives the request, then it should return a custom error response", func(t *testing.T) {
+		db := loadDB(t)
+		service := service.NewURLShortenerService(repository.NewShortenedURLRepository(db))
+		handler := NewURLShortenerHandler(service)
+
+		server := httptest.NewServer(http.HandlerFunc(handler.Create))
+		defer server.Close()
+
+		params := url.Values{}
+		params.Add("url", "http://www.bemobi.com.br")
+		params.Add("expires_at", time.Now().Add(-time.Hour).Format(time.RFC3339))
+		fullUrl := server.URL + "?" + params.Encode()
+
+		resp, err := http.Post(fullUrl, "application/json", nil)
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+		var response HttpResponseErrorBody
+		err = json.NewDecoder(resp.Body).Decode(&response)
+		assert.NoError(t, err)
+
+		assert.Equal(t, "004", response.ErrCode, "ErrCode should be '004'")
+	})
+
+	t.Run("Given a valid URL and an unsupported redirect type, when the API receives the request, then it should return a custom error response", func(t *testing.T) {
+		db := loadDB(t)
+		service := service.NewURLShortenerService(repository.NewShortenedURLRepository(db))
+		handler := NewURLShortenerHandler(service)
+
+		server := httptest.NewServer(http.HandlerFunc(handler.Create))
+		defer server.Close()
+
+		params := url.Values{}
+		params.Add("url", "http://www.bemobi.com.br")
+		params.Add("redirect_type", "200")
+		fullUrl := server.URL + "?" + params.Encode()
+
+		resp, err := http.Post(fullUrl, "application/json", nil)
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		assert.Equal(t, "023", decodeErrCode(t, resp))
+	})
+
+	t.Run("Given a URL with a disallowed scheme, when the API receives the request, then it should return a custom error response", func(t *testing.T) {
+		db := loadDB(t)
+		service := service.NewURLShortenerService(repository.NewShortenedURLRepository(db))
+		handler := NewURLShortenerHandler(service)
+
+		server := httptest.NewServer(http.HandlerFunc(handler.Create))
+		defer server.Close()
+
+		params := url.Values{}
+		params.Add("url", "javascript:alert(document.cookie)")
+		fullUrl := server.URL + "?" + params.Encode()
+
+		resp, err := http.Post(fullUrl, "application/json", nil)
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+		var response HttpResponseErrorBody
+		err = json.NewDecoder(resp.Body).Decode(&response)
+		assert.NoError(t, err)
+
+		assert.Equal(t, "011", response.ErrCode, "ErrCode should be '011'")
+		assert.Equal(t, "URL SCHEME NOT ALLOWED", response.Description)
+	})
+
+	t.Run("Given a reserved custom alias, when the API receives the request, then it should return a custom error response distinct from an existing alias", func(t *testing.T) {
+		db := loadDB(t)
+		service := service.NewURLShortenerService(repository.NewShortenedURLRepository(db))
+		handler := NewURLShortenerHandler(service)
+
+		server := httptest.NewServer(http.HandlerFunc(handler.Create))
+		defer server.Close()
+
+		params := url.Values{}
+		params.Add("url", "http://www.bemobi.com.br")
+		params.Add("alias", "most_acessed")
+		fullUrl := server.URL + "?" + params.Encode()
+
+		resp, err := http.Post(fullUrl, "application/json", nil)
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+		var response HttpResponseErrorBody
+		err = json.NewDecoder(resp.Body).Decode(&response)
+		assert.NoError(t, err)
+
+		assert.Equal(t, "016", response.ErrCode, "ErrCode should be '016'")
+		assert.Equal(t, "most_acessed", response.Alias)
+	})
+
+	t.Run("Given deduplication and an already shortened URL, when the API receives the request, then it should return the existing alias flagged as reused", func(t *testing.T) {
+		db := loadDB(t)
+		service := service.NewURLShortenerService(repository.NewShortenedURLRepository(db), service.WithDeduplication())
+		handler := NewURLShortenerHandler(service)
+
+		server := httptest.NewServer(http.HandlerFunc(handler.Create))
+		defer server.Close()
+
+		params := url.Values{}
+		params.Add("url", "http://www.bemobi.com.br")
+		fullUrl := server.URL + "?" + params.Encode()
+
+		var responses []dto.CreatedShortenedURLDTO
+		for _, expectedStatus := range []int{http.StatusCreated, http.StatusOK} {
+			resp, err := http.Post(fullUrl, "application/json", nil)
+			assert.NoError(t, err)
+			defer resp.Body.Close()
+			assert.Equal(t, expectedStatus, resp.StatusCode)
+
+			var response dto.CreatedShortenedURLDTO
+			assert.NoError(t, json.NewDecoder(resp.Body).Decode(&response))
+			responses = append(responses, response)
+		}
+
+		assert.False(t, responses[0].Reused)
+		assert.True(t, responses[1].Reused)
+		assert.Equal(t, responses[0].Alias, responses[1].Alias)
+	})
 }
 
 func TestShortenerHandlerIntegration_RetrieveByAlias(t *testing.T) {
@@ -119,7 +330,11 @@ func TestShortenerHandlerIntegration_RetrieveByAlias(t *testing.T) {
 		url := "http://www.bemobi.com.br"
 		db.Create(&entity.ShortenedURL{Alias: alias, Url: url})
 
-		resp, err := http.Get(server.URL + "/u/" + alias)
+		req, err := http.NewRequest(http.MethodGet, server.URL+"/u/"+alias, nil)
+		assert.NoError(t, err)
+		req.Header.Set("Accept", "application/json")
+
+		resp, err := http.DefaultClient.Do(req)
 		assert.NoError(t, err)
 		defer resp.Body.Close()
 
@@ -132,6 +347,103 @@ func TestShortenerHandlerIntegration_RetrieveByAlias(t *testing.T) {
 		assert.Equal(t, url, response.URL, "The returned URL should match the stored URL")
 	})
 
+	t.Run("Given a valid alias and the format query set to json, when the API receives the GET request, then it should retrieve the shortened URL", func(t *testing.T) {
+		db := loadDB(t)
+		service := service.NewURLShortenerService(repository.NewShortenedURLRepository(db))
+		handler := NewURLShortenerHandler(service)
+
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET /u/{alias}", handler.RetrieveByAlias)
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		alias := "abc123"
+		url := "http://www.bemobi.com.br"
+		db.Create(&entity.ShortenedURL{Alias: alias, Url: url})
+
+		resp, err := noRedirectClient().Get(server.URL + "/u/" + alias + "?format=json")
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var response dto.ShortenedUrlRetrieveDTO
+		err = json.NewDecoder(resp.Body).Decode(&response)
+		assert.NoError(t, err)
+
+		assert.Equal(t, url, response.URL, "The returned URL should match the stored URL")
+	})
+
+	t.Run("Given a valid alias without a redirect type, when a browser requests it, then it should redirect with the default status", func(t *testing.T) {
+		db := loadDB(t)
+		service := service.NewURLShortenerService(repository.NewShortenedURLRepository(db))
+		handler := NewURLShortenerHandler(service)
+
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET /u/{alias}", handler.RetrieveByAlias)
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		alias := "abc123"
+		url := "http://www.bemobi.com.br"
+		db.Create(&entity.ShortenedURL{Alias: alias, Url: url})
+
+		req, err := http.NewRequest(http.MethodGet, server.URL+"/u/"+alias, nil)
+		assert.NoError(t, err)
+		req.Header.Set("Accept", "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8")
+
+		resp, err := noRedirectClient().Do(req)
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusFound, resp.StatusCode)
+		assert.Equal(t, url, resp.Header.Get("Location"), "Location should point to the stored URL")
+	})
+
+	t.Run("Given a handler with a global redirect type, when an alias without its own redirect type is requested, then it should redirect with the global status", func(t *testing.T) {
+		db := loadDB(t)
+		service := service.NewURLShortenerService(repository.NewShortenedURLRepository(db))
+		handler := NewURLShortenerHandler(service, WithDefaultRedirectType(http.StatusTemporaryRedirect))
+
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET /u/{alias}", handler.RetrieveByAlias)
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		alias := "abc123"
+		url := "http://www.bemobi.com.br"
+		db.Create(&entity.ShortenedURL{Alias: alias, Url: url})
+
+		resp, err := noRedirectClient().Get(server.URL + "/u/" + alias)
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusTemporaryRedirect, resp.StatusCode)
+		assert.Equal(t, url, resp.Header.Get("Location"), "Location should point to the stored URL")
+	})
+
+	t.Run("Given an alias with its own redirect type, when it is requested, then it should redirect with the link status instead of the global one", func(t *testing.T) {
+		db := loadDB(t)
+		service := service.NewURLShortenerService(repository.NewShortenedURLRepository(db))
+		handler := NewURLShortenerHandler(service, WithDefaultRedirectType(http.StatusTemporaryRedirect))
+
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET /u/{alias}", handler.RetrieveByAlias)
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		alias := "abc123"
+		url := "http://www.bemobi.com.br"
+		db.Create(&entity.ShortenedURL{Alias: alias, Url: url, RedirectType: http.StatusMovedPermanently})
+
+		resp, err := noRedirectClient().Get(server.URL + "/u/" + alias)
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusMovedPermanently, resp.StatusCode)
+		assert.Equal(t, url, resp.Header.Get("Location"), "Location should point to the stored URL")
+	})
+
 	t.Run("Given an non-existing alias, when the API receives the GET request, then it should return a custom error response", func(t *testing.T) {
 		db := loadDB(t)
 		service := service.NewURLShortenerService(repository.NewShortenedURLRepository(db))
@@ -159,6 +471,120 @@ func TestShortenerHandlerIntegration_RetrieveByAlias(t *testing.T) {
 	})
 }
 
+func TestShortenerHandlerIntegration_RetrieveByAliasClickEvents(t *testing.T) {
+	t.Run("Given a valid alias, when the API receives the GET request, then it should record a click event with the request details", func(t *testing.T) {
+		db := loadDB(t)
+		service := service.NewURLShortenerService(repository.NewShortenedURLRepository(db),
+			service.WithClickEventRepository(repository.NewClickEventRepository(db)))
+		handler := NewURLShortenerHandler(service)
+
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET /u/{alias}", handler.RetrieveByAlias)
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		alias := "abc123"
+		db.Create(&entity.ShortenedURL{Alias: alias, Url: "http://www.bemobi.com.br"})
+
+		req, err := http.NewRequest(http.MethodGet, server.URL+"/u/"+alias, nil)
+		assert.NoError(t, err)
+		req.Header.Set("Referer", "http://www.google.com")
+		req.Header.Set("User-Agent", "Mozilla/5.0")
+		req.Header.Set("Accept-Language", "pt-BR")
+
+		resp, err := noRedirectClient().Do(req)
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusFound, resp.StatusCode)
+
+		var events []entity.ClickEvent
+		err = db.Find(&events, "alias = ?", alias).Error
+		assert.NoError(t, err)
+		assert.Len(t, events, 1, "A single click event should be recorded")
+		assert.Equal(t, "http://www.google.com", events[0].Referrer)
+		assert.Equal(t, "Mozilla/5.0", events[0].UserAgent)
+		assert.Equal(t, "pt-BR", events[0].AcceptLanguage)
+		assert.Equal(t, "127.0.0.1", events[0].ClientIP)
+
+		var stored entity.ShortenedURL
+		err = db.First(&stored, "alias = ?", alias).Error
+		assert.NoError(t, err)
+		assert.Equal(t, int32(1), stored.AccessTimes, "The access times total should still be kept")
+	})
+}
+
+func TestShortenerHandlerIntegration_RetrieveExpiredByAlias(t *testing.T) {
+	t.Run("Given an expired alias, when the API receives the GET request, then it should return a link expired error response", func(t *testing.T) {
+		db := loadDB(t)
+		service := service.NewURLShortenerService(repository.NewShortenedURLRepository(db))
+		handler := NewURLShortenerHandler(service)
+
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET /u/{alias}", handler.RetrieveByAlias)
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		alias := "abc123"
+		expiredAt := time.Now().Add(-time.Minute)
+		db.Create(&entity.ShortenedURL{Alias: alias, Url: "http://www.bemobi.com.br", ExpiresAt: &expiredAt})
+
+		resp, err := noRedirectClient().Get(server.URL + "/u/" + alias)
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusGone, resp.StatusCode)
+
+		var response HttpResponseErrorBody
+		err = json.NewDecoder(resp.Body).Decode(&response)
+		assert.NoError(t, err)
+
+		assert.Equal(t, "003", response.ErrCode, "ErrCode should be '003'")
+		assert.Equal(t, "LINK EXPIRED", response.Description, "Description should indicate the link has expired")
+		assert.Equal(t, alias, response.Alias)
+	})
+}
+
+func TestShortenerHandlerIntegration_RetrieveLimitedByAlias(t *testing.T) {
+	t.Run("Given an alias created with a max access times, when it is requested more times than allowed, then it should return an access limit error response", func(t *testing.T) {
+		db := loadDB(t)
+		service := service.NewURLShortenerService(repository.NewShortenedURLRepository(db))
+		handler := NewURLShortenerHandler(service)
+
+		mux := http.NewServeMux()
+		mux.HandleFunc("POST /", handler.Create)
+		mux.HandleFunc("GET /u/{alias}", handler.RetrieveByAlias)
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		params := url.Values{}
+		params.Add("url", "http://www.bemobi.com.br")
+		params.Add("alias", "once")
+		params.Add("max_access_times", "1")
+		resp, err := http.Post(server.URL+"?"+params.Encode(), "application/json", nil)
+		assert.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusCreated, resp.StatusCode)
+
+		resp, err = noRedirectClient().Get(server.URL + "/u/once")
+		assert.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusFound, resp.StatusCode, "The first access should be redirected")
+
+		resp, err = noRedirectClient().Get(server.URL + "/u/once")
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusGone, resp.StatusCode)
+
+		var response HttpResponseErrorBody
+		err = json.NewDecoder(resp.Body).Decode(&response)
+		assert.NoError(t, err)
+
+		assert.Equal(t, "005", response.ErrCode, "ErrCode should be '005'")
+		assert.Equal(t, "ACCESS LIMIT REACHED", response.Description, "Description should indicate the access limit was reached")
+	})
+}
+
 func TestShortenerHandlerIntegration_CreatexRetrieve(t *testing.T) {
 	t.Run("Given a valid alias and a url, when create is called followed by retrieve endpoint, then it should receive the shorten URL and redirect to the full URL", func(t *testing.T) {
 		db := loadDB(t)
@@ -189,18 +615,13 @@ func TestShortenerHandlerIntegration_CreatexRetrieve(t *testing.T) {
 		err = json.NewDecoder(resp.Body).Decode(&createResBody)
 		assert.NoError(t, err)
 
-		// Now get the full URL using the shorten url retrieved by create
-		resp, err = http.Get(createResBody.URL)
+		// Now follow the shorten url retrieved by create
+		resp, err = noRedirectClient().Get(createResBody.URL)
 		assert.NoError(t, err)
 		defer resp.Body.Close()
 
-		assert.Equal(t, http.StatusOK, resp.StatusCode)
-
-		var retrieveResBody dto.ShortenedUrlRetrieveDTO
-		err = json.NewDecoder(resp.Body).Decode(&retrieveResBody)
-		assert.NoError(t, err)
-
-		assert.Equal(t, urlToShort, retrieveResBody.URL, "The returned URL should match the stored URL")
+		assert.Equal(t, http.StatusFound, resp.StatusCode)
+		assert.Equal(t, urlToShort, resp.Header.Get("Location"), "The redirect should point to the stored URL")
 	})
 }
 
@@ -213,21 +634,21 @@ func TestShortenerHandlerIntegration_GetMostAcessedUrls(t *testing.T) {
 		alias2 := "ABcdeF"
 		service.Create(alias2, "http://www.bemobi.com.br")
 		for i := 0; i < 3; i++ {
-			_, err := service.RetrieveByAlias(alias2)
+			_, err := service.RetrieveByAlias(alias2, nil)
 			assert.NoError(t, err)
 		}
 
 		alias3 := "123abc"
 		service.Create(alias3, "http://www.example.com")
 		for i := 0; i < 2; i++ {
-			_, err := service.RetrieveByAlias(alias3)
+			_, err := service.RetrieveByAlias(alias3, nil)
 			assert.NoError(t, err)
 		}
 
 		alias1 := "XYhakR"
 		service.Create(alias1, "http://www.abcde.com.br")
 		for i := 0; i < 5; i++ {
-			_, err := service.RetrieveByAlias(alias1)
+			_, err := service.RetrieveByAlias(alias1, nil)
 			assert.NoError(t, err)
 		}
 
@@ -254,13 +675,84 @@ func TestShortenerHandlerIntegration_GetMostAcessedUrls(t *testing.T) {
 		assert.Equal(t, 3, resBody[1].AccessTimes, "The access times for the second most accessed URL should be 3")
 		assert.Equal(t, "http://www.example.com", resBody[2].URL, "The third most accessed URL should be third")
 		assert.Equal(t, 2, resBody[2].AccessTimes, "The access times for the third most accessed URL should be 2")
+		assert.Equal(t, alias3, resBody[2].Alias, "The alias should be returned")
+		assert.False(t, resBody[2].CreatedAt.IsZero(), "The creation date should be returned")
 	})
+
+	t.Run("Given a limit, an offset and a time window, when GetMostAcessedUrls is called, then it should return the requested page of the window ranking", func(t *testing.T) {
+		db := loadDB(t)
+		service := service.NewURLShortenerService(repository.NewShortenedURLRepository(db),
+			service.WithClickEventRepository(repository.NewClickEventRepository(db)))
+		handler := NewURLShortenerHandler(service)
+
+		for i, alias := range []string{"first", "second", "third"} {
+			service.Create(alias, "http://www.bemobi.com.br")
+			for j := 0; j < 3-i; j++ {
+				_, err := service.RetrieveByAlias(alias, entity.NewClickEvent("", "", "127.0.0.1", ""))
+				assert.NoError(t, err)
+			}
+		}
+
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET /most_acessed", handler.GetMostAcessedUrls)
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		params := url.Values{}
+		params.Add("limit", "1")
+		params.Add("offset", "1")
+		params.Add("since", time.Now().Add(-time.Hour).Format(time.RFC3339))
+		resp, err := http.Get(server.URL + "/most_acessed?" + params.Encode())
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var resBody []dto.MostAcessedUrlDTO
+		err = json.NewDecoder(resp.Body).Decode(&resBody)
+		assert.NoError(t, err)
+
+		assert.Len(t, resBody, 1, "Only one URL should be returned")
+		assert.Equal(t, "second", resBody[0].Alias, "The second most accessed URL should be returned")
+		assert.Equal(t, 2, resBody[0].AccessTimes)
+	})
+
+	t.Run("Given a limit above the maximum, when GetMostAcessedUrls is called, then it should return a custom error response", func(t *testing.T) {
+		db := loadDB(t)
+		service := service.NewURLShortenerService(repository.NewShortenedURLRepository(db))
+		handler := NewURLShortenerHandler(service)
+
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET /most_acessed", handler.GetMostAcessedUrls)
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		resp, err := http.Get(server.URL + "/most_acessed?limit=1000")
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+		var response HttpResponseErrorBody
+		err = json.NewDecoder(resp.Body).Decode(&response)
+		assert.NoError(t, err)
+		assert.Equal(t, "008", response.ErrCode, "ErrCode should be '008'")
+		assert.Equal(t, "INVALID RANKING QUERY", response.Description)
+	})
+}
+
+func noRedirectClient() *http.Client {
+	return &http.Client{
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
 }
 
 func loadDB(t *testing.T) *gorm.DB {
-	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{TranslateError: true, NowFunc: func() time.Time { return time.Now().UTC() }})
 	assert.NoError(t, err)
-	err = db.AutoMigrate(&entity.ShortenedURL{})
+	err = db.AutoMigrate(&entity.ShortenedURL{}, &entity.ClickEvent{}, &entity.APIKey{}, &entity.Tenant{}, &entity.Domain{})
 	assert.NoError(t, err)
 	return db
 }
@@ -0,0 +1,58 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/lucasfarolfi/hire.me/internal/entity"
+	"github.com/lucasfarolfi/hire.me/internal/service"
+)
+
+type LinkStatsDTO struct {
+	Alias             string            `json:"alias"`
+	TotalClicks       int64             `json:"total_clicks"`
+	From              time.Time         `json:"from"`
+	To                time.Time         `json:"to"`
+	Interval          string            `json:"interval"`
+	RangeClicks       int64             `json:"range_clicks"`
+	UniqueVisitors    int64             `json:"unique_visitors"`
+	Clicks            []ClicksBucketDTO `json:"clicks"`
+	TopReferrers      []ClickCountDTO   `json:"top_referrers"`
+	UserAgentFamilies []ClickCountDTO   `json:"user_agent_families"`
+}
+
+type ClicksBucketDTO struct {
+	Start  time.Time `json:"start"`
+	Clicks int64     `json:"clicks"`
+}
+
+type ClickCountDTO struct {
+	Value string `json:"value"`
+	Count int64  `json:"count"`
+}
+
+func NewLinkStatsDTO(stats *service.LinkStats) *LinkStatsDTO {
+	clicks := make([]ClicksBucketDTO, 0, len(stats.Buckets))
+	for _, bucket := range stats.Buckets {
+		clicks = append(clicks, ClicksBucketDTO{Start: bucket.Start, Clicks: bucket.Clicks})
+	}
+	return &LinkStatsDTO{
+		Alias:             stats.Alias,
+		TotalClicks:       stats.TotalClicks,
+		From:              stats.From,
+		To:                stats.To,
+		Interval:          stats.Interval,
+		RangeClicks:       stats.RangeClicks,
+		UniqueVisitors:    stats.UniqueVisitors,
+		Clicks:            clicks,
+		TopReferrers:      newClickCountsDTO(stats.TopReferrers),
+		UserAgentFamilies: newClickCountsDTO(stats.UserAgentFamilies),
+	}
+}
+
+func newClickCountsDTO(counts []entity.ClickCount) []ClickCountDTO {
+	dto := make([]ClickCountDTO, 0, len(counts))
+	for _, count := range counts {
+		dto = append(dto, ClickCountDTO{Value: count.Value, Count: count.Count})
+	}
+	return dto
+}
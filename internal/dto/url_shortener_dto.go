@@ -1,11 +1,99 @@
 package dto
 
-import "github.com/lucasfarolfi/hire.me/internal/entity"
+import (
+	"time"
+
+	"github.com/lucasfarolfi/hire.me/internal/entity"
+)
+
+// CreateLinkRequestDTO is the body of POST /api/v1/links. The legacy POST / endpoint fills it from
+// the query string.
+type CreateLinkRequestDTO struct {
+	URL            string `json:"url"`
+	Alias          string `json:"alias,omitempty"`
+	RedirectType   int    `json:"redirect_type,omitempty"`
+	ExpiresAt      string `json:"expires_at,omitempty"`
+	TTL            string `json:"ttl,omitempty"`
+	MaxAccessTimes *int32 `json:"max_access_times,omitempty"`
+}
+
+// UpdateLinkRequestDTO is the body of PATCH /api/v1/links/{alias}. Omitted fields are left unchanged.
+type UpdateLinkRequestDTO struct {
+	URL          *string `json:"url,omitempty"`
+	RedirectType *int    `json:"redirect_type,omitempty"`
+	ExpiresAt    *string `json:"expires_at,omitempty"`
+	TTL          *string `json:"ttl,omitempty"`
+}
+
+// LinkDTO is the shortened URL resource returned by the /api/v1/links endpoints.
+type LinkDTO struct {
+	Alias          string     `json:"alias"`
+	URL            string     `json:"url"`
+	ShortURL       string     `json:"short_url"`
+	RedirectType   int        `json:"redirect_type,omitempty"`
+	ExpiresAt      *time.Time `json:"expires_at,omitempty"`
+	MaxAccessTimes *int32     `json:"max_access_times,omitempty"`
+	AccessTimes    int32      `json:"access_times"`
+	CreatedAt      time.Time  `json:"created_at"`
+	Reused         bool       `json:"reused,omitempty"`
+	FlaggedAt      *time.Time `json:"flagged_at,omitempty"`
+	FlagReason     string     `json:"flag_reason,omitempty"`
+}
+
+func NewLinkDTO(shortUrl *entity.ShortenedURL, shortURL string) *LinkDTO {
+	return &LinkDTO{
+		Alias:          shortUrl.Alias,
+		URL:            shortUrl.Url,
+		ShortURL:       shortURL,
+		RedirectType:   shortUrl.RedirectType,
+		ExpiresAt:      shortUrl.ExpiresAt,
+		MaxAccessTimes: shortUrl.MaxAccessTimes,
+		AccessTimes:    shortUrl.AccessTimes,
+		CreatedAt:      shortUrl.CreatedAt,
+		FlaggedAt:      shortUrl.FlaggedAt,
+		FlagReason:     shortUrl.FlagReason,
+	}
+}
+
+// LinkPageDTO is a page of GET /api/v1/links.
+type LinkPageDTO struct {
+	Links      []LinkDTO `json:"links"`
+	NextCursor string    `json:"next_cursor,omitempty"`
+}
+
+// BulkLinksDTO is the response of POST /api/v1/links/bulk, with one result per item in request order.
+// Items that reused an existing shortened URL are counted as Reused rather than Created.
+type BulkLinksDTO struct {
+	Created int                 `json:"created"`
+	Reused  int                 `json:"reused"`
+	Failed  int                 `json:"failed"`
+	Results []BulkLinkResultDTO `json:"results"`
+}
+
+// BulkLinkResultDTO is the created link of a bulk item, or the error code that prevented it.
+type BulkLinkResultDTO struct {
+	Index       int    `json:"index"`
+	Alias       string `json:"alias,omitempty"`
+	ShortURL    string `json:"short_url,omitempty"`
+	Reused      bool   `json:"reused,omitempty"`
+	ErrCode     string `json:"err_code,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// ImportSummaryDTO is the response of POST /api/v1/admin/links/import.
+type ImportSummaryDTO struct {
+	Created     int `json:"created"`
+	Overwritten int `json:"overwritten"`
+	Skipped     int `json:"skipped"`
+}
 
 type CreatedShortenedURLDTO struct {
-	Alias      string         `json:"alias"`
-	URL        string         `json:"url"`
-	Statistics *StatisticsDTO `json:"statistics"`
+	Alias          string         `json:"alias"`
+	URL            string         `json:"url"`
+	ExpiresAt      *time.Time     `json:"expires_at,omitempty"`
+	MaxAccessTimes *int32         `json:"max_access_times,omitempty"`
+	Reused         bool           `json:"reused"`
+	Statistics     *StatisticsDTO `json:"statistics"`
 }
 
 type StatisticsDTO struct {
@@ -13,7 +101,7 @@ type StatisticsDTO struct {
 }
 
 func NewCreatedShortenedURLDTO(alias, url, timeTaken string) *CreatedShortenedURLDTO {
-	return &CreatedShortenedURLDTO{alias, url, &StatisticsDTO{timeTaken}}
+	return &CreatedShortenedURLDTO{Alias: alias, URL: url, Statistics: &StatisticsDTO{timeTaken}}
 }
 
 type ShortenedUrlRetrieveDTO struct {
@@ -21,16 +109,20 @@ type ShortenedUrlRetrieveDTO struct {
 }
 
 type MostAcessedUrlDTO struct {
-	URL         string `json:"url"`
-	AccessTimes int    `json:"access_times"`
+	Alias       string    `json:"alias"`
+	URL         string    `json:"url"`
+	AccessTimes int       `json:"access_times"`
+	CreatedAt   time.Time `json:"created_at"`
 }
 
 func NewMostAcessedUrlsDTO(shortUrls []entity.ShortenedURL) []MostAcessedUrlDTO {
 	dto := make([]MostAcessedUrlDTO, 0, len(shortUrls))
 	for _, su := range shortUrls {
 		dto = append(dto, MostAcessedUrlDTO{
+			Alias:       su.Alias,
 			URL:         su.Url,
 			AccessTimes: int(su.AccessTimes),
+			CreatedAt:   su.CreatedAt,
 		})
 	}
 	return dto
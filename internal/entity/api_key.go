@@ -0,0 +1,41 @@
+package entity
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+)
+
+// APIKey authenticates the requests of an owner within a tenant. Only the SHA-256 of the key is stored;
+// Prefix keeps its first characters so the owner can tell keys apart.
+type APIKey struct {
+	ID        int        `gorm:"primaryKey;autoIncrement"`
+	OwnerID   string     `gorm:"column:owner_id;size:64;index"`
+	TenantID  int        `gorm:"column:tenant_id;not null;default:0;index"`
+	Name      string     `gorm:"column:name"`
+	KeyHash   string     `gorm:"column:key_hash;size:64;unique"`
+	Prefix    string     `gorm:"column:prefix;size:16"`
+	Admin     bool       `gorm:"column:admin"`
+	CreatedAt time.Time  `gorm:"column:created_at"`
+	RevokedAt *time.Time `gorm:"column:revoked_at"`
+}
+
+// HashAPIKey is the hex SHA-256 of the key, which is what gets stored and looked up.
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// IsRevoked reports whether the key was revoked and no longer authenticates requests.
+func (k *APIKey) IsRevoked() bool {
+	return k.RevokedAt != nil
+}
+
+// CanManage reports whether the key may change or see the stats of the shortened URL: admin keys manage
+// every shortened URL of their tenant, other keys only the ones of their owner.
+func (k *APIKey) CanManage(shortUrl *ShortenedURL) bool {
+	if shortUrl.TenantID != k.TenantID {
+		return false
+	}
+	return k.Admin || (k.OwnerID != "" && shortUrl.OwnerID == k.OwnerID)
+}
@@ -0,0 +1,24 @@
+package entity
+
+import "time"
+
+type ClickEvent struct {
+	ID             int       `gorm:"primaryKey;autoIncrement"`
+	ShortenedURLID int       `gorm:"column:shortened_url_id;index:idx_click_events_shortened_url_id_clicked_at,priority:1"`
+	Alias          string    `gorm:"column:alias;index"`
+	ClickedAt      time.Time `gorm:"column:clicked_at;index;index:idx_click_events_shortened_url_id_clicked_at,priority:2"`
+	Referrer       string    `gorm:"column:referrer"`
+	UserAgent      string    `gorm:"column:user_agent"`
+	ClientIP       string    `gorm:"column:client_ip"`
+	AcceptLanguage string    `gorm:"column:accept_language"`
+}
+
+func NewClickEvent(referrer, userAgent, clientIP, acceptLanguage string) *ClickEvent {
+	return &ClickEvent{Referrer: referrer, UserAgent: userAgent, ClientIP: clientIP, AcceptLanguage: acceptLanguage}
+}
+
+// ClickCount is the number of click events sharing the same value of an attribute, such as the referrer.
+type ClickCount struct {
+	Value string
+	Count int64
+}
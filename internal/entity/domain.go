@@ -0,0 +1,15 @@
+package entity
+
+import "time"
+
+// Domain is a custom short domain, such as go.acme.io, serving the shortened URLs of a tenant. Requests
+// arriving on the host resolve aliases in the namespace of the tenant, and the canonical domain of a
+// tenant is the one its short URLs are built on.
+type Domain struct {
+	ID        int       `gorm:"primaryKey;autoIncrement"`
+	Host      string    `gorm:"column:host;size:253;unique"`
+	TenantID  int       `gorm:"column:tenant_id;not null;default:0;index"`
+	Scheme    string    `gorm:"column:scheme;size:5;not null;default:'https'"`
+	Canonical bool      `gorm:"column:canonical"`
+	CreatedAt time.Time `gorm:"column:created_at"`
+}
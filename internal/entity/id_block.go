@@ -0,0 +1,8 @@
+package entity
+
+// IDBlock holds the next unleased value of a named ID sequence. Replicas lease ranges of IDs by moving
+// NextValue forward, so each one can mint unique IDs without a database round-trip per ID.
+type IDBlock struct {
+	Name      string `gorm:"column:name;primaryKey;size:64"`
+	NextValue int64  `gorm:"column:next_value"`
+}
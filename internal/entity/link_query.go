@@ -0,0 +1,92 @@
+package entity
+
+import (
+	"cmp"
+	"strings"
+	"time"
+)
+
+const (
+	LinkSortCreatedAt   = "created_at"
+	LinkSortAccessTimes = "access_times"
+	LinkSortAlias       = "alias"
+)
+
+// LinkQuery filters, sorts and paginates the shortened URLs of a tenant that were not deleted, unless
+// IncludeDeleted is set. An empty OwnerID matches every owner and Flagged keeps only flagged shortened
+// URLs. Shortened URLs created at the same time are ordered by ID, so LinkSortCreatedAt sorts by ID alone.
+type LinkQuery struct {
+	TenantID       int
+	AliasPrefix    string
+	Domain         string
+	OwnerID        string
+	CreatedFrom    *time.Time
+	CreatedTo      *time.Time
+	MinAccessTimes int32
+	IncludeDeleted bool
+	Flagged        bool
+	SortBy         string
+	Descending     bool
+	After          *LinkCursor
+	Limit          int
+}
+
+// LinkCursor is the position of the last shortened URL of a page: its ID plus the value of the sort key.
+type LinkCursor struct {
+	ID          int    `json:"id"`
+	AccessTimes int32  `json:"access_times,omitempty"`
+	Alias       string `json:"alias,omitempty"`
+}
+
+// Matches reports whether the shortened URL passes the filters of the query, ignoring the cursor.
+func (q *LinkQuery) Matches(shortUrl *ShortenedURL) bool {
+	switch {
+	case shortUrl.TenantID != q.TenantID:
+		return false
+	case shortUrl.IsDeleted() && !q.IncludeDeleted:
+		return false
+	case q.AliasPrefix != "" && !strings.HasPrefix(shortUrl.Alias, q.AliasPrefix):
+		return false
+	case q.Domain != "" && shortUrl.URLHost != q.Domain && !strings.HasSuffix(shortUrl.URLHost, "."+q.Domain):
+		return false
+	case q.OwnerID != "" && shortUrl.OwnerID != q.OwnerID:
+		return false
+	case q.CreatedFrom != nil && shortUrl.CreatedAt.Before(*q.CreatedFrom):
+		return false
+	case q.CreatedTo != nil && !shortUrl.CreatedAt.Before(*q.CreatedTo):
+		return false
+	case q.Flagged && !shortUrl.IsFlagged():
+		return false
+	}
+	return shortUrl.AccessTimes >= q.MinAccessTimes
+}
+
+// IsAfter reports whether the shortened URL comes after the cursor in the sort order of the query.
+func (q *LinkQuery) IsAfter(shortUrl *ShortenedURL, cursor *LinkCursor) bool {
+	var order int
+	switch q.SortBy {
+	case LinkSortAccessTimes:
+		order = cmp.Compare(shortUrl.AccessTimes, cursor.AccessTimes)
+	case LinkSortAlias:
+		order = cmp.Compare(shortUrl.Alias, cursor.Alias)
+	}
+	if order == 0 {
+		order = cmp.Compare(shortUrl.ID, cursor.ID)
+	}
+	if q.Descending {
+		return order < 0
+	}
+	return order > 0
+}
+
+// CursorOf is the cursor positioned at the shortened URL.
+func (q *LinkQuery) CursorOf(shortUrl *ShortenedURL) *LinkCursor {
+	cursor := &LinkCursor{ID: shortUrl.ID}
+	switch q.SortBy {
+	case LinkSortAccessTimes:
+		cursor.AccessTimes = shortUrl.AccessTimes
+	case LinkSortAlias:
+		cursor.Alias = shortUrl.Alias
+	}
+	return cursor
+}
@@ -1,12 +1,73 @@
 package entity
 
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
 type ShortenedURL struct {
-	ID          int    `gorm:"primaryKey;autoIncrement"`
-	Alias       string `gorm:"column:alias;unique"`
-	Url         string `gorm:"column:url"`
-	AccessTimes int32  `gorm:"column:access_times"`
+	ID             int        `gorm:"primaryKey;autoIncrement"`
+	Alias          string     `gorm:"column:alias;uniqueIndex:idx_shortened_urls_tenant_alias,priority:2"`
+	Url            string     `gorm:"column:url"`
+	AccessTimes    int32      `gorm:"column:access_times"`
+	MaxAccessTimes *int32     `gorm:"column:max_access_times"`
+	RedirectType   int        `gorm:"column:redirect_type"`
+	ExpiresAt      *time.Time `gorm:"column:expires_at;index"`
+	CreatedAt      time.Time  `gorm:"column:created_at"`
+	URLHash        string     `gorm:"column:url_hash;size:64;index"`
+	CustomAlias    bool       `gorm:"column:custom_alias"`
+	DeletedAt      *time.Time `gorm:"column:deleted_at;index"`
+	URLHost        string     `gorm:"column:url_host;size:255;index"`
+	OwnerID        string     `gorm:"column:owner_id;size:64;not null;default:'';index"`
+	TenantID       int        `gorm:"column:tenant_id;not null;default:0;uniqueIndex:idx_shortened_urls_tenant_alias,priority:1"`
+	FlaggedAt      *time.Time `gorm:"column:flagged_at;index"`
+	FlagReason     string     `gorm:"column:flag_reason;size:255;not null;default:''"`
 }
 
 func NewShortenedURL(alias, url string) *ShortenedURL {
-	return &ShortenedURL{Alias: alias, Url: url, AccessTimes: 0}
+	return &ShortenedURL{Alias: alias, Url: url, AccessTimes: 0, URLHash: HashURL(url), URLHost: URLHost(url)}
+}
+
+// URLHost is the lowercased host of the destination URL without the port, used to search by domain.
+func URLHost(rawURL string) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(parsed.Hostname())
+}
+
+// HashURL is the hex SHA-256 of the destination URL, used to find shortened URLs of the same destination.
+func HashURL(url string) string {
+	sum := sha256.Sum256([]byte(url))
+	return hex.EncodeToString(sum[:])
+}
+
+// IsExpired reports whether the shortened URL has an expiration date that is not after now.
+func (su *ShortenedURL) IsExpired(now time.Time) bool {
+	return su.ExpiresAt != nil && !su.ExpiresAt.After(now)
+}
+
+// IsDeleted reports whether the shortened URL was soft deleted. Its alias stays taken.
+func (su *ShortenedURL) IsDeleted() bool {
+	return su.DeletedAt != nil
+}
+
+// IsFlagged reports whether the destination was found on the malicious URL blocklist the last time the
+// shortened URL was resolved.
+func (su *ShortenedURL) IsFlagged() bool {
+	return su.FlaggedAt != nil
+}
+
+// IsValidRedirectType reports whether code is one of the HTTP statuses a shortened URL can redirect with.
+func IsValidRedirectType(code int) bool {
+	switch code {
+	case http.StatusMovedPermanently, http.StatusFound, http.StatusTemporaryRedirect, http.StatusPermanentRedirect:
+		return true
+	}
+	return false
 }
@@ -0,0 +1,17 @@
+package entity
+
+import "time"
+
+// DefaultTenantID is the namespace of the shortened URLs created without a tenant, including every
+// shortened URL created before tenants existed. It has no row in the tenants table and no quota.
+const DefaultTenantID = 0
+
+// Tenant is a workspace with its own alias namespace, so two tenants can use the same custom alias.
+// MaxLinks caps how many shortened URLs that were not deleted the tenant may have; nil means no limit.
+type Tenant struct {
+	ID        int       `gorm:"primaryKey;autoIncrement"`
+	Slug      string    `gorm:"column:slug;size:63;unique"`
+	Name      string    `gorm:"column:name"`
+	MaxLinks  *int      `gorm:"column:max_links"`
+	CreatedAt time.Time `gorm:"column:created_at"`
+}
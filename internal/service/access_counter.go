@@ -0,0 +1,119 @@
+package service
+
+import (
+	"log"
+	"sync"
+	"time"
+)
+
+// AccessCounter buffers access increments per shortened URL in memory and writes them to the repository
+// in batches, either every flush interval or as soon as maxPending accesses are buffered.
+// Increments that fail to be written are kept in the buffer and retried on the next flush.
+type AccessCounter struct {
+	repository    ShortenedURLRepository
+	flushInterval time.Duration
+	maxPending    int
+
+	mu           sync.Mutex
+	pending      map[int]int32
+	pendingTotal int
+
+	flushMu     sync.Mutex
+	flushSignal chan struct{}
+	stop        chan struct{}
+	done        chan struct{}
+	startOnce   sync.Once
+	closeOnce   sync.Once
+}
+
+func NewAccessCounter(repository ShortenedURLRepository, flushInterval time.Duration, maxPending int) *AccessCounter {
+	return &AccessCounter{
+		repository:    repository,
+		flushInterval: flushInterval,
+		maxPending:    maxPending,
+		pending:       make(map[int]int32),
+		flushSignal:   make(chan struct{}, 1),
+		stop:          make(chan struct{}),
+		done:          make(chan struct{}),
+	}
+}
+
+// Increment buffers one access to the shortened URL with the given ID.
+func (c *AccessCounter) Increment(id int) {
+	c.mu.Lock()
+	c.pending[id]++
+	c.pendingTotal++
+	full := c.pendingTotal >= c.maxPending
+	c.mu.Unlock()
+
+	if full {
+		select {
+		case c.flushSignal <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Flush writes every buffered increment to the repository.
+func (c *AccessCounter) Flush() error {
+	c.flushMu.Lock()
+	defer c.flushMu.Unlock()
+
+	c.mu.Lock()
+	if c.pendingTotal == 0 {
+		c.mu.Unlock()
+		return nil
+	}
+	batch := c.pending
+	batchTotal := c.pendingTotal
+	c.pending = make(map[int]int32, len(batch))
+	c.pendingTotal = 0
+	c.mu.Unlock()
+
+	if err := c.repository.AddAccessTimes(batch); err != nil {
+		c.mu.Lock()
+		for id, increment := range batch {
+			c.pending[id] += increment
+		}
+		c.pendingTotal += batchTotal
+		c.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+// Start flushes the buffered increments in a background goroutine until Close is called.
+func (c *AccessCounter) Start() {
+	c.startOnce.Do(func() {
+		go c.run()
+	})
+}
+
+func (c *AccessCounter) run() {
+	defer close(c.done)
+	ticker := time.NewTicker(c.flushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+		case <-c.flushSignal:
+		case <-c.stop:
+			return
+		}
+		if err := c.Flush(); err != nil {
+			log.Println("Failed to flush access counts, retrying on next flush:", err)
+		}
+	}
+}
+
+// Close stops the background flushing and writes the remaining buffered increments.
+func (c *AccessCounter) Close() error {
+	c.closeOnce.Do(func() {
+		close(c.stop)
+		c.startOnce.Do(func() {
+			close(c.done)
+		})
+		<-c.done
+	})
+	return c.Flush()
+}
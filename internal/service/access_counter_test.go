@@ -0,0 +1,94 @@
+package service
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// recordAddedAccessTimes makes the mock repository accumulate every flushed increment into totals.
+func recordAddedAccessTimes(repo *MockShortenedURLRepository, totals map[int]int32, mu *sync.Mutex) {
+	repo.On("AddAccessTimes", mock.AnythingOfType("map[int]int32")).Return(nil).Run(func(args mock.Arguments) {
+		mu.Lock()
+		defer mu.Unlock()
+		for id, increment := range args.Get(0).(map[int]int32) {
+			totals[id] += increment
+		}
+	})
+}
+
+func TestAccessCounterUnit_ConcurrentIncrements(t *testing.T) {
+	t.Run("Given many concurrent accesses, when the counter flushes by interval, size threshold and on close, then no access should be lost", func(t *testing.T) {
+		var mu sync.Mutex
+		totals := make(map[int]int32)
+		repo := &MockShortenedURLRepository{}
+		recordAddedAccessTimes(repo, totals, &mu)
+
+		counter := NewAccessCounter(repo, 5*time.Millisecond, 100)
+		counter.Start()
+
+		const goroutines, incrementsPerGoroutine, ids = 50, 200, 5
+		var wg sync.WaitGroup
+		for g := 0; g < goroutines; g++ {
+			wg.Add(1)
+			go func(g int) {
+				defer wg.Done()
+				for i := 0; i < incrementsPerGoroutine; i++ {
+					counter.Increment((g+i)%ids + 1)
+				}
+			}(g)
+		}
+		wg.Wait()
+
+		assert.NoError(t, counter.Close())
+
+		mu.Lock()
+		defer mu.Unlock()
+		var sum int32
+		for id := 1; id <= ids; id++ {
+			assert.Equal(t, int32(goroutines*incrementsPerGoroutine/ids), totals[id], "Every access of the shortened URL should be flushed")
+			sum += totals[id]
+		}
+		assert.Equal(t, int32(goroutines*incrementsPerGoroutine), sum)
+	})
+}
+
+func TestAccessCounterUnit_Flush(t *testing.T) {
+	t.Run("Given a failing repository, when Flush is called, then the increments should be kept and written on the next flush", func(t *testing.T) {
+		repo := &MockShortenedURLRepository{}
+		repo.On("AddAccessTimes", map[int]int32{1: 2}).Return(assert.AnError).Once()
+		counter := NewAccessCounter(repo, time.Hour, 1000)
+
+		counter.Increment(1)
+		counter.Increment(1)
+		assert.ErrorIs(t, counter.Flush(), assert.AnError)
+
+		counter.Increment(2)
+		repo.On("AddAccessTimes", map[int]int32{1: 2, 2: 1}).Return(nil).Once()
+		assert.NoError(t, counter.Flush())
+
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("Given no buffered accesses, when Flush is called, then the repository should not be called", func(t *testing.T) {
+		repo := &MockShortenedURLRepository{}
+		counter := NewAccessCounter(repo, time.Hour, 1000)
+
+		assert.NoError(t, counter.Flush())
+		repo.AssertNotCalled(t, "AddAccessTimes", mock.Anything)
+	})
+
+	t.Run("Given a counter that was never started, when Close is called, then it should flush the buffered accesses", func(t *testing.T) {
+		repo := &MockShortenedURLRepository{}
+		repo.On("AddAccessTimes", map[int]int32{3: 1}).Return(nil).Once()
+		counter := NewAccessCounter(repo, time.Hour, 1000)
+
+		counter.Increment(3)
+		assert.NoError(t, counter.Close())
+
+		repo.AssertExpectations(t)
+	})
+}
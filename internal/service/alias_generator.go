@@ -0,0 +1,233 @@
+package service
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"math/bits"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrAliasGenerationFailed = fmt.Errorf("could not generate an unused alias")
+var ErrInvalidAliasStrategy = fmt.Errorf("invalid alias generation strategy")
+
+const (
+	AliasStrategyTimestamp     = "timestamp"
+	AliasStrategyRandom        = "random"
+	AliasStrategySequential    = "sequential"
+	AliasStrategyBlock         = "block"
+	AliasStrategyHash          = "hash"
+	AliasStrategyPronounceable = "pronounceable"
+
+	DefaultAliasGenerationAttempts = 10
+
+	base62Alphabet = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+	consonants     = "bcdfghjklmnpqrstvwxz"
+	vowels         = "aeiou"
+)
+
+// AliasGenerator proposes aliases for shortened URLs created without a custom one. attempt starts at zero
+// and grows after every collision, so deterministic strategies can propose a different alias.
+type AliasGenerator interface {
+	Generate(url string, attempt int) (string, error)
+}
+
+// NewAliasGenerator builds the generator of the given strategy, sequential when empty, so replicas
+// leasing IDs from the same IDSource never propose colliding aliases. length is the alias length (in syllables
+// for the pronounceable strategy), zero meaning the strategy default. It is ignored by the timestamp and
+// block strategies. ids feeds the sequential and block strategies; when nil, an in-process counter is used.
+func NewAliasGenerator(strategy string, length int, ids IDSource) (AliasGenerator, error) {
+	if length < 0 {
+		return nil, ErrInvalidAliasStrategy
+	}
+	if ids == nil {
+		ids = NewCounterIDSource(uint64(time.Now().UnixMilli()))
+	}
+	switch strategy {
+	case AliasStrategyTimestamp:
+		return TimestampAliasGenerator{}, nil
+	case AliasStrategyRandom:
+		return NewRandomAliasGenerator(length), nil
+	case "", AliasStrategySequential:
+		return NewSequentialAliasGenerator(length, ids), nil
+	case AliasStrategyBlock:
+		return NewBlockAliasGenerator(ids), nil
+	case AliasStrategyHash:
+		return NewHashAliasGenerator(length), nil
+	case AliasStrategyPronounceable:
+		return NewPronounceableAliasGenerator(length), nil
+	}
+	return nil, ErrInvalidAliasStrategy
+}
+
+// TimestampAliasGenerator is the original scheme: the millisecond timestamp shifted by 20 bits and
+// combined with random bits, in base62. It is the default of NewURLShortenerService, which has no
+// IDSource to lease IDs from.
+type TimestampAliasGenerator struct{}
+
+func (TimestampAliasGenerator) Generate(url string, attempt int) (string, error) {
+	timestamp := uint64(time.Now().UnixMilli())
+	randomPart := uint64(randomUint32())
+	return encodeBase62((timestamp << 20) | randomPart), nil
+}
+
+// RandomAliasGenerator draws every character uniformly from the base62 alphabet.
+type RandomAliasGenerator struct {
+	length int
+}
+
+func NewRandomAliasGenerator(length int) *RandomAliasGenerator {
+	if length == 0 {
+		length = 8
+	}
+	return &RandomAliasGenerator{length: length}
+}
+
+func (g *RandomAliasGenerator) Generate(url string, attempt int) (string, error) {
+	return randomString(base62Alphabet, g.length)
+}
+
+// SequentialAliasGenerator encodes the IDs of an IDSource after an affine permutation of the 62^length
+// alias space, so consecutive aliases look unrelated while staying unique until the space wraps around.
+type SequentialAliasGenerator struct {
+	length int
+	space  uint64
+	ids    IDSource
+}
+
+// sequenceMultiplier is a prime, hence coprime with every 62^length, which makes the permutation a bijection.
+const (
+	sequenceMultiplier = 1000000007
+	sequenceOffset     = 0x5DEECE66D
+)
+
+func NewSequentialAliasGenerator(length int, ids IDSource) *SequentialAliasGenerator {
+	if length == 0 {
+		length = 7
+	}
+	space := uint64(1)
+	for i := 0; i < length && space <= (1<<64-1)/62; i++ {
+		space *= 62
+	}
+	return &SequentialAliasGenerator{length: length, space: space, ids: ids}
+}
+
+func (g *SequentialAliasGenerator) Generate(url string, attempt int) (string, error) {
+	id, err := g.ids.NextID()
+	if err != nil {
+		return "", err
+	}
+	return g.encode(id), nil
+}
+
+func (g *SequentialAliasGenerator) encode(sequence uint64) string {
+	hi, lo := bits.Mul64(sequence%g.space, sequenceMultiplier)
+	permuted := (bits.Rem64(hi, lo, g.space) + sequenceOffset%g.space) % g.space
+	return padBase62(permuted, g.length)
+}
+
+// BlockAliasGenerator encodes the IDs of an IDSource in base62 as they are, producing the shortest aliases.
+type BlockAliasGenerator struct {
+	ids IDSource
+}
+
+func NewBlockAliasGenerator(ids IDSource) *BlockAliasGenerator {
+	return &BlockAliasGenerator{ids: ids}
+}
+
+func (g *BlockAliasGenerator) Generate(url string, attempt int) (string, error) {
+	id, err := g.ids.NextID()
+	if err != nil {
+		return "", err
+	}
+	return encodeBase62(id), nil
+}
+
+// HashAliasGenerator derives the alias from the SHA-256 of the URL, so the same URL first proposes
+// the same alias. Collisions are resolved by hashing the URL with the attempt number.
+type HashAliasGenerator struct {
+	length int
+}
+
+func NewHashAliasGenerator(length int) *HashAliasGenerator {
+	if length == 0 {
+		length = 7
+	}
+	return &HashAliasGenerator{length: length}
+}
+
+func (g *HashAliasGenerator) Generate(url string, attempt int) (string, error) {
+	input := url
+	if attempt > 0 {
+		input += "#" + strconv.Itoa(attempt)
+	}
+	sum := sha256.Sum256([]byte(input))
+	var alias strings.Builder
+	for i := 0; alias.Len() < g.length; i += 8 {
+		if i+8 > len(sum) {
+			sum = sha256.Sum256(sum[:])
+			i = 0
+		}
+		alias.WriteString(padBase62(binary.BigEndian.Uint64(sum[i:i+8]), 10))
+	}
+	return alias.String()[:g.length], nil
+}
+
+// PronounceableAliasGenerator builds aliases from random consonant-vowel syllables, such as "kobemisa".
+type PronounceableAliasGenerator struct {
+	syllables int
+}
+
+func NewPronounceableAliasGenerator(syllables int) *PronounceableAliasGenerator {
+	if syllables == 0 {
+		syllables = 4
+	}
+	return &PronounceableAliasGenerator{syllables: syllables}
+}
+
+func (g *PronounceableAliasGenerator) Generate(url string, attempt int) (string, error) {
+	var alias strings.Builder
+	for i := 0; i < g.syllables; i++ {
+		consonant, err := randomString(consonants, 1)
+		if err != nil {
+			return "", err
+		}
+		vowel, err := randomString(vowels, 1)
+		if err != nil {
+			return "", err
+		}
+		alias.WriteString(consonant + vowel)
+	}
+	return alias.String(), nil
+}
+
+// randomString draws length characters uniformly from alphabet, rejecting the bytes that would bias the draw.
+func randomString(alphabet string, length int) (string, error) {
+	limit := 256 - 256%len(alphabet)
+	result := make([]byte, 0, length)
+	buf := make([]byte, length)
+	for len(result) < length {
+		if _, err := rand.Read(buf); err != nil {
+			return "", err
+		}
+		for _, b := range buf {
+			if int(b) < limit && len(result) < length {
+				result = append(result, alphabet[int(b)%len(alphabet)])
+			}
+		}
+	}
+	return string(result), nil
+}
+
+// padBase62 encodes num in exactly length base62 digits, keeping the least significant ones.
+func padBase62(num uint64, length int) string {
+	result := make([]byte, length)
+	for i := range result {
+		result[i] = base62Alphabet[num%62]
+		num /= 62
+	}
+	return string(result)
+}
@@ -0,0 +1,124 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAliasGeneratorUnit_NewAliasGenerator(t *testing.T) {
+	t.Run("Given a known strategy, when the generator is built, then it should return the matching generator", func(t *testing.T) {
+		for strategy, expected := range map[string]AliasGenerator{
+			"":                         &SequentialAliasGenerator{},
+			AliasStrategyTimestamp:     TimestampAliasGenerator{},
+			AliasStrategyRandom:        &RandomAliasGenerator{},
+			AliasStrategySequential:    &SequentialAliasGenerator{},
+			AliasStrategyBlock:         &BlockAliasGenerator{},
+			AliasStrategyHash:          &HashAliasGenerator{},
+			AliasStrategyPronounceable: &PronounceableAliasGenerator{},
+		} {
+			generator, err := NewAliasGenerator(strategy, 0, nil)
+
+			assert.NoError(t, err)
+			assert.IsType(t, expected, generator)
+		}
+	})
+
+	t.Run("Given an unknown strategy or a negative length, when the generator is built, then it should return an error", func(t *testing.T) {
+		_, err := NewAliasGenerator("uuid", 0, nil)
+		assert.ErrorIs(t, err, ErrInvalidAliasStrategy)
+
+		_, err = NewAliasGenerator(AliasStrategyRandom, -1, nil)
+		assert.ErrorIs(t, err, ErrInvalidAliasStrategy)
+	})
+}
+
+func TestAliasGeneratorUnit_Random(t *testing.T) {
+	t.Run("Given a configured length, when aliases are generated, then they should be base62 strings of that length", func(t *testing.T) {
+		generator := NewRandomAliasGenerator(5)
+		seen := make(map[string]bool)
+
+		for i := 0; i < 100; i++ {
+			alias, err := generator.Generate("http://www.bemobi.com.br", 0)
+			assert.NoError(t, err)
+			assert.Regexp(t, "^[a-zA-Z0-9]{5}$", alias)
+			seen[alias] = true
+		}
+		assert.Greater(t, len(seen), 95)
+	})
+}
+
+func TestAliasGeneratorUnit_Sequential(t *testing.T) {
+	t.Run("Given consecutive counter values, when aliases are generated, then they should be distinct and fixed length until the space wraps around", func(t *testing.T) {
+		generator := NewSequentialAliasGenerator(3, NewCounterIDSource(0))
+		seen := make(map[string]bool)
+
+		for i := 0; i < 62*62*62; i++ {
+			alias, err := generator.Generate("http://www.bemobi.com.br", 0)
+			assert.NoError(t, err)
+			if len(alias) != 3 || seen[alias] {
+				t.Fatalf("alias %q is duplicated or has the wrong length", alias)
+			}
+			seen[alias] = true
+		}
+	})
+
+	t.Run("Given consecutive counter values, when aliases are generated, then most of their characters should change", func(t *testing.T) {
+		generator := NewSequentialAliasGenerator(7, NewCounterIDSource(41))
+
+		first, _ := generator.Generate("http://www.bemobi.com.br", 0)
+		second, _ := generator.Generate("http://www.bemobi.com.br", 0)
+
+		changed := 0
+		for i := range first {
+			if first[i] != second[i] {
+				changed++
+			}
+		}
+		assert.GreaterOrEqual(t, changed, 4)
+	})
+}
+
+func TestAliasGeneratorUnit_Block(t *testing.T) {
+	t.Run("Given leased IDs, when aliases are generated, then they should be the IDs in base62", func(t *testing.T) {
+		generator := NewBlockAliasGenerator(NewCounterIDSource(61))
+
+		first, _ := generator.Generate("http://www.bemobi.com.br", 0)
+		second, _ := generator.Generate("http://www.bemobi.com.br", 0)
+
+		assert.Equal(t, "z", first)
+		assert.Equal(t, encodeBase62(62), second)
+	})
+}
+
+func TestAliasGeneratorUnit_Hash(t *testing.T) {
+	t.Run("Given the same URL and attempt, when aliases are generated, then they should be equal, and differ across attempts", func(t *testing.T) {
+		generator := NewHashAliasGenerator(12)
+
+		first, _ := generator.Generate("http://www.bemobi.com.br", 0)
+		again, _ := generator.Generate("http://www.bemobi.com.br", 0)
+		retried, _ := generator.Generate("http://www.bemobi.com.br", 1)
+		other, _ := generator.Generate("http://www.google.com", 0)
+
+		assert.Regexp(t, "^[a-zA-Z0-9]{12}$", first)
+		assert.Equal(t, first, again)
+		assert.NotEqual(t, first, retried)
+		assert.NotEqual(t, first, other)
+	})
+
+	t.Run("Given a length beyond a single digest, when an alias is generated, then it should still have that length", func(t *testing.T) {
+		alias, err := NewHashAliasGenerator(64).Generate("http://www.bemobi.com.br", 0)
+
+		assert.NoError(t, err)
+		assert.Len(t, alias, 64)
+	})
+}
+
+func TestAliasGeneratorUnit_Pronounceable(t *testing.T) {
+	t.Run("Given a number of syllables, when an alias is generated, then it should alternate consonants and vowels", func(t *testing.T) {
+		alias, err := NewPronounceableAliasGenerator(5).Generate("http://www.bemobi.com.br", 0)
+
+		assert.NoError(t, err)
+		assert.Regexp(t, "^([bcdfghjklmnpqrstvwxz][aeiou]){5}$", alias)
+	})
+}
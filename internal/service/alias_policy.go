@@ -0,0 +1,110 @@
+package service
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"unicode/utf8"
+)
+
+var ErrInvalidAlias = fmt.Errorf("alias does not follow the alias rules")
+var ErrReservedAlias = fmt.Errorf("alias is reserved")
+var ErrInvalidAliasPolicy = fmt.Errorf("invalid alias policy")
+
+const (
+	// AliasCasePreserve stores custom aliases as given; aliases are case-sensitive.
+	AliasCasePreserve = "preserve"
+	// AliasCaseLower lowercases custom aliases before they are validated and stored.
+	AliasCaseLower = "lower"
+)
+
+// ReservedAliases are always blocked, since they clash with routes or could be mistaken for official links.
+var ReservedAliases = []string{"u", "api", "admin", "most_acessed", "stats", "static", "health", "metrics", "login", "logout"}
+
+// AliasPolicy holds the rules custom aliases must follow. Blocklist entries are matched case-insensitively,
+// either exactly or, when they contain *, as glob patterns such as *word* for any alias containing word.
+type AliasPolicy struct {
+	MinLength         int
+	MaxLength         int
+	AllowedCharacters string
+	CasePolicy        string
+	Blocklist         []string
+}
+
+// DefaultAliasPolicy accepts aliases of 3 to 64 letters, digits, hyphens and underscores, except the ReservedAliases.
+func DefaultAliasPolicy() AliasPolicy {
+	return AliasPolicy{
+		MinLength:         3,
+		MaxLength:         64,
+		AllowedCharacters: base62Alphabet + "-_",
+		CasePolicy:        AliasCasePreserve,
+		Blocklist:         ReservedAliases,
+	}
+}
+
+// Validate reports whether the policy itself is consistent.
+func (p AliasPolicy) Validate() error {
+	if p.MinLength < 1 || p.MaxLength < p.MinLength || p.AllowedCharacters == "" {
+		return ErrInvalidAliasPolicy
+	}
+	if p.CasePolicy != AliasCasePreserve && p.CasePolicy != AliasCaseLower {
+		return ErrInvalidAliasPolicy
+	}
+	return nil
+}
+
+// Apply checks a custom alias against the rules and returns it as it must be stored.
+func (p AliasPolicy) Apply(alias string) (string, error) {
+	if p.CasePolicy == AliasCaseLower {
+		alias = strings.ToLower(alias)
+	}
+	length := utf8.RuneCountInString(alias)
+	if length < p.MinLength || length > p.MaxLength {
+		return "", ErrInvalidAlias
+	}
+	for _, r := range alias {
+		if !strings.ContainsRune(p.AllowedCharacters, r) {
+			return "", ErrInvalidAlias
+		}
+	}
+	if p.IsBlocked(alias) {
+		return "", ErrReservedAlias
+	}
+	return alias, nil
+}
+
+// IsBlocked reports whether alias matches an entry of the blocklist.
+func (p AliasPolicy) IsBlocked(alias string) bool {
+	alias = strings.ToLower(alias)
+	for _, entry := range p.Blocklist {
+		entry = strings.ToLower(entry)
+		if entry == alias {
+			return true
+		}
+		if strings.Contains(entry, "*") {
+			if matched, err := path.Match(entry, alias); err == nil && matched {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ParseAliasBlocklist reads one blocklist entry per line, skipping blank lines and # comments.
+func ParseAliasBlocklist(r io.Reader) ([]string, error) {
+	var entries []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		entries = append(entries, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
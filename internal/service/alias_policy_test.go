@@ -0,0 +1,95 @@
+package service
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAliasPolicyUnit_Apply(t *testing.T) {
+	t.Run("Given aliases that follow the default rules, when the policy is applied, then they should be kept as given", func(t *testing.T) {
+		policy := DefaultAliasPolicy()
+		for _, alias := range []string{"abc", "XYhakR", "summer-sale_2024", strings.Repeat("a", 64)} {
+			applied, err := policy.Apply(alias)
+
+			assert.NoError(t, err, alias)
+			assert.Equal(t, alias, applied)
+		}
+	})
+
+	t.Run("Given aliases that break the default rules, when the policy is applied, then they should be rejected", func(t *testing.T) {
+		policy := DefaultAliasPolicy()
+		for _, alias := range []string{"ab", strings.Repeat("a", 65), "a/b/c", "with space", "ação", "../etc", "a?b=c"} {
+			_, err := policy.Apply(alias)
+
+			assert.ErrorIs(t, err, ErrInvalidAlias, alias)
+		}
+	})
+
+	t.Run("Given reserved aliases in any case, when the policy is applied, then they should be rejected as reserved", func(t *testing.T) {
+		policy := DefaultAliasPolicy()
+		for _, alias := range []string{"most_acessed", "Admin", "API", "stats"} {
+			_, err := policy.Apply(alias)
+
+			assert.ErrorIs(t, err, ErrReservedAlias, alias)
+		}
+	})
+
+	t.Run("Given a blocklist with glob patterns, when the policy is applied, then aliases matching them should be rejected", func(t *testing.T) {
+		policy := DefaultAliasPolicy()
+		policy.Blocklist = []string{"*darn*", "internal-*"}
+
+		for alias, expected := range map[string]error{
+			"darn":           ErrReservedAlias,
+			"oh-DARN-it":     ErrReservedAlias,
+			"internal-tools": ErrReservedAlias,
+			"my-internal":    nil,
+			"admin":          nil,
+		} {
+			_, err := policy.Apply(alias)
+
+			if expected == nil {
+				assert.NoError(t, err, alias)
+			} else {
+				assert.ErrorIs(t, err, expected, alias)
+			}
+		}
+	})
+
+	t.Run("Given the lower case policy, when the policy is applied, then the alias should be lowercased", func(t *testing.T) {
+		policy := DefaultAliasPolicy()
+		policy.CasePolicy = AliasCaseLower
+
+		applied, err := policy.Apply("SummerSale")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "summersale", applied)
+	})
+}
+
+func TestAliasPolicyUnit_Validate(t *testing.T) {
+	t.Run("Given inconsistent rules, when the policy is validated, then it should return an error", func(t *testing.T) {
+		for _, change := range []func(p *AliasPolicy){
+			func(p *AliasPolicy) { p.MinLength = 0 },
+			func(p *AliasPolicy) { p.MaxLength = p.MinLength - 1 },
+			func(p *AliasPolicy) { p.AllowedCharacters = "" },
+			func(p *AliasPolicy) { p.CasePolicy = "upper" },
+		} {
+			policy := DefaultAliasPolicy()
+			change(&policy)
+
+			assert.ErrorIs(t, policy.Validate(), ErrInvalidAliasPolicy)
+		}
+		assert.NoError(t, DefaultAliasPolicy().Validate())
+	})
+}
+
+func TestAliasPolicyUnit_ParseAliasBlocklist(t *testing.T) {
+	t.Run("Given a blocklist file, when it is parsed, then it should skip blank lines and comments", func(t *testing.T) {
+		entries, err := ParseAliasBlocklist(strings.NewReader("# reserved\nsupport\n\n  *darn*  \n# end\n"))
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"support", "*darn*"}, entries)
+	})
+}
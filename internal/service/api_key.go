@@ -0,0 +1,96 @@
+package service
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lucasfarolfi/hire.me/internal/entity"
+	"gorm.io/gorm"
+)
+
+var ErrInvalidAPIKey = fmt.Errorf("api key is missing, invalid or revoked")
+var ErrInvalidOwnerID = fmt.Errorf("owner id must have between 1 and 64 characters")
+var ErrLinkNotOwned = fmt.Errorf("shortened url belongs to another owner")
+
+// apiKeyPrefix starts every issued key, so leaked keys are easy to recognize.
+const apiKeyPrefix = "hk_"
+
+const maxOwnerIDLength = 64
+
+type APIKeyRepository interface {
+	Create(key *entity.APIKey) error
+	FindByHash(keyHash string) (*entity.APIKey, error)
+	FindAll() ([]entity.APIKey, error)
+	Revoke(id int, revokedAt time.Time) error
+}
+
+// APIKeyService issues API keys and authenticates requests with them.
+type APIKeyService struct {
+	repository APIKeyRepository
+}
+
+func NewAPIKeyService(repository APIKeyRepository) *APIKeyService {
+	return &APIKeyService{repository: repository}
+}
+
+// Issue creates an API key for the owner within the tenant and returns it with the plain key, which is not
+// stored and cannot be recovered afterwards. Admin keys manage the links of every owner of their tenant and
+// the admin routes.
+func (s *APIKeyService) Issue(tenantID int, ownerID, name string, admin bool) (*entity.APIKey, string, error) {
+	ownerID = strings.TrimSpace(ownerID)
+	if ownerID == "" || len(ownerID) > maxOwnerIDLength {
+		return nil, "", ErrInvalidOwnerID
+	}
+
+	var secret [32]byte
+	if _, err := rand.Read(secret[:]); err != nil {
+		return nil, "", err
+	}
+	key := apiKeyPrefix + base64.RawURLEncoding.EncodeToString(secret[:])
+	apiKey := &entity.APIKey{
+		OwnerID:   ownerID,
+		TenantID:  tenantID,
+		Name:      name,
+		KeyHash:   entity.HashAPIKey(key),
+		Prefix:    key[:len(apiKeyPrefix)+6],
+		Admin:     admin,
+		CreatedAt: time.Now().UTC(),
+	}
+	if err := s.repository.Create(apiKey); err != nil {
+		return nil, "", err
+	}
+	return apiKey, key, nil
+}
+
+// Authenticate returns the API key matching the plain key, or ErrInvalidAPIKey when it is unknown or
+// revoked.
+func (s *APIKeyService) Authenticate(key string) (*entity.APIKey, error) {
+	if !strings.HasPrefix(key, apiKeyPrefix) {
+		return nil, ErrInvalidAPIKey
+	}
+	apiKey, err := s.repository.FindByHash(entity.HashAPIKey(key))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrInvalidAPIKey
+	}
+	if err != nil {
+		return nil, err
+	}
+	if apiKey.IsRevoked() {
+		return nil, ErrInvalidAPIKey
+	}
+	return apiKey, nil
+}
+
+// List returns every API key, including revoked ones, in creation order.
+func (s *APIKeyService) List() ([]entity.APIKey, error) {
+	return s.repository.FindAll()
+}
+
+// Revoke stops the API key with the given ID from authenticating requests.
+func (s *APIKeyService) Revoke(id int) error {
+	return s.repository.Revoke(id, time.Now().UTC())
+}
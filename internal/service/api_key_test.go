@@ -0,0 +1,93 @@
+package service
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/lucasfarolfi/hire.me/infrastructure/repository/memory"
+	"github.com/lucasfarolfi/hire.me/internal/entity"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestAPIKeyServiceMemory_IssueAndAuthenticate(t *testing.T) {
+	t.Run("Given an issued key, when it is authenticated, then it should return the key without storing the secret", func(t *testing.T) {
+		repository := memory.NewAPIKeyRepository()
+		service := NewAPIKeyService(repository)
+
+		issued, key, err := service.Issue(0, "alice", "ci", false)
+		assert.NoError(t, err)
+		assert.True(t, strings.HasPrefix(key, apiKeyPrefix))
+		assert.True(t, strings.HasPrefix(key, issued.Prefix))
+		assert.Equal(t, entity.HashAPIKey(key), issued.KeyHash)
+		assert.NotContains(t, issued.KeyHash, key[len(apiKeyPrefix):])
+
+		authenticated, err := service.Authenticate(key)
+		assert.NoError(t, err)
+		assert.Equal(t, issued.ID, authenticated.ID)
+		assert.Equal(t, "alice", authenticated.OwnerID)
+	})
+
+	t.Run("Given unknown, empty or revoked keys, when they are authenticated, then it should return an invalid api key error", func(t *testing.T) {
+		service := NewAPIKeyService(memory.NewAPIKeyRepository())
+		issued, key, err := service.Issue(0, "alice", "", false)
+		assert.NoError(t, err)
+
+		_, err = service.Authenticate("")
+		assert.ErrorIs(t, err, ErrInvalidAPIKey)
+		_, err = service.Authenticate(key + "x")
+		assert.ErrorIs(t, err, ErrInvalidAPIKey)
+
+		assert.NoError(t, service.Revoke(issued.ID))
+		_, err = service.Authenticate(key)
+		assert.ErrorIs(t, err, ErrInvalidAPIKey)
+		assert.ErrorIs(t, service.Revoke(issued.ID+1), gorm.ErrRecordNotFound)
+	})
+
+	t.Run("Given an invalid owner, when a key is issued, then it should return an invalid owner error", func(t *testing.T) {
+		service := NewAPIKeyService(memory.NewAPIKeyRepository())
+
+		_, _, err := service.Issue(0, " ", "", false)
+		assert.ErrorIs(t, err, ErrInvalidOwnerID)
+		_, _, err = service.Issue(0, strings.Repeat("a", maxOwnerIDLength+1), "", false)
+		assert.ErrorIs(t, err, ErrInvalidOwnerID)
+	})
+}
+
+func TestShortenerServiceMemory_Ownership(t *testing.T) {
+	alice := &entity.APIKey{OwnerID: "alice"}
+	bob := &entity.APIKey{OwnerID: "bob"}
+	admin := &entity.APIKey{OwnerID: "ops", Admin: true}
+
+	t.Run("Given a link of an owner, when keys are authorized for it, then only the owner and admins should manage it", func(t *testing.T) {
+		service := newMemoryService()
+		_, err := service.Create("abc123", "http://www.bemobi.com.br", WithOwner("alice"))
+		assert.NoError(t, err)
+		_, err = service.Create("legacy", "http://www.bemobi.com.br")
+		assert.NoError(t, err)
+
+		assert.NoError(t, service.Authorize(alice, "abc123"))
+		assert.NoError(t, service.Authorize(admin, "abc123"))
+		assert.ErrorIs(t, service.Authorize(bob, "abc123"), ErrLinkNotOwned)
+		assert.ErrorIs(t, service.Authorize(alice, "legacy"), ErrLinkNotOwned)
+		assert.NoError(t, service.Authorize(admin, "legacy"))
+		assert.ErrorIs(t, service.Authorize(alice, "unknown"), gorm.ErrRecordNotFound)
+	})
+
+	t.Run("Given deduplication, when owners shorten the same URL, then each owner should get its own link", func(t *testing.T) {
+		service := newMemoryService(WithDeduplication())
+
+		first, _, err := service.CreateOrReuse("", "http://www.bemobi.com.br", WithOwner("alice"))
+		assert.NoError(t, err)
+		reused, wasReused, err := service.CreateOrReuse("", "http://www.bemobi.com.br", WithOwner("alice"))
+		assert.NoError(t, err)
+		other, otherReused, err := service.CreateOrReuse("", "http://www.bemobi.com.br", WithOwner("bob"))
+		assert.NoError(t, err)
+
+		assert.True(t, wasReused)
+		assert.Equal(t, first.Alias, reused.Alias)
+		assert.False(t, otherReused)
+		assert.NotEqual(t, first.Alias, other.Alias)
+		assert.Equal(t, "bob", other.OwnerID)
+	})
+}
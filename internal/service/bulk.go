@@ -0,0 +1,119 @@
+package service
+
+import (
+	"github.com/lucasfarolfi/hire.me/internal/entity"
+)
+
+const DefaultBulkBatchSize = 100
+
+// Transactor runs fn with a repository bound to a single transaction, committing it when fn returns nil
+// and rolling it back otherwise. Writes that fail inside fn must not abort the whole transaction.
+type Transactor func(fn func(repository ShortenedURLRepository) error) error
+
+// WithTransactor makes bulk operations write each batch in a single transaction. Without it, every
+// item is written on its own.
+func WithTransactor(transactor Transactor) ServiceOption {
+	return func(s *URLShortenerService) {
+		s.transactor = transactor
+	}
+}
+
+// WithBulkBatchSize sets how many items bulk operations write per transaction.
+func WithBulkBatchSize(size int) ServiceOption {
+	return func(s *URLShortenerService) {
+		s.bulkBatchSize = size
+	}
+}
+
+// BulkItem is one shortened URL to create with BulkCreate. An empty alias is generated.
+type BulkItem struct {
+	Alias   string
+	URL     string
+	Options []CreateOption
+}
+
+// BulkResult is the outcome of a BulkItem: the created (or reused) shortened URL, or the error that
+// prevented it.
+type BulkResult struct {
+	ShortenedURL *entity.ShortenedURL
+	Reused       bool
+	Err          error
+}
+
+// BulkCreate creates every item through CreateOrReuse, in transactions of the configured batch size.
+// A failing item does not prevent the others from being created, but when a batch cannot be committed
+// all of its items fail with the commit error.
+func (s *URLShortenerService) BulkCreate(items []BulkItem) []BulkResult {
+	results := make([]BulkResult, len(items))
+	for start := 0; start < len(items); start += s.bulkBatchSize {
+		end := min(start+s.bulkBatchSize, len(items))
+		err := s.inTransaction(func(tx *URLShortenerService) error {
+			for i := start; i < end; i++ {
+				created, reused, err := tx.CreateOrReuse(items[i].Alias, items[i].URL, items[i].Options...)
+				results[i] = BulkResult{ShortenedURL: created, Reused: reused, Err: err}
+			}
+			return nil
+		})
+		if err != nil {
+			for i := start; i < end; i++ {
+				if results[i].Err == nil {
+					results[i] = BulkResult{Err: err}
+				}
+			}
+		}
+	}
+	return results
+}
+
+// aliasInvalidator is implemented by caching repositories, whose entries must be dropped after writes
+// made through a transaction-bound repository.
+type aliasInvalidator interface {
+	Invalidate(tenantID int, alias string)
+}
+
+// inTransaction runs fn with a copy of the service bound to a transaction, or with the service itself
+// when no transactor is configured.
+func (s *URLShortenerService) inTransaction(fn func(tx *URLShortenerService) error) error {
+	if s.transactor == nil {
+		return fn(s)
+	}
+	var written []*entity.ShortenedURL
+	err := s.transactor(func(repository ShortenedURLRepository) error {
+		tx := *s
+		tx.Repository = &recordingRepository{ShortenedURLRepository: repository, written: &written}
+		tx.transactor = nil
+		return fn(&tx)
+	})
+	if invalidator, ok := s.Repository.(aliasInvalidator); ok {
+		for _, shortUrl := range written {
+			invalidator.Invalidate(shortUrl.TenantID, shortUrl.Alias)
+		}
+	}
+	return err
+}
+
+// recordingRepository records the shortened URLs written through a transaction-bound repository.
+type recordingRepository struct {
+	ShortenedURLRepository
+	written *[]*entity.ShortenedURL
+}
+
+func (r *recordingRepository) Create(shortUrl *entity.ShortenedURL) error {
+	*r.written = append(*r.written, shortUrl)
+	return r.ShortenedURLRepository.Create(shortUrl)
+}
+
+func (r *recordingRepository) Update(shortUrl *entity.ShortenedURL) error {
+	*r.written = append(*r.written, shortUrl)
+	return r.ShortenedURLRepository.Update(shortUrl)
+}
+
+func (r *recordingRepository) Replace(shortUrl *entity.ShortenedURL) error {
+	*r.written = append(*r.written, shortUrl)
+	return r.ShortenedURLRepository.Replace(shortUrl)
+}
+
+func (r *recordingRepository) SoftDelete(shortUrl *entity.ShortenedURL) error {
+	*r.written = append(*r.written, shortUrl)
+	return r.ShortenedURLRepository.SoftDelete(shortUrl)
+}
@@ -0,0 +1,16 @@
+package service
+
+import "net"
+
+// anonymizeIP masks the host part of an IP address, keeping the first three octets of IPv4
+// addresses and the first 48 bits of IPv6 ones. Values that are not IP addresses are dropped.
+func anonymizeIP(ip string) string {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return ""
+	}
+	if ipv4 := parsed.To4(); ipv4 != nil {
+		return ipv4.Mask(net.CIDRMask(24, 32)).String()
+	}
+	return parsed.Mask(net.CIDRMask(48, 128)).String()
+}
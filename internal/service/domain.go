@@ -0,0 +1,188 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lucasfarolfi/hire.me/internal/entity"
+	"gorm.io/gorm"
+)
+
+var ErrInvalidDomain = fmt.Errorf("domain must be a host name, optionally followed by a port")
+var ErrInvalidDomainScheme = fmt.Errorf("domain scheme must be http or https")
+var ErrDomainAlreadyExists = fmt.Errorf("domain already exists")
+var ErrDomainNotFound = fmt.Errorf("domain not found")
+
+const DefaultDomainRefreshInterval = time.Minute
+
+type DomainRepository interface {
+	Create(domain *entity.Domain) error
+	FindAll() ([]entity.Domain, error)
+	// CreateCanonical creates the domain and unsets the previous canonical domain of its tenant atomically.
+	CreateCanonical(domain *entity.Domain) error
+	DeleteByHost(host string) error
+}
+
+// DomainService is the registry of custom short domains. Lookups are served from a snapshot of the
+// registry reloaded every refresh interval, so resolving the host of a redirect does not query the
+// database and domains registered by another process are picked up within the interval.
+type DomainService struct {
+	repository      DomainRepository
+	refreshInterval time.Duration
+
+	mu        sync.RWMutex
+	byHost    map[string]entity.Domain
+	canonical map[int]entity.Domain
+	loadedAt  time.Time
+}
+
+func NewDomainService(repository DomainRepository, refreshInterval time.Duration) *DomainService {
+	return &DomainService{repository: repository, refreshInterval: refreshInterval}
+}
+
+// Add registers the host for the tenant. A canonical domain replaces the previous canonical domain of
+// the tenant as the one its short URLs are built on.
+func (s *DomainService) Add(host string, tenantID int, scheme string, canonical bool) (*entity.Domain, error) {
+	host, err := normalizeDomainHost(host)
+	if err != nil {
+		return nil, err
+	}
+	if scheme != "http" && scheme != "https" {
+		return nil, ErrInvalidDomainScheme
+	}
+	domain := &entity.Domain{Host: host, TenantID: tenantID, Scheme: scheme, Canonical: canonical, CreatedAt: time.Now().UTC()}
+	if canonical {
+		err = s.repository.CreateCanonical(domain)
+	} else {
+		err = s.repository.Create(domain)
+	}
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return nil, ErrDomainAlreadyExists
+	}
+	if err != nil {
+		return nil, err
+	}
+	s.invalidate()
+	return domain, nil
+}
+
+// List returns every registered domain in creation order.
+func (s *DomainService) List() ([]entity.Domain, error) {
+	return s.repository.FindAll()
+}
+
+// Remove unregisters the host, or returns ErrDomainNotFound.
+func (s *DomainService) Remove(host string) error {
+	host, err := normalizeDomainHost(host)
+	if err != nil {
+		return err
+	}
+	err = s.repository.DeleteByHost(host)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrDomainNotFound
+	}
+	if err != nil {
+		return err
+	}
+	s.invalidate()
+	return nil
+}
+
+// Resolve returns the domain registered for the Host header of a request, or nil when the host is not a
+// custom domain. A host with a port also matches the domain registered without it.
+func (s *DomainService) Resolve(host string) (*entity.Domain, error) {
+	byHost, _, err := s.snapshot()
+	if err != nil {
+		return nil, err
+	}
+	host, err = normalizeDomainHost(host)
+	if err != nil {
+		return nil, nil
+	}
+	if domain, ok := byHost[host]; ok {
+		return &domain, nil
+	}
+	if hostname, _, err := net.SplitHostPort(host); err == nil {
+		if domain, ok := byHost[hostname]; ok {
+			return &domain, nil
+		}
+	}
+	return nil, nil
+}
+
+// Canonical returns the canonical domain of the tenant, or nil when it has none.
+func (s *DomainService) Canonical(tenantID int) (*entity.Domain, error) {
+	_, canonical, err := s.snapshot()
+	if err != nil {
+		return nil, err
+	}
+	if domain, ok := canonical[tenantID]; ok {
+		return &domain, nil
+	}
+	return nil, nil
+}
+
+// snapshot returns the registry, reloading it when it is older than the refresh interval. A failed reload
+// keeps serving the previous snapshot until the next interval.
+func (s *DomainService) snapshot() (map[string]entity.Domain, map[int]entity.Domain, error) {
+	s.mu.RLock()
+	byHost, canonical, loadedAt := s.byHost, s.canonical, s.loadedAt
+	s.mu.RUnlock()
+	if !loadedAt.IsZero() && time.Since(loadedAt) < s.refreshInterval {
+		return byHost, canonical, nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.loadedAt.Equal(loadedAt) {
+		return s.byHost, s.canonical, nil
+	}
+	domains, err := s.repository.FindAll()
+	if err != nil {
+		if s.byHost == nil {
+			return nil, nil, err
+		}
+		log.Println("Failed to reload custom domains, keeping the previous ones:", err)
+		s.loadedAt = time.Now()
+		return s.byHost, s.canonical, nil
+	}
+	s.byHost = make(map[string]entity.Domain, len(domains))
+	s.canonical = make(map[int]entity.Domain)
+	for _, domain := range domains {
+		s.byHost[domain.Host] = domain
+		if _, ok := s.canonical[domain.TenantID]; domain.Canonical && !ok {
+			s.canonical[domain.TenantID] = domain
+		}
+	}
+	s.loadedAt = time.Now()
+	return s.byHost, s.canonical, nil
+}
+
+func (s *DomainService) invalidate() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.loadedAt = time.Time{}
+}
+
+// normalizeDomainHost lowercases the host and converts internationalized names to ASCII, so the Host
+// headers of requests match the registered domains.
+func normalizeDomainHost(host string) (string, error) {
+	host = strings.TrimSpace(host)
+	if host == "" || strings.ContainsAny(host, "/@?#") {
+		return "", ErrInvalidDomain
+	}
+	hostname, port := host, ""
+	if h, p, err := net.SplitHostPort(host); err == nil {
+		hostname, port = h, p
+	}
+	normalized, err := normalizeHost("", strings.ToLower(hostname), port)
+	if err != nil {
+		return "", ErrInvalidDomain
+	}
+	return normalized, nil
+}
@@ -0,0 +1,98 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lucasfarolfi/hire.me/infrastructure/repository/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDomainServiceMemory(t *testing.T) {
+	t.Run("Given invalid hosts or schemes, when a domain is added, then it should reject them", func(t *testing.T) {
+		service := NewDomainService(memory.NewDomainRepository(), time.Minute)
+
+		for _, host := range []string{"", "https://go.acme.io", "go.acme.io/path", "user@go.acme.io", "go.acme.io:99999"} {
+			_, err := service.Add(host, 0, "https", false)
+			assert.ErrorIs(t, err, ErrInvalidDomain, host)
+		}
+		_, err := service.Add("go.acme.io", 0, "ftp", false)
+		assert.ErrorIs(t, err, ErrInvalidDomainScheme)
+	})
+
+	t.Run("Given registered domains, when hosts are resolved, then they should match regardless of case and port", func(t *testing.T) {
+		service := NewDomainService(memory.NewDomainRepository(), time.Minute)
+		domain, err := service.Add(" Go.Acme.IO ", 3, "https", false)
+		assert.NoError(t, err)
+		assert.Equal(t, "go.acme.io", domain.Host)
+		_, err = service.Add("go.acme.io", 4, "https", false)
+		assert.ErrorIs(t, err, ErrDomainAlreadyExists)
+
+		for _, host := range []string{"go.acme.io", "GO.ACME.IO", "go.acme.io:8080"} {
+			resolved, err := service.Resolve(host)
+			assert.NoError(t, err)
+			assert.Equal(t, 3, resolved.TenantID, host)
+		}
+		resolved, err := service.Resolve("localhost:8080")
+		assert.NoError(t, err)
+		assert.Nil(t, resolved)
+
+		assert.NoError(t, service.Remove("go.acme.io"))
+		resolved, err = service.Resolve("go.acme.io")
+		assert.NoError(t, err)
+		assert.Nil(t, resolved)
+		assert.ErrorIs(t, service.Remove("go.acme.io"), ErrDomainNotFound)
+	})
+
+	t.Run("Given a tenant with canonical domains, when a new canonical domain is added, then it should replace the previous one", func(t *testing.T) {
+		service := NewDomainService(memory.NewDomainRepository(), time.Minute)
+		_, err := service.Add("go.acme.io", 3, "https", true)
+		assert.NoError(t, err)
+		canonical, err := service.Canonical(3)
+		assert.NoError(t, err)
+		assert.Equal(t, "go.acme.io", canonical.Host)
+
+		_, err = service.Add("acme.link", 3, "http", true)
+
+		assert.NoError(t, err)
+		canonical, err = service.Canonical(3)
+		assert.NoError(t, err)
+		assert.Equal(t, "acme.link", canonical.Host)
+		assert.Equal(t, "http", canonical.Scheme)
+		canonical, err = service.Canonical(0)
+		assert.NoError(t, err)
+		assert.Nil(t, canonical)
+	})
+
+	t.Run("Given a tenant with a canonical domain, when a duplicate host is added as canonical, then the previous one should remain canonical", func(t *testing.T) {
+		repository := memory.NewDomainRepository()
+		service := NewDomainService(repository, time.Minute)
+		_, err := service.Add("go.acme.io", 3, "https", true)
+		assert.NoError(t, err)
+		_, err = service.Add("acme.link", 4, "https", false)
+		assert.NoError(t, err)
+
+		_, err = service.Add("acme.link", 3, "https", true)
+
+		assert.ErrorIs(t, err, ErrDomainAlreadyExists)
+		canonical, err := NewDomainService(repository, time.Minute).Canonical(3)
+		assert.NoError(t, err)
+		assert.Equal(t, "go.acme.io", canonical.Host)
+	})
+
+	t.Run("Given a domain registered by another process, when the refresh interval passes, then it should be resolved", func(t *testing.T) {
+		repository := memory.NewDomainRepository()
+		service := NewDomainService(repository, 10*time.Millisecond)
+		resolved, err := service.Resolve("go.acme.io")
+		assert.NoError(t, err)
+		assert.Nil(t, resolved)
+
+		_, err = NewDomainService(repository, time.Minute).Add("go.acme.io", 3, "https", false)
+		assert.NoError(t, err)
+
+		assert.Eventually(t, func() bool {
+			resolved, err := service.Resolve("go.acme.io")
+			return err == nil && resolved != nil
+		}, time.Second, 5*time.Millisecond)
+	})
+}
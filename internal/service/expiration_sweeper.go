@@ -0,0 +1,77 @@
+package service
+
+import (
+	"log"
+	"sync"
+	"time"
+)
+
+// ExpirationSweeper periodically purges shortened URLs that expired more than a retention period ago.
+// Expired links are kept during the retention period so clients keep receiving a "link expired" error
+// instead of a "not found" one right after the expiration.
+type ExpirationSweeper struct {
+	repository ShortenedURLRepository
+	interval   time.Duration
+	retention  time.Duration
+	stop       chan struct{}
+	done       chan struct{}
+	mu         sync.Mutex
+}
+
+func NewExpirationSweeper(repository ShortenedURLRepository, interval, retention time.Duration) *ExpirationSweeper {
+	return &ExpirationSweeper{
+		repository: repository,
+		interval:   interval,
+		retention:  retention,
+	}
+}
+
+// Sweep deletes every shortened URL whose expiration is older than the retention period.
+func (s *ExpirationSweeper) Sweep() (int64, error) {
+	return s.repository.DeleteExpiredBefore(time.Now().UTC().Add(-s.retention))
+}
+
+// Start runs Sweep on every interval in a background goroutine until Stop is called.
+func (s *ExpirationSweeper) Start() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stop != nil {
+		return
+	}
+	s.stop = make(chan struct{})
+	s.done = make(chan struct{})
+	go s.run(s.stop, s.done)
+}
+
+func (s *ExpirationSweeper) run(stop, done chan struct{}) {
+	defer close(done)
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			purged, err := s.Sweep()
+			if err != nil {
+				log.Println("Failed to purge expired shortened URLs:", err)
+				continue
+			}
+			if purged > 0 {
+				log.Println("Purged expired shortened URLs:", purged)
+			}
+		case <-stop:
+			return
+		}
+	}
+}
+
+// Stop halts the background sweeping and waits for an in-flight sweep to finish.
+func (s *ExpirationSweeper) Stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stop == nil {
+		return
+	}
+	close(s.stop)
+	<-s.done
+	s.stop, s.done = nil, nil
+}
@@ -0,0 +1,47 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestExpirationSweeperUnit_Sweep(t *testing.T) {
+	t.Run("Given a retention period, when Sweep is called, then it should purge the urls expired before the retention period", func(t *testing.T) {
+		retention := 24 * time.Hour
+		repo := &MockShortenedURLRepository{}
+		repo.On("DeleteExpiredBefore", mock.MatchedBy(func(before time.Time) bool {
+			expected := time.Now().UTC().Add(-retention)
+			return before.Sub(expected).Abs() < time.Second
+		})).Return(int64(3), nil)
+		sweeper := NewExpirationSweeper(repo, time.Hour, retention)
+
+		purged, err := sweeper.Sweep()
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), purged)
+		repo.AssertExpectations(t)
+	})
+}
+
+func TestExpirationSweeperUnit_Start(t *testing.T) {
+	t.Run("Given a started sweeper, when the interval elapses, then it should sweep in background until stopped", func(t *testing.T) {
+		swept := make(chan struct{}, 10)
+		repo := &MockShortenedURLRepository{}
+		repo.On("DeleteExpiredBefore", mock.AnythingOfType("time.Time")).Return(int64(0), nil).Run(func(args mock.Arguments) {
+			swept <- struct{}{}
+		})
+		sweeper := NewExpirationSweeper(repo, 10*time.Millisecond, time.Hour)
+
+		sweeper.Start()
+		select {
+		case <-swept:
+		case <-time.After(time.Second):
+			t.Fatal("The sweeper should have swept at least once")
+		}
+		sweeper.Stop()
+		sweeper.Stop()
+	})
+}
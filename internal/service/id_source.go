@@ -0,0 +1,65 @@
+package service
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// AliasSequence names the ID sequence used to mint aliases.
+const AliasSequence = "alias"
+
+// IDSource hands out unique IDs.
+type IDSource interface {
+	NextID() (uint64, error)
+}
+
+type IDBlockRepository interface {
+	LeaseBlock(sequence string, size int64) (int64, error)
+}
+
+// BlockIDSource hands out IDs from blocks leased from the repository, so IDs stay unique across replicas
+// while only one lease per block hits the database. IDs left in a block when the app stops are skipped.
+type BlockIDSource struct {
+	repository IDBlockRepository
+	sequence   string
+	blockSize  int64
+
+	mu   sync.Mutex
+	next int64
+	end  int64
+}
+
+func NewBlockIDSource(repository IDBlockRepository, sequence string, blockSize int64) *BlockIDSource {
+	return &BlockIDSource{repository: repository, sequence: sequence, blockSize: blockSize}
+}
+
+func (s *BlockIDSource) NextID() (uint64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.next >= s.end {
+		start, err := s.repository.LeaseBlock(s.sequence, s.blockSize)
+		if err != nil {
+			return 0, err
+		}
+		s.next, s.end = start, start+s.blockSize
+	}
+	id := s.next
+	s.next++
+	return uint64(id), nil
+}
+
+// CounterIDSource is an in-process counter, unique only within a single instance of the app.
+type CounterIDSource struct {
+	counter atomic.Uint64
+}
+
+func NewCounterIDSource(start uint64) *CounterIDSource {
+	s := &CounterIDSource{}
+	s.counter.Store(start)
+	return s
+}
+
+func (s *CounterIDSource) NextID() (uint64, error) {
+	return s.counter.Add(1) - 1, nil
+}
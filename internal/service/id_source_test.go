@@ -0,0 +1,72 @@
+package service
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/lucasfarolfi/hire.me/infrastructure/repository/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBlockIDSourceUnit_NextID(t *testing.T) {
+	t.Run("Given a leased block, when IDs are requested, then it should only lease a new block once the current one is used up", func(t *testing.T) {
+		repo := &MockIDBlockRepository{}
+		repo.On("LeaseBlock", AliasSequence, int64(3)).Return(int64(1), nil).Once()
+		repo.On("LeaseBlock", AliasSequence, int64(3)).Return(int64(10), nil).Once()
+		source := NewBlockIDSource(repo, AliasSequence, 3)
+
+		var ids []uint64
+		for i := 0; i < 5; i++ {
+			id, err := source.NextID()
+			assert.NoError(t, err)
+			ids = append(ids, id)
+		}
+
+		assert.Equal(t, []uint64{1, 2, 3, 10, 11}, ids)
+		repo.AssertNumberOfCalls(t, "LeaseBlock", 2)
+	})
+
+	t.Run("Given a failing lease, when an ID is requested, then it should return the error and lease again on the next request", func(t *testing.T) {
+		repo := &MockIDBlockRepository{}
+		repo.On("LeaseBlock", AliasSequence, int64(3)).Return(int64(0), assert.AnError).Once()
+		repo.On("LeaseBlock", AliasSequence, int64(3)).Return(int64(7), nil).Once()
+		source := NewBlockIDSource(repo, AliasSequence, 3)
+
+		_, err := source.NextID()
+		assert.ErrorIs(t, err, assert.AnError)
+		id, err := source.NextID()
+
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(7), id)
+	})
+
+	t.Run("Given several replicas sharing the block store, when IDs are requested concurrently, then no ID should be handed out twice", func(t *testing.T) {
+		blocks := memory.NewIDBlockRepository()
+		replicas := []*BlockIDSource{
+			NewBlockIDSource(blocks, AliasSequence, 7),
+			NewBlockIDSource(blocks, AliasSequence, 7),
+			NewBlockIDSource(blocks, AliasSequence, 7),
+		}
+
+		var mu sync.Mutex
+		var wg sync.WaitGroup
+		seen := make(map[uint64]bool)
+		for i := 0; i < 30; i++ {
+			for _, replica := range replicas {
+				wg.Add(1)
+				go func(source *BlockIDSource) {
+					defer wg.Done()
+					id, err := source.NextID()
+					assert.NoError(t, err)
+					mu.Lock()
+					defer mu.Unlock()
+					assert.False(t, seen[id], "ID %d was handed out twice", id)
+					seen[id] = true
+				}(replica)
+			}
+		}
+		wg.Wait()
+
+		assert.Len(t, seen, 90)
+	})
+}
@@ -0,0 +1,103 @@
+package service
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/lucasfarolfi/hire.me/internal/entity"
+	"golang.org/x/net/idna"
+)
+
+var ErrInvalidLinkQuery = fmt.Errorf("invalid link query")
+
+const (
+	DefaultLinkPageLimit = 50
+	MaxLinkPageLimit     = 1000
+)
+
+// LinkPage is a page of ListLinks. NextCursor is empty on the last page.
+type LinkPage struct {
+	Links      []entity.ShortenedURL
+	NextCursor string
+}
+
+// linkCursorToken is the content of the opaque cursor handed to clients. It carries the sort it was
+// created for, so it cannot be replayed against a different order.
+type linkCursorToken struct {
+	SortBy     string `json:"sort"`
+	Descending bool   `json:"desc,omitempty"`
+	entity.LinkCursor
+}
+
+// ListLinks returns a page of the shortened URLs of the tenant of the service matching the query, starting
+// after the given cursor (empty for the first page). A zero limit means DefaultLinkPageLimit and an empty
+// sort means newest first.
+func (s *URLShortenerService) ListLinks(query entity.LinkQuery, cursor string) (*LinkPage, error) {
+	query.TenantID = s.TenantID()
+	if query.Limit == 0 {
+		query.Limit = DefaultLinkPageLimit
+	}
+	if query.Limit < 0 || query.Limit > MaxLinkPageLimit || query.MinAccessTimes < 0 {
+		return nil, ErrInvalidLinkQuery
+	}
+	if query.SortBy == "" {
+		query.SortBy, query.Descending = entity.LinkSortCreatedAt, true
+	}
+	switch query.SortBy {
+	case entity.LinkSortCreatedAt, entity.LinkSortAccessTimes, entity.LinkSortAlias:
+	default:
+		return nil, ErrInvalidLinkQuery
+	}
+	if query.CreatedFrom != nil && query.CreatedTo != nil && !query.CreatedFrom.Before(*query.CreatedTo) {
+		return nil, ErrInvalidLinkQuery
+	}
+	if query.Domain != "" {
+		domain, err := idna.Lookup.ToASCII(strings.ToLower(strings.TrimSpace(query.Domain)))
+		if err != nil {
+			return nil, ErrInvalidLinkQuery
+		}
+		query.Domain = domain
+	}
+	if cursor != "" {
+		after, err := decodeLinkCursor(cursor, query)
+		if err != nil {
+			return nil, err
+		}
+		query.After = after
+	}
+
+	limit := query.Limit
+	query.Limit++
+	links, err := s.Repository.FindLinks(query)
+	if err != nil {
+		return nil, err
+	}
+	page := &LinkPage{Links: links}
+	if len(links) > limit {
+		page.Links = links[:limit]
+		page.NextCursor = encodeLinkCursor(query, query.CursorOf(&page.Links[limit-1]))
+	}
+	return page, nil
+}
+
+func encodeLinkCursor(query entity.LinkQuery, cursor *entity.LinkCursor) string {
+	encoded, _ := json.Marshal(linkCursorToken{SortBy: query.SortBy, Descending: query.Descending, LinkCursor: *cursor})
+	return base64.RawURLEncoding.EncodeToString(encoded)
+}
+
+func decodeLinkCursor(cursor string, query entity.LinkQuery) (*entity.LinkCursor, error) {
+	decoded, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, ErrInvalidLinkQuery
+	}
+	var token linkCursorToken
+	if err := json.Unmarshal(decoded, &token); err != nil {
+		return nil, ErrInvalidLinkQuery
+	}
+	if token.SortBy != query.SortBy || token.Descending != query.Descending || token.ID <= 0 {
+		return nil, ErrInvalidLinkQuery
+	}
+	return &token.LinkCursor, nil
+}
@@ -0,0 +1,164 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/lucasfarolfi/hire.me/internal/entity"
+)
+
+var ErrInvalidStatsQuery = fmt.Errorf("invalid statistics query")
+var ErrClickEventsDisabled = fmt.Errorf("click events are not recorded")
+
+const (
+	StatsIntervalHour = "hour"
+	StatsIntervalDay  = "day"
+	StatsIntervalWeek = "week"
+
+	maxStatsBuckets  = 1000
+	maxTopReferrers  = 10
+	directReferrer   = "(direct)"
+	unknownUserAgent = "Other"
+)
+
+type LinkStats struct {
+	Alias             string
+	TotalClicks       int64
+	From              time.Time
+	To                time.Time
+	Interval          string
+	RangeClicks       int64
+	UniqueVisitors    int64
+	Buckets           []StatsBucket
+	TopReferrers      []entity.ClickCount
+	UserAgentFamilies []entity.ClickCount
+}
+
+type StatsBucket struct {
+	Start  time.Time
+	Clicks int64
+}
+
+// GetStatsByAlias summarizes the click events of a shortened URL between from (inclusive) and to (exclusive),
+// bucketing the clicks by the given interval.
+func (s *URLShortenerService) GetStatsByAlias(alias string, from, to time.Time, interval string) (*LinkStats, error) {
+	if s.ClickEventRepository == nil {
+		return nil, ErrClickEventsDisabled
+	}
+	from, to = from.UTC(), to.UTC()
+	if !from.Before(to) {
+		return nil, ErrInvalidStatsQuery
+	}
+	buckets, step, err := newStatsBuckets(from, to, interval)
+	if err != nil {
+		return nil, err
+	}
+
+	shortUrl, err := s.findActiveByAlias(alias)
+	if err != nil {
+		return nil, err
+	}
+
+	bucketCounts, err := s.ClickEventRepository.CountByBucket(shortUrl.ID, from, to, buckets[0].Start, step)
+	if err != nil {
+		return nil, err
+	}
+	var rangeClicks int64
+	for i, count := range bucketCounts {
+		if i >= 0 && i < len(buckets) {
+			buckets[i].Clicks = count
+		}
+		rangeClicks += count
+	}
+
+	uniqueVisitors, err := s.ClickEventRepository.CountUniqueVisitors(shortUrl.ID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	referrers, err := s.ClickEventRepository.CountByReferrer(shortUrl.ID, from, to, maxTopReferrers)
+	if err != nil {
+		return nil, err
+	}
+	for i := range referrers {
+		if referrers[i].Value == "" {
+			referrers[i].Value = directReferrer
+		}
+	}
+
+	userAgents, err := s.ClickEventRepository.CountByUserAgent(shortUrl.ID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return &LinkStats{
+		Alias:             shortUrl.Alias,
+		TotalClicks:       int64(shortUrl.AccessTimes),
+		From:              from,
+		To:                to,
+		Interval:          interval,
+		RangeClicks:       rangeClicks,
+		UniqueVisitors:    uniqueVisitors,
+		Buckets:           buckets,
+		TopReferrers:      referrers,
+		UserAgentFamilies: groupByUserAgentFamily(userAgents),
+	}, nil
+}
+
+func newStatsBuckets(from, to time.Time, interval string) ([]StatsBucket, time.Duration, error) {
+	var step time.Duration
+	switch interval {
+	case StatsIntervalHour:
+		step = time.Hour
+	case StatsIntervalDay:
+		step = 24 * time.Hour
+	case StatsIntervalWeek:
+		step = 7 * 24 * time.Hour
+	default:
+		return nil, 0, ErrInvalidStatsQuery
+	}
+
+	start := truncateToInterval(from, interval)
+	if int(to.Sub(start)/step) >= maxStatsBuckets {
+		return nil, 0, ErrInvalidStatsQuery
+	}
+
+	var buckets []StatsBucket
+	for bucketStart := start; bucketStart.Before(to); bucketStart = bucketStart.Add(step) {
+		buckets = append(buckets, StatsBucket{Start: bucketStart})
+	}
+	return buckets, step, nil
+}
+
+// truncateToInterval aligns t to the start of its hour, day or ISO week (monday) in UTC.
+func truncateToInterval(t time.Time, interval string) time.Time {
+	switch interval {
+	case StatsIntervalHour:
+		return t.Truncate(time.Hour)
+	case StatsIntervalWeek:
+		day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+		daysSinceMonday := (int(day.Weekday()) + 6) % 7
+		return day.AddDate(0, 0, -daysSinceMonday)
+	default:
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	}
+}
+
+func groupByUserAgentFamily(userAgents []entity.ClickCount) []entity.ClickCount {
+	var families []entity.ClickCount
+	positions := make(map[string]int)
+	for _, userAgent := range userAgents {
+		family := userAgentFamily(userAgent.Value)
+		if i, ok := positions[family]; ok {
+			families[i].Count += userAgent.Count
+			continue
+		}
+		positions[family] = len(families)
+		families = append(families, entity.ClickCount{Value: family, Count: userAgent.Count})
+	}
+	sort.SliceStable(families, func(i, j int) bool {
+		return families[i].Count > families[j].Count
+	})
+	return families
+}
@@ -0,0 +1,96 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lucasfarolfi/hire.me/internal/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestShortenerServiceUnit_GetStatsByAlias(t *testing.T) {
+	from := time.Date(2025, 1, 6, 10, 30, 0, 0, time.UTC)
+	to := time.Date(2025, 1, 8, 12, 0, 0, 0, time.UTC)
+
+	t.Run("Given recorded click events, when GetStatsByAlias is called with a daily interval, then it should bucket the clicks by day", func(t *testing.T) {
+		repo := &MockShortenedURLRepository{}
+		repo.On("FindByAlias", 0, "abc123").Return(&entity.ShortenedURL{ID: 1, Alias: "abc123", AccessTimes: 42}, nil)
+		clickRepo := &MockClickEventRepository{}
+		clickRepo.On("CountByBucket", 1, from, to, time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC), 24*time.Hour).
+			Return(map[int]int64{0: 1, 1: 2, 2: 1}, nil)
+		clickRepo.On("CountUniqueVisitors", 1, from, to).Return(int64(3), nil)
+		clickRepo.On("CountByReferrer", 1, from, to, maxTopReferrers).Return([]entity.ClickCount{
+			{Value: "http://www.google.com", Count: 3}, {Value: "", Count: 1},
+		}, nil)
+		clickRepo.On("CountByUserAgent", 1, from, to).Return([]entity.ClickCount{
+			{Value: "curl/8.0", Count: 1},
+			{Value: "Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0 Safari/537.36", Count: 2},
+			{Value: "Mozilla/5.0 (Windows NT 10.0) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0 Safari/537.36 Edg/120.0", Count: 1},
+			{Value: "Mozilla/5.0 (Macintosh) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/119.0 Safari/537.36", Count: 1},
+		}, nil)
+		service := NewURLShortenerService(repo, WithClickEventRepository(clickRepo))
+
+		stats, err := service.GetStatsByAlias("abc123", from, to, StatsIntervalDay)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(42), stats.TotalClicks, "Total clicks should come from the access times")
+		assert.Equal(t, int64(4), stats.RangeClicks)
+		assert.Equal(t, int64(3), stats.UniqueVisitors)
+		assert.Equal(t, []StatsBucket{
+			{Start: time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC), Clicks: 1},
+			{Start: time.Date(2025, 1, 7, 0, 0, 0, 0, time.UTC), Clicks: 2},
+			{Start: time.Date(2025, 1, 8, 0, 0, 0, 0, time.UTC), Clicks: 1},
+		}, stats.Buckets)
+		assert.Equal(t, []entity.ClickCount{{Value: "http://www.google.com", Count: 3}, {Value: directReferrer, Count: 1}}, stats.TopReferrers)
+		assert.Equal(t, []entity.ClickCount{{Value: "Chrome", Count: 3}, {Value: "curl", Count: 1}, {Value: "Edge", Count: 1}}, stats.UserAgentFamilies)
+	})
+
+	t.Run("Given a weekly interval, when GetStatsByAlias is called, then the buckets should start on mondays", func(t *testing.T) {
+		repo := &MockShortenedURLRepository{}
+		repo.On("FindByAlias", 0, "abc123").Return(&entity.ShortenedURL{ID: 1, Alias: "abc123"}, nil)
+		clickRepo := &MockClickEventRepository{}
+		weekFrom := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+		weekTo := time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC)
+		clickRepo.On("CountByBucket", 1, weekFrom, weekTo, time.Date(2024, 12, 30, 0, 0, 0, 0, time.UTC), 7*24*time.Hour).
+			Return(map[int]int64{0: 1}, nil)
+		clickRepo.On("CountUniqueVisitors", 1, weekFrom, weekTo).Return(int64(1), nil)
+		clickRepo.On("CountByReferrer", 1, weekFrom, weekTo, maxTopReferrers).Return([]entity.ClickCount{}, nil)
+		clickRepo.On("CountByUserAgent", 1, weekFrom, weekTo).Return([]entity.ClickCount{}, nil)
+		service := NewURLShortenerService(repo, WithClickEventRepository(clickRepo))
+
+		stats, err := service.GetStatsByAlias("abc123", weekFrom, weekTo, StatsIntervalWeek)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []StatsBucket{
+			{Start: time.Date(2024, 12, 30, 0, 0, 0, 0, time.UTC), Clicks: 1},
+			{Start: time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC), Clicks: 0},
+			{Start: time.Date(2025, 1, 13, 0, 0, 0, 0, time.UTC), Clicks: 0},
+		}, stats.Buckets)
+	})
+
+	t.Run("Given an invalid interval or range, when GetStatsByAlias is called, then it should return an invalid query error", func(t *testing.T) {
+		repo := &MockShortenedURLRepository{}
+		service := NewURLShortenerService(repo, WithClickEventRepository(&MockClickEventRepository{}))
+
+		_, err := service.GetStatsByAlias("abc123", from, to, "month")
+		assert.ErrorIs(t, err, ErrInvalidStatsQuery)
+
+		_, err = service.GetStatsByAlias("abc123", to, from, StatsIntervalDay)
+		assert.ErrorIs(t, err, ErrInvalidStatsQuery)
+
+		_, err = service.GetStatsByAlias("abc123", from.AddDate(-1, 0, 0), to, StatsIntervalHour)
+		assert.ErrorIs(t, err, ErrInvalidStatsQuery, "Ranges with too many buckets should be rejected")
+
+		repo.AssertNotCalled(t, "FindByAlias", mock.Anything)
+	})
+}
+
+func TestShortenerServiceUnit_UserAgentFamily(t *testing.T) {
+	t.Run("Should classify user agents into their browser families", func(t *testing.T) {
+		assert.Equal(t, "Firefox", userAgentFamily("Mozilla/5.0 (X11; Linux x86_64; rv:121.0) Gecko/20100101 Firefox/121.0"))
+		assert.Equal(t, "Safari", userAgentFamily("Mozilla/5.0 (Macintosh) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.1 Safari/605.1.15"))
+		assert.Equal(t, "Bot", userAgentFamily("Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)"))
+		assert.Equal(t, "Other", userAgentFamily(""))
+	})
+}
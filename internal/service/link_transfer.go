@@ -0,0 +1,401 @@
+package service
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/lucasfarolfi/hire.me/internal/entity"
+	"gorm.io/gorm"
+)
+
+var ErrInvalidTransferFormat = fmt.Errorf("format must be csv or ndjson")
+var ErrInvalidConflictPolicy = fmt.Errorf("conflict policy must be skip, overwrite or fail")
+var ErrInvalidImportRecord = fmt.Errorf("invalid import record")
+var ErrImportConflict = fmt.Errorf("alias already exists")
+
+const (
+	TransferFormatCSV    = "csv"
+	TransferFormatNDJSON = "ndjson"
+
+	ConflictSkip      = "skip"
+	ConflictOverwrite = "overwrite"
+	ConflictFail      = "fail"
+
+	exportBatchSize = 500
+)
+
+// LinkRecord is the portable form of a shortened URL written by ExportLinks and read by ImportLinks.
+type LinkRecord struct {
+	Alias          string     `json:"alias"`
+	URL            string     `json:"url"`
+	AccessTimes    int32      `json:"access_times"`
+	MaxAccessTimes *int32     `json:"max_access_times,omitempty"`
+	RedirectType   int        `json:"redirect_type,omitempty"`
+	ExpiresAt      *time.Time `json:"expires_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	CustomAlias    bool       `json:"custom_alias"`
+	DeletedAt      *time.Time `json:"deleted_at,omitempty"`
+	OwnerID        string     `json:"owner_id,omitempty"`
+}
+
+// linkRecordColumns is the CSV header of exports. Imports accept the columns in any order, but require
+// alias and url.
+var linkRecordColumns = []string{"alias", "url", "access_times", "max_access_times", "redirect_type",
+	"expires_at", "created_at", "custom_alias", "deleted_at", "owner_id"}
+
+// ImportSummary counts what ImportLinks did with the records of committed batches.
+type ImportSummary struct {
+	Created     int
+	Overwritten int
+	Skipped     int
+}
+
+// ImportError reports the record, counted from 1, that stopped an import.
+type ImportError struct {
+	Record int
+	Alias  string
+	Err    error
+}
+
+func (e *ImportError) Error() string {
+	return fmt.Sprintf("record %d (alias %q): %v", e.Record, e.Alias, e.Err)
+}
+
+func (e *ImportError) Unwrap() error {
+	return e.Err
+}
+
+// ExportLinks streams every shortened URL of the tenant of the service, including deleted ones, to w in
+// the given format, in creation order. It returns the number of exported records.
+func (s *URLShortenerService) ExportLinks(w io.Writer, format string) (int, error) {
+	var write func(record LinkRecord) error
+	var flush func() error
+	switch format {
+	case TransferFormatCSV:
+		writer := csv.NewWriter(w)
+		if err := writer.Write(linkRecordColumns); err != nil {
+			return 0, err
+		}
+		write = func(record LinkRecord) error { return writer.Write(record.csvRow()) }
+		flush = func() error { writer.Flush(); return writer.Error() }
+	case TransferFormatNDJSON:
+		encoder := json.NewEncoder(w)
+		write = func(record LinkRecord) error { return encoder.Encode(record) }
+		flush = func() error { return nil }
+	default:
+		return 0, ErrInvalidTransferFormat
+	}
+
+	exported := 0
+	query := entity.LinkQuery{TenantID: s.TenantID(), IncludeDeleted: true, SortBy: entity.LinkSortCreatedAt, Limit: exportBatchSize}
+	for {
+		links, err := s.Repository.FindLinks(query)
+		if err != nil {
+			return exported, err
+		}
+		for i := range links {
+			if err := write(newLinkRecord(&links[i])); err != nil {
+				return exported, err
+			}
+			exported++
+		}
+		if len(links) < query.Limit {
+			return exported, flush()
+		}
+		query.After = query.CursorOf(&links[len(links)-1])
+	}
+}
+
+// ImportLinks reads shortened URLs in the given format from r and stores them in transactions of the
+// configured batch size. Records with an alias that already exists are skipped, overwritten or stop the
+// import according to the conflict policy. An invalid record or a conflict under ConflictFail stops the
+// import with an *ImportError, rolling back its batch; earlier batches stay imported.
+func (s *URLShortenerService) ImportLinks(r io.Reader, format, onConflict string) (*ImportSummary, error) {
+	switch onConflict {
+	case ConflictSkip, ConflictOverwrite, ConflictFail:
+	default:
+		return nil, ErrInvalidConflictPolicy
+	}
+	read, err := newLinkRecordReader(r, format)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &ImportSummary{}
+	record := 0
+	for done := false; !done; {
+		var batch []LinkRecord
+		for len(batch) < s.bulkBatchSize {
+			linkRecord, err := read()
+			if errors.Is(err, io.EOF) {
+				done = true
+				break
+			}
+			if err != nil {
+				return summary, &ImportError{Record: record + len(batch) + 1, Err: fmt.Errorf("%w: %w", ErrInvalidImportRecord, err)}
+			}
+			batch = append(batch, linkRecord)
+		}
+
+		var batchSummary ImportSummary
+		err := s.inTransaction(func(tx *URLShortenerService) error {
+			for i, linkRecord := range batch {
+				if err := tx.importLink(linkRecord, onConflict, &batchSummary); err != nil {
+					return &ImportError{Record: record + i + 1, Alias: linkRecord.Alias, Err: err}
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return summary, err
+		}
+		record += len(batch)
+		summary.Created += batchSummary.Created
+		summary.Overwritten += batchSummary.Overwritten
+		summary.Skipped += batchSummary.Skipped
+	}
+	return summary, nil
+}
+
+func (s *URLShortenerService) importLink(record LinkRecord, onConflict string, summary *ImportSummary) error {
+	shortUrl, err := s.newImportedShortenedURL(record)
+	if err != nil {
+		return err
+	}
+
+	existing, err := s.Repository.FindByAlias(shortUrl.TenantID, shortUrl.Alias)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		if err := s.checkQuota(); err != nil {
+			return err
+		}
+		if err := s.Repository.Create(shortUrl); err != nil {
+			if errors.Is(err, gorm.ErrDuplicatedKey) {
+				return ErrImportConflict
+			}
+			return err
+		}
+		summary.Created++
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	switch onConflict {
+	case ConflictSkip:
+		summary.Skipped++
+		return nil
+	case ConflictOverwrite:
+		shortUrl.ID = existing.ID
+		if err := s.Repository.Replace(shortUrl); err != nil {
+			return err
+		}
+		summary.Overwritten++
+		return nil
+	}
+	return ErrImportConflict
+}
+
+// newImportedShortenedURL validates the record with the same URL and custom alias rules as Create.
+func (s *URLShortenerService) newImportedShortenedURL(record LinkRecord) (*entity.ShortenedURL, error) {
+	if record.Alias == "" {
+		return nil, fmt.Errorf("%w: alias is required", ErrInvalidImportRecord)
+	}
+	url, err := s.normalizeURL(record.URL)
+	if err != nil {
+		return nil, err
+	}
+	// Generated aliases may not follow the custom alias rules, e.g. mixed case under AliasCaseLower.
+	alias := record.Alias
+	if record.CustomAlias {
+		if alias, err = s.aliasPolicy.Apply(record.Alias); err != nil {
+			return nil, err
+		}
+	}
+	if record.RedirectType != 0 && !entity.IsValidRedirectType(record.RedirectType) {
+		return nil, ErrInvalidRedirectType
+	}
+	if record.MaxAccessTimes != nil && *record.MaxAccessTimes <= 0 {
+		return nil, ErrInvalidMaxAccessTimes
+	}
+	if len(record.OwnerID) > maxOwnerIDLength {
+		return nil, fmt.Errorf("%w: owner_id is too long", ErrInvalidImportRecord)
+	}
+	if record.AccessTimes < 0 {
+		return nil, fmt.Errorf("%w: access_times must not be negative", ErrInvalidImportRecord)
+	}
+
+	shortUrl := entity.NewShortenedURL(alias, url)
+	shortUrl.AccessTimes = record.AccessTimes
+	shortUrl.MaxAccessTimes = record.MaxAccessTimes
+	shortUrl.RedirectType = record.RedirectType
+	shortUrl.ExpiresAt = utcPointer(record.ExpiresAt)
+	shortUrl.CreatedAt = record.CreatedAt.UTC()
+	shortUrl.CustomAlias = record.CustomAlias
+	shortUrl.DeletedAt = utcPointer(record.DeletedAt)
+	shortUrl.OwnerID = record.OwnerID
+	shortUrl.TenantID = s.TenantID()
+	if shortUrl.CreatedAt.IsZero() {
+		shortUrl.CreatedAt = time.Now().UTC()
+	}
+	return shortUrl, nil
+}
+
+func utcPointer(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	utc := t.UTC()
+	return &utc
+}
+
+func newLinkRecord(shortUrl *entity.ShortenedURL) LinkRecord {
+	return LinkRecord{
+		Alias:          shortUrl.Alias,
+		URL:            shortUrl.Url,
+		AccessTimes:    shortUrl.AccessTimes,
+		MaxAccessTimes: shortUrl.MaxAccessTimes,
+		RedirectType:   shortUrl.RedirectType,
+		ExpiresAt:      shortUrl.ExpiresAt,
+		CreatedAt:      shortUrl.CreatedAt,
+		CustomAlias:    shortUrl.CustomAlias,
+		DeletedAt:      shortUrl.DeletedAt,
+		OwnerID:        shortUrl.OwnerID,
+	}
+}
+
+func (r LinkRecord) csvRow() []string {
+	row := []string{r.Alias, r.URL, strconv.Itoa(int(r.AccessTimes)), "", "", "", r.CreatedAt.UTC().Format(time.RFC3339Nano),
+		strconv.FormatBool(r.CustomAlias), "", r.OwnerID}
+	if r.MaxAccessTimes != nil {
+		row[3] = strconv.Itoa(int(*r.MaxAccessTimes))
+	}
+	if r.RedirectType != 0 {
+		row[4] = strconv.Itoa(r.RedirectType)
+	}
+	if r.ExpiresAt != nil {
+		row[5] = r.ExpiresAt.UTC().Format(time.RFC3339Nano)
+	}
+	if r.DeletedAt != nil {
+		row[8] = r.DeletedAt.UTC().Format(time.RFC3339Nano)
+	}
+	return row
+}
+
+// newLinkRecordReader returns a function reading the next record of r, or io.EOF after the last one.
+func newLinkRecordReader(r io.Reader, format string) (func() (LinkRecord, error), error) {
+	switch format {
+	case TransferFormatNDJSON:
+		decoder := json.NewDecoder(r)
+		decoder.DisallowUnknownFields()
+		return func() (LinkRecord, error) {
+			var record LinkRecord
+			err := decoder.Decode(&record)
+			return record, err
+		}, nil
+	case TransferFormatCSV:
+		reader := csv.NewReader(r)
+		reader.FieldsPerRecord = -1
+		header, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return func() (LinkRecord, error) { return LinkRecord{}, io.EOF }, nil
+		}
+		if err != nil {
+			return nil, &ImportError{Record: 0, Err: fmt.Errorf("%w: %w", ErrInvalidImportRecord, err)}
+		}
+		columns := make(map[string]int, len(header))
+		for i, name := range header {
+			columns[name] = i
+		}
+		if _, ok := columns["alias"]; !ok {
+			return nil, &ImportError{Record: 0, Err: fmt.Errorf("%w: missing alias column", ErrInvalidImportRecord)}
+		}
+		if _, ok := columns["url"]; !ok {
+			return nil, &ImportError{Record: 0, Err: fmt.Errorf("%w: missing url column", ErrInvalidImportRecord)}
+		}
+		return func() (LinkRecord, error) {
+			row, err := reader.Read()
+			if err != nil {
+				return LinkRecord{}, err
+			}
+			if len(row) != len(header) {
+				return LinkRecord{}, fmt.Errorf("expected %d columns, got %d", len(header), len(row))
+			}
+			return parseLinkRecordRow(row, columns)
+		}, nil
+	}
+	return nil, ErrInvalidTransferFormat
+}
+
+func parseLinkRecordRow(row []string, columns map[string]int) (LinkRecord, error) {
+	value := func(name string) string {
+		if i, ok := columns[name]; ok {
+			return row[i]
+		}
+		return ""
+	}
+	record := LinkRecord{Alias: value("alias"), URL: value("url"), OwnerID: value("owner_id")}
+	if v := value("access_times"); v != "" {
+		accessTimes, err := strconv.ParseInt(v, 10, 32)
+		if err != nil {
+			return record, err
+		}
+		record.AccessTimes = int32(accessTimes)
+	}
+	if v := value("max_access_times"); v != "" {
+		maxAccessTimes, err := strconv.ParseInt(v, 10, 32)
+		if err != nil {
+			return record, err
+		}
+		limit := int32(maxAccessTimes)
+		record.MaxAccessTimes = &limit
+	}
+	if v := value("redirect_type"); v != "" {
+		redirectType, err := strconv.Atoi(v)
+		if err != nil {
+			return record, err
+		}
+		record.RedirectType = redirectType
+	}
+	if v := value("custom_alias"); v != "" {
+		customAlias, err := strconv.ParseBool(v)
+		if err != nil {
+			return record, err
+		}
+		record.CustomAlias = customAlias
+	}
+	var err error
+	if record.CreatedAt, err = parseOptionalTime(value("created_at")); err != nil {
+		return record, err
+	}
+	if record.ExpiresAt, err = parseOptionalTimePointer(value("expires_at")); err != nil {
+		return record, err
+	}
+	if record.DeletedAt, err = parseOptionalTimePointer(value("deleted_at")); err != nil {
+		return record, err
+	}
+	return record, nil
+}
+
+func parseOptionalTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339Nano, value)
+}
+
+func parseOptionalTimePointer(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
@@ -0,0 +1,180 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lucasfarolfi/hire.me/infrastructure/repository/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+// invalidatingRepository records the aliases invalidated after transactions, like the cache does.
+type invalidatingRepository struct {
+	ShortenedURLRepository
+	invalidated []string
+}
+
+func (r *invalidatingRepository) Invalidate(tenantID int, alias string) {
+	r.invalidated = append(r.invalidated, alias)
+}
+
+func TestShortenerServiceMemory_BulkCreate(t *testing.T) {
+	t.Run("Given valid and invalid items, when BulkCreate is called, then it should create the valid ones and report each error", func(t *testing.T) {
+		service := newMemoryService()
+
+		results := service.BulkCreate([]BulkItem{
+			{Alias: "first", URL: "http://www.bemobi.com.br"},
+			{URL: "javascript:alert(1)"},
+			{Alias: "first", URL: "http://www.example.com"},
+			{URL: "http://www.example.com", Options: []CreateOption{WithRedirectType(301)}},
+		})
+
+		assert.Len(t, results, 4)
+		assert.NoError(t, results[0].Err)
+		assert.Equal(t, "first", results[0].ShortenedURL.Alias)
+		assert.ErrorIs(t, results[1].Err, ErrURLSchemeNotAllowed)
+		assert.ErrorIs(t, results[2].Err, ErrAliasAlreadyExists)
+		assert.NoError(t, results[3].Err)
+		assert.Equal(t, 301, results[3].ShortenedURL.RedirectType)
+		assert.True(t, service.ExistsByAlias(results[3].ShortenedURL.Alias))
+	})
+
+	t.Run("Given a transactor, when BulkCreate is called, then it should write the items in batches and invalidate the written aliases", func(t *testing.T) {
+		repository := &invalidatingRepository{ShortenedURLRepository: memory.NewShortenedURLRepository(nil)}
+		transactions := 0
+		service := NewURLShortenerService(repository, WithBulkBatchSize(2),
+			WithTransactor(func(fn func(ShortenedURLRepository) error) error {
+				transactions++
+				return fn(repository.ShortenedURLRepository)
+			}))
+
+		results := service.BulkCreate([]BulkItem{
+			{Alias: "first", URL: "http://www.bemobi.com.br"},
+			{Alias: "second", URL: "http://www.bemobi.com.br"},
+			{Alias: "third", URL: "http://www.bemobi.com.br"},
+		})
+
+		for _, result := range results {
+			assert.NoError(t, result.Err)
+		}
+		assert.Equal(t, 2, transactions)
+		assert.Equal(t, []string{"first", "second", "third"}, repository.invalidated)
+	})
+
+	t.Run("Given a batch that cannot be committed, when BulkCreate is called, then all of its items should fail", func(t *testing.T) {
+		failure := fmt.Errorf("commit failed")
+		service := NewURLShortenerService(memory.NewShortenedURLRepository(nil),
+			WithTransactor(func(fn func(ShortenedURLRepository) error) error {
+				fn(memory.NewShortenedURLRepository(nil))
+				return failure
+			}))
+
+		results := service.BulkCreate([]BulkItem{
+			{Alias: "first", URL: "http://www.bemobi.com.br"},
+			{URL: "javascript:alert(1)"},
+		})
+
+		assert.ErrorIs(t, results[0].Err, failure)
+		assert.Nil(t, results[0].ShortenedURL)
+		assert.ErrorIs(t, results[1].Err, ErrURLSchemeNotAllowed)
+	})
+}
+
+func TestShortenerServiceMemory_ExportAndImport(t *testing.T) {
+	for _, format := range []string{TransferFormatCSV, TransferFormatNDJSON} {
+		t.Run(fmt.Sprintf("Given stored links, when they are exported as %s and imported elsewhere, then every field should be kept", format), func(t *testing.T) {
+			source := newMemoryService(WithBulkBatchSize(2))
+			expiresAt := time.Now().UTC().Add(time.Hour).Truncate(time.Second)
+			_, err := source.Create("first", "http://www.bemobi.com.br", WithRedirectType(301), WithExpiresAt(expiresAt), WithMaxAccessTimes(5))
+			assert.NoError(t, err)
+			_, err = source.Create("second", "http://www.example.com/a,b", WithOwner("alice"))
+			assert.NoError(t, err)
+			_, err = source.Create("third", "http://www.example.com")
+			assert.NoError(t, err)
+			_, err = source.RetrieveByAlias("first", nil)
+			assert.NoError(t, err)
+			assert.NoError(t, source.Delete("third"))
+
+			var exported bytes.Buffer
+			count, err := source.ExportLinks(&exported, format)
+			assert.NoError(t, err)
+			assert.Equal(t, 3, count)
+
+			target := newMemoryService(WithBulkBatchSize(2))
+			summary, err := target.ImportLinks(&exported, format, ConflictFail)
+			assert.NoError(t, err)
+			assert.Equal(t, &ImportSummary{Created: 3}, summary)
+
+			for _, alias := range []string{"first", "second", "third"} {
+				original, err := source.Repository.FindByAlias(0, alias)
+				assert.NoError(t, err)
+				imported, err := target.Repository.FindByAlias(0, alias)
+				assert.NoError(t, err)
+				assert.Equal(t, original.Url, imported.Url)
+				assert.Equal(t, original.URLHash, imported.URLHash)
+				assert.Equal(t, original.AccessTimes, imported.AccessTimes)
+				assert.Equal(t, original.MaxAccessTimes, imported.MaxAccessTimes)
+				assert.Equal(t, original.RedirectType, imported.RedirectType)
+				assert.Equal(t, original.CustomAlias, imported.CustomAlias)
+				assert.True(t, original.CreatedAt.Equal(imported.CreatedAt))
+				assert.Equal(t, original.IsDeleted(), imported.IsDeleted())
+				assert.Equal(t, original.OwnerID, imported.OwnerID)
+				if original.ExpiresAt != nil {
+					assert.True(t, original.ExpiresAt.Equal(*imported.ExpiresAt))
+				}
+			}
+		})
+	}
+
+	t.Run("Given an existing alias, when it is imported with each conflict policy, then it should be skipped, overwritten or stop the import", func(t *testing.T) {
+		service := newMemoryService()
+		_, err := service.Create("abc123", "http://www.bemobi.com.br")
+		assert.NoError(t, err)
+		records := `{"alias":"abc123","url":"http://www.example.com","access_times":7}` + "\n" +
+			`{"alias":"new","url":"http://www.example.com"}` + "\n"
+
+		summary, err := service.ImportLinks(strings.NewReader(records), TransferFormatNDJSON, ConflictSkip)
+		assert.NoError(t, err)
+		assert.Equal(t, &ImportSummary{Created: 1, Skipped: 1}, summary)
+		shortUrl, _ := service.Repository.FindByAlias(0, "abc123")
+		assert.Equal(t, "http://www.bemobi.com.br", shortUrl.Url)
+
+		summary, err = service.ImportLinks(strings.NewReader(records), TransferFormatNDJSON, ConflictOverwrite)
+		assert.NoError(t, err)
+		assert.Equal(t, &ImportSummary{Overwritten: 2}, summary)
+		shortUrl, _ = service.Repository.FindByAlias(0, "abc123")
+		assert.Equal(t, "http://www.example.com", shortUrl.Url)
+		assert.Equal(t, int32(7), shortUrl.AccessTimes)
+
+		_, err = service.ImportLinks(strings.NewReader(records), TransferFormatNDJSON, ConflictFail)
+		var importErr *ImportError
+		assert.ErrorAs(t, err, &importErr)
+		assert.ErrorIs(t, err, ErrImportConflict)
+		assert.Equal(t, 1, importErr.Record)
+		assert.Equal(t, "abc123", importErr.Alias)
+	})
+
+	t.Run("Given invalid records, when they are imported, then it should stop with the record that failed", func(t *testing.T) {
+		service := newMemoryService()
+
+		_, err := service.ImportLinks(strings.NewReader("alias,url\nokay,http://www.bemobi.com.br\nbad,javascript:alert(1)\n"), TransferFormatCSV, ConflictFail)
+		var importErr *ImportError
+		assert.ErrorAs(t, err, &importErr)
+		assert.ErrorIs(t, err, ErrURLSchemeNotAllowed)
+		assert.Equal(t, 2, importErr.Record)
+
+		_, err = service.ImportLinks(strings.NewReader(`{"alias":"no spaces","url":"http://www.bemobi.com.br","custom_alias":true}`), TransferFormatNDJSON, ConflictFail)
+		assert.ErrorIs(t, err, ErrInvalidAlias)
+		_, err = service.ImportLinks(strings.NewReader("alias\nabc\n"), TransferFormatCSV, ConflictFail)
+		assert.ErrorIs(t, err, ErrInvalidImportRecord)
+		_, err = service.ImportLinks(strings.NewReader(`{"alias":"abc","url":"http://www.bemobi.com.br","extra":1}`), TransferFormatNDJSON, ConflictFail)
+		assert.ErrorIs(t, err, ErrInvalidImportRecord)
+		_, err = service.ImportLinks(strings.NewReader(""), "xml", ConflictFail)
+		assert.ErrorIs(t, err, ErrInvalidTransferFormat)
+		_, err = service.ImportLinks(strings.NewReader(""), TransferFormatCSV, "merge")
+		assert.ErrorIs(t, err, ErrInvalidConflictPolicy)
+	})
+}
@@ -1,6 +1,8 @@
 package service
 
 import (
+	"time"
+
 	"github.com/lucasfarolfi/hire.me/internal/entity"
 	"github.com/stretchr/testify/mock"
 )
@@ -14,28 +16,126 @@ func (m *MockShortenedURLRepository) Create(shortUrl *entity.ShortenedURL) error
 	return args.Error(0)
 }
 
-func (m *MockShortenedURLRepository) FindByAlias(alias string) (*entity.ShortenedURL, error) {
-	args := m.Called(alias)
+func (m *MockShortenedURLRepository) FindByAlias(tenantID int, alias string) (*entity.ShortenedURL, error) {
+	args := m.Called(tenantID, alias)
+	if args.Get(0) != nil {
+		return args.Get(0).(*entity.ShortenedURL), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockShortenedURLRepository) FindReusableByURLHash(tenantID int, urlHash string, redirectType int, ownerID string) (*entity.ShortenedURL, error) {
+	args := m.Called(tenantID, urlHash, redirectType, ownerID)
 	if args.Get(0) != nil {
 		return args.Get(0).(*entity.ShortenedURL), args.Error(1)
 	}
 	return nil, args.Error(1)
 }
 
-func (m *MockShortenedURLRepository) ExistsByAlias(alias string) bool {
-	args := m.Called(alias)
+func (m *MockShortenedURLRepository) ExistsByAlias(tenantID int, alias string) bool {
+	args := m.Called(tenantID, alias)
 	return args.Bool(0)
 }
 
-func (m *MockShortenedURLRepository) IncrementAccessTimesByID(id int) error {
+func (m *MockShortenedURLRepository) Update(shortUrl *entity.ShortenedURL) error {
+	args := m.Called(shortUrl)
+	return args.Error(0)
+}
+
+func (m *MockShortenedURLRepository) Replace(shortUrl *entity.ShortenedURL) error {
+	args := m.Called(shortUrl)
+	return args.Error(0)
+}
+
+func (m *MockShortenedURLRepository) SoftDelete(shortUrl *entity.ShortenedURL) error {
+	args := m.Called(shortUrl)
+	return args.Error(0)
+}
+
+func (m *MockShortenedURLRepository) UpdateFlag(shortUrl *entity.ShortenedURL) error {
+	args := m.Called(shortUrl)
+	return args.Error(0)
+}
+
+func (m *MockShortenedURLRepository) IncrementAccessTimesByID(id int) (bool, error) {
 	args := m.Called(id)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockShortenedURLRepository) AddAccessTimes(increments map[int]int32) error {
+	args := m.Called(increments)
 	return args.Error(0)
 }
 
-func (m *MockShortenedURLRepository) Get10MostAcessedUrls() ([]entity.ShortenedURL, error) {
-	args := m.Called()
+func (m *MockShortenedURLRepository) FindMostAcessedUrls(tenantID, limit, offset int, since, until *time.Time) ([]entity.ShortenedURL, error) {
+	args := m.Called(tenantID, limit, offset, since, until)
 	if args.Get(0) != nil {
 		return args.Get(0).([]entity.ShortenedURL), args.Error(1)
 	}
 	return nil, args.Error(1)
 }
+
+func (m *MockShortenedURLRepository) CountLinks(tenantID int) (int64, error) {
+	args := m.Called(tenantID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockShortenedURLRepository) FindLinks(query entity.LinkQuery) ([]entity.ShortenedURL, error) {
+	args := m.Called(query)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.ShortenedURL), args.Error(1)
+}
+
+func (m *MockShortenedURLRepository) DeleteExpiredBefore(before time.Time) (int64, error) {
+	args := m.Called(before)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+type MockClickEventRepository struct {
+	mock.Mock
+}
+
+func (m *MockClickEventRepository) Create(event *entity.ClickEvent) error {
+	args := m.Called(event)
+	return args.Error(0)
+}
+
+func (m *MockClickEventRepository) CountByBucket(shortenedURLID int, from, to, bucketStart time.Time, step time.Duration) (map[int]int64, error) {
+	args := m.Called(shortenedURLID, from, to, bucketStart, step)
+	if args.Get(0) != nil {
+		return args.Get(0).(map[int]int64), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockClickEventRepository) CountUniqueVisitors(shortenedURLID int, from, to time.Time) (int64, error) {
+	args := m.Called(shortenedURLID, from, to)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockClickEventRepository) CountByReferrer(shortenedURLID int, from, to time.Time, limit int) ([]entity.ClickCount, error) {
+	args := m.Called(shortenedURLID, from, to, limit)
+	if args.Get(0) != nil {
+		return args.Get(0).([]entity.ClickCount), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockClickEventRepository) CountByUserAgent(shortenedURLID int, from, to time.Time) ([]entity.ClickCount, error) {
+	args := m.Called(shortenedURLID, from, to)
+	if args.Get(0) != nil {
+		return args.Get(0).([]entity.ClickCount), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+type MockIDBlockRepository struct {
+	mock.Mock
+}
+
+func (m *MockIDBlockRepository) LeaseBlock(sequence string, size int64) (int64, error) {
+	args := m.Called(sequence, size)
+	return args.Get(0).(int64), args.Error(1)
+}
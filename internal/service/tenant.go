@@ -0,0 +1,136 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/lucasfarolfi/hire.me/internal/entity"
+	"gorm.io/gorm"
+)
+
+var ErrInvalidTenantSlug = fmt.Errorf("tenant slug must have between 1 and 63 lowercase letters, digits or hyphens")
+var ErrTenantAlreadyExists = fmt.Errorf("tenant already exists")
+var ErrTenantNotFound = fmt.Errorf("tenant not found")
+var ErrInvalidTenantQuota = fmt.Errorf("tenant link quota must not be negative")
+var ErrTenantQuotaExceeded = fmt.Errorf("tenant reached its shortened url quota")
+
+var tenantSlugPattern = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?$`)
+
+type TenantRepository interface {
+	Create(tenant *entity.Tenant) error
+	FindByID(id int) (*entity.Tenant, error)
+	FindBySlug(slug string) (*entity.Tenant, error)
+	FindAll() ([]entity.Tenant, error)
+	UpdateMaxLinks(id int, maxLinks *int) error
+}
+
+// TenantService manages the tenants, each with its own alias namespace and link quota.
+type TenantService struct {
+	repository TenantRepository
+}
+
+func NewTenantService(repository TenantRepository) *TenantService {
+	return &TenantService{repository: repository}
+}
+
+// Create stores a tenant with the given slug, which names it in URLs, and an optional link quota.
+func (s *TenantService) Create(slug, name string, maxLinks *int) (*entity.Tenant, error) {
+	slug = strings.TrimSpace(slug)
+	if !tenantSlugPattern.MatchString(slug) {
+		return nil, ErrInvalidTenantSlug
+	}
+	if maxLinks != nil && *maxLinks < 0 {
+		return nil, ErrInvalidTenantQuota
+	}
+	tenant := &entity.Tenant{Slug: slug, Name: name, MaxLinks: maxLinks, CreatedAt: time.Now().UTC()}
+	err := s.repository.Create(tenant)
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return nil, ErrTenantAlreadyExists
+	}
+	if err != nil {
+		return nil, err
+	}
+	return tenant, nil
+}
+
+// FindByID returns the tenant with the given ID, or nil for entity.DefaultTenantID.
+func (s *TenantService) FindByID(id int) (*entity.Tenant, error) {
+	if id == entity.DefaultTenantID {
+		return nil, nil
+	}
+	return s.find(s.repository.FindByID(id))
+}
+
+// FindBySlug returns the tenant with the given slug, or ErrTenantNotFound.
+func (s *TenantService) FindBySlug(slug string) (*entity.Tenant, error) {
+	return s.find(s.repository.FindBySlug(slug))
+}
+
+func (s *TenantService) find(tenant *entity.Tenant, err error) (*entity.Tenant, error) {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrTenantNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return tenant, nil
+}
+
+// List returns every tenant in creation order.
+func (s *TenantService) List() ([]entity.Tenant, error) {
+	return s.repository.FindAll()
+}
+
+// SetQuota changes how many shortened URLs the tenant may have; nil removes the limit. Tenants already
+// above the new quota keep their shortened URLs but cannot create new ones.
+func (s *TenantService) SetQuota(id int, maxLinks *int) error {
+	if maxLinks != nil && *maxLinks < 0 {
+		return ErrInvalidTenantQuota
+	}
+	err := s.repository.UpdateMaxLinks(id, maxLinks)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrTenantNotFound
+	}
+	return err
+}
+
+// ForTenant returns a copy of the service whose aliases, rankings and listings are scoped to the
+// tenant, enforcing its link quota. A nil tenant is the default one.
+func (s *URLShortenerService) ForTenant(tenant *entity.Tenant) *URLShortenerService {
+	scoped := *s
+	scoped.tenant = tenant
+	return &scoped
+}
+
+// Tenant returns the tenant the service is scoped to, or nil for the default one.
+func (s *URLShortenerService) Tenant() *entity.Tenant {
+	return s.tenant
+}
+
+// TenantID returns the ID of the tenant the service is scoped to.
+func (s *URLShortenerService) TenantID() int {
+	if s.tenant == nil {
+		return entity.DefaultTenantID
+	}
+	return s.tenant.ID
+}
+
+// checkQuota returns ErrTenantQuotaExceeded when the tenant cannot create another shortened URL. Deleted
+// shortened URLs do not count. The check is not atomic with the creation, so concurrent requests may
+// overshoot the quota by a few shortened URLs.
+func (s *URLShortenerService) checkQuota() error {
+	if s.tenant == nil || s.tenant.MaxLinks == nil {
+		return nil
+	}
+	count, err := s.Repository.CountLinks(s.tenant.ID)
+	if err != nil {
+		return err
+	}
+	if count >= int64(*s.tenant.MaxLinks) {
+		return ErrTenantQuotaExceeded
+	}
+	return nil
+}
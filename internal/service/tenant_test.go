@@ -0,0 +1,106 @@
+package service
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/lucasfarolfi/hire.me/infrastructure/repository/memory"
+	"github.com/lucasfarolfi/hire.me/internal/entity"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestTenantServiceMemory(t *testing.T) {
+	t.Run("Given invalid slugs or quotas, when a tenant is created, then it should reject them", func(t *testing.T) {
+		service := NewTenantService(memory.NewTenantRepository())
+
+		for _, slug := range []string{"", "Marketing", "-marketing", "marketing-", "mark eting", strings.Repeat("a", 64)} {
+			_, err := service.Create(slug, "", nil)
+			assert.ErrorIs(t, err, ErrInvalidTenantSlug, slug)
+		}
+		negative := -1
+		_, err := service.Create("marketing", "", &negative)
+		assert.ErrorIs(t, err, ErrInvalidTenantQuota)
+	})
+
+	t.Run("Given a created tenant, when it is created again, found and its quota is changed, then it should keep one tenant with the new quota", func(t *testing.T) {
+		service := NewTenantService(memory.NewTenantRepository())
+		tenant, err := service.Create("marketing", "Marketing", nil)
+		assert.NoError(t, err)
+
+		_, err = service.Create("marketing", "", nil)
+		assert.ErrorIs(t, err, ErrTenantAlreadyExists)
+		maxLinks := 3
+		assert.NoError(t, service.SetQuota(tenant.ID, &maxLinks))
+		assert.ErrorIs(t, service.SetQuota(tenant.ID+1, nil), ErrTenantNotFound)
+
+		found, err := service.FindBySlug("marketing")
+		assert.NoError(t, err)
+		assert.Equal(t, 3, *found.MaxLinks)
+		_, err = service.FindBySlug("sales")
+		assert.ErrorIs(t, err, ErrTenantNotFound)
+		defaultTenant, err := service.FindByID(entity.DefaultTenantID)
+		assert.NoError(t, err)
+		assert.Nil(t, defaultTenant)
+	})
+}
+
+func TestShortenerServiceMemory_Tenants(t *testing.T) {
+	newTenant := func(id int, maxLinks *int) *entity.Tenant {
+		return &entity.Tenant{ID: id, Slug: "tenant", MaxLinks: maxLinks}
+	}
+
+	t.Run("Given the same custom alias in two tenants, when they are resolved, then each tenant should get its own shortened url", func(t *testing.T) {
+		service := NewURLShortenerService(memory.NewShortenedURLRepository(memory.NewClickEventRepository()))
+		marketing := service.ForTenant(newTenant(1, nil))
+		_, err := service.Create("promo", "http://www.bemobi.com.br")
+		assert.NoError(t, err)
+
+		created, err := marketing.Create("promo", "http://www.google.com")
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, created.TenantID)
+		shortUrl, err := marketing.RetrieveByAlias("promo", nil)
+		assert.NoError(t, err)
+		assert.Equal(t, "http://www.google.com", shortUrl.Url)
+		shortUrl, err = service.RetrieveByAlias("promo", nil)
+		assert.NoError(t, err)
+		assert.Equal(t, "http://www.bemobi.com.br", shortUrl.Url)
+		_, err = service.ForTenant(newTenant(2, nil)).GetByAlias("promo")
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+		ranking, err := marketing.GetMostAcessedUrls(10, 0, nil, nil)
+		assert.NoError(t, err)
+		assert.Len(t, ranking, 1)
+		page, err := marketing.ListLinks(entity.LinkQuery{}, "")
+		assert.NoError(t, err)
+		assert.Len(t, page.Links, 1)
+		var exported bytes.Buffer
+		count, err := marketing.ExportLinks(&exported, TransferFormatNDJSON)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, count)
+	})
+
+	t.Run("Given a tenant at its quota, when links are created, then only reused links should be allowed until one is deleted", func(t *testing.T) {
+		maxLinks := 2
+		service := NewURLShortenerService(memory.NewShortenedURLRepository(memory.NewClickEventRepository()),
+			WithDeduplication()).ForTenant(newTenant(1, &maxLinks))
+		_, err := service.Create("", "http://www.bemobi.com.br")
+		assert.NoError(t, err)
+		_, err = service.Create("promo", "http://www.google.com")
+		assert.NoError(t, err)
+
+		_, err = service.Create("other", "http://www.google.com")
+		assert.ErrorIs(t, err, ErrTenantQuotaExceeded)
+		_, reused, err := service.CreateOrReuse("", "http://www.bemobi.com.br")
+		assert.NoError(t, err)
+		assert.True(t, reused)
+		_, err = service.ImportLinks(strings.NewReader(`{"alias":"imported","url":"http://www.google.com"}`+"\n"), TransferFormatNDJSON, ConflictFail)
+		assert.ErrorIs(t, err, ErrTenantQuotaExceeded)
+
+		assert.NoError(t, service.Delete("promo"))
+		_, err = service.Create("other", "http://www.google.com")
+		assert.NoError(t, err)
+	})
+}
@@ -0,0 +1,148 @@
+package service
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/idna"
+)
+
+var ErrInvalidURL = fmt.Errorf("url is malformed")
+var ErrURLSchemeNotAllowed = fmt.Errorf("url scheme is not allowed")
+var ErrURLHostRequired = fmt.Errorf("url must be absolute and have a host")
+var ErrURLTooLong = fmt.Errorf("url is too long")
+var ErrInvalidURLHost = fmt.Errorf("url host is invalid")
+
+const DefaultMaxURLLength = 2048
+
+var defaultPorts = map[string]string{"http": "80", "https": "443"}
+
+// URLPolicy validates destination URLs and rewrites them in a canonical form, so equivalent URLs are
+// stored, compared and deduplicated the same way.
+type URLPolicy struct {
+	AllowedSchemes []string
+	MaxLength      int
+}
+
+// DefaultURLPolicy only accepts http and https URLs up to DefaultMaxURLLength characters.
+func DefaultURLPolicy() URLPolicy {
+	return URLPolicy{AllowedSchemes: []string{"http", "https"}, MaxLength: DefaultMaxURLLength}
+}
+
+// Normalize validates rawURL and returns its canonical form: lowercase scheme and host, internationalized
+// host names in punycode, no default port and percent-encoding with uppercase hex digits that leaves
+// unreserved characters decoded.
+func (p URLPolicy) Normalize(rawURL string) (string, error) {
+	rawURL = strings.TrimSpace(rawURL)
+	if len(rawURL) > p.MaxLength {
+		return "", ErrURLTooLong
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", ErrInvalidURL
+	}
+
+	scheme := strings.ToLower(u.Scheme)
+	if !p.allowsScheme(scheme) {
+		return "", ErrURLSchemeNotAllowed
+	}
+	if u.Opaque != "" || u.Host == "" {
+		return "", ErrURLHostRequired
+	}
+	host, err := normalizeHost(scheme, u.Hostname(), u.Port())
+	if err != nil {
+		return "", err
+	}
+
+	var normalized strings.Builder
+	normalized.WriteString(scheme + "://")
+	if u.User != nil {
+		normalized.WriteString(u.User.String() + "@")
+	}
+	normalized.WriteString(host)
+	normalized.WriteString(normalizePercentEncoding(u.EscapedPath()))
+	if u.ForceQuery || u.RawQuery != "" {
+		normalized.WriteString("?" + normalizePercentEncoding(u.RawQuery))
+	}
+	if u.Fragment != "" {
+		normalized.WriteString("#" + normalizePercentEncoding(u.EscapedFragment()))
+	}
+
+	if normalized.Len() > p.MaxLength {
+		return "", ErrURLTooLong
+	}
+	return normalized.String(), nil
+}
+
+func (p URLPolicy) allowsScheme(scheme string) bool {
+	for _, allowed := range p.AllowedSchemes {
+		if strings.EqualFold(allowed, scheme) {
+			return true
+		}
+	}
+	return false
+}
+
+func normalizeHost(scheme, hostname, port string) (string, error) {
+	if port != "" {
+		if number, err := strconv.Atoi(port); err != nil || number <= 0 || number > 65535 {
+			return "", ErrInvalidURLHost
+		}
+		if defaultPorts[scheme] == port {
+			port = ""
+		}
+	}
+
+	if ip := net.ParseIP(hostname); ip != nil {
+		hostname = ip.String()
+		if ip.To4() == nil {
+			hostname = "[" + hostname + "]"
+		}
+	} else {
+		ascii, err := idna.Lookup.ToASCII(strings.TrimSuffix(hostname, "."))
+		if err != nil || ascii == "" {
+			return "", ErrInvalidURLHost
+		}
+		hostname = ascii
+	}
+
+	if port != "" {
+		return hostname + ":" + port, nil
+	}
+	return hostname, nil
+}
+
+// normalizePercentEncoding decodes the percent-encoded unreserved characters of s and uppercases
+// the hex digits of the remaining escapes.
+func normalizePercentEncoding(s string) string {
+	if !strings.Contains(s, "%") {
+		return s
+	}
+	var result strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '%' || i+2 >= len(s) {
+			result.WriteByte(s[i])
+			continue
+		}
+		value, err := strconv.ParseUint(s[i+1:i+3], 16, 8)
+		if err != nil {
+			result.WriteByte(s[i])
+			continue
+		}
+		if isUnreserved(byte(value)) {
+			result.WriteByte(byte(value))
+		} else {
+			result.WriteString("%" + strings.ToUpper(s[i+1:i+3]))
+		}
+		i += 2
+	}
+	return result.String()
+}
+
+func isUnreserved(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' ||
+		c == '-' || c == '.' || c == '_' || c == '~'
+}
@@ -0,0 +1,244 @@
+package service
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lucasfarolfi/hire.me/internal/entity"
+)
+
+var ErrURLBlocked = fmt.Errorf("url is on the malicious url blocklist")
+var ErrLinkFlagged = fmt.Errorf("shortened url destination is on the malicious url blocklist")
+var ErrInvalidURLBlocklist = fmt.Errorf("invalid url blocklist entry")
+
+// maxFlagReasonLength is the size of the flag_reason column.
+const maxFlagReasonLength = 255
+
+// hostsFileNames are the entries of hosts files that name the machine itself rather than blocked hosts.
+var hostsFileNames = map[string]bool{
+	"localhost": true, "localhost.localdomain": true, "local": true, "broadcasthost": true,
+	"ip6-localhost": true, "ip6-loopback": true, "0.0.0.0": true,
+}
+
+// URLBlocklist holds blocked hosts, which also block their subdomains, and blocked URLs, which are matched
+// regardless of their scheme.
+type URLBlocklist struct {
+	hosts map[string]bool
+	urls  map[string]bool
+}
+
+func NewURLBlocklist() *URLBlocklist {
+	return &URLBlocklist{hosts: make(map[string]bool), urls: make(map[string]bool)}
+}
+
+// Load adds the entries read from r, in either format:
+//   - hosts files, where each line is an address followed by the blocked hosts, e.g. 0.0.0.0 evil.com
+//   - plain lists, with one host or URL per line, e.g. evil.com or https://evil.com/login
+//
+// Blank lines and # comments are skipped.
+func (b *URLBlocklist) Load(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		fields := strings.Fields(scanner.Text())
+		for i, field := range fields {
+			if strings.HasPrefix(field, "#") {
+				fields = fields[:i]
+				break
+			}
+		}
+		if len(fields) == 0 {
+			continue
+		}
+		if err := b.add(fields); err != nil {
+			return fmt.Errorf("%w: line %d", err, line)
+		}
+	}
+	return scanner.Err()
+}
+
+func (b *URLBlocklist) add(fields []string) error {
+	if len(fields) == 1 {
+		if strings.Contains(fields[0], "/") {
+			return b.addURL(fields[0])
+		}
+		return b.addHost(fields[0])
+	}
+	if net.ParseIP(fields[0]) == nil {
+		return ErrInvalidURLBlocklist
+	}
+	for _, host := range fields[1:] {
+		if hostsFileNames[strings.ToLower(host)] {
+			continue
+		}
+		if err := b.addHost(host); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b *URLBlocklist) addHost(host string) error {
+	host, err := normalizeHost("", strings.ToLower(host), "")
+	if err != nil {
+		return ErrInvalidURLBlocklist
+	}
+	// Hostname strips the brackets of IPv6 addresses from the URLs being matched.
+	b.hosts[strings.Trim(host, "[]")] = true
+	return nil
+}
+
+func (b *URLBlocklist) addURL(rawURL string) error {
+	if !strings.Contains(rawURL, "://") {
+		rawURL = "http://" + rawURL
+	}
+	key, err := blocklistURLKey(rawURL)
+	if err != nil {
+		return ErrInvalidURLBlocklist
+	}
+	b.urls[key] = true
+	return nil
+}
+
+// Len returns the number of blocked hosts and URLs.
+func (b *URLBlocklist) Len() int {
+	return len(b.hosts) + len(b.urls)
+}
+
+// Match returns the entry blocking the normalized URL, if any.
+func (b *URLBlocklist) Match(normalizedURL string) (string, bool) {
+	u, err := url.Parse(normalizedURL)
+	if err != nil {
+		return "", false
+	}
+	host := strings.ToLower(u.Hostname())
+	if b.hosts[host] {
+		return host, true
+	}
+	if net.ParseIP(host) == nil {
+		for _, parent, ok := strings.Cut(host, "."); ok; _, parent, ok = strings.Cut(parent, ".") {
+			if b.hosts[parent] {
+				return parent, true
+			}
+		}
+	}
+	if key, err := blocklistURLKey(normalizedURL); err == nil && b.urls[key] {
+		return key, true
+	}
+	return "", false
+}
+
+// blocklistURLKey is the normalized URL without its scheme, user and fragment, so blocked URLs match
+// whichever scheme they are shortened with.
+func blocklistURLKey(rawURL string) (string, error) {
+	policy := URLPolicy{AllowedSchemes: []string{"http", "https"}, MaxLength: DefaultMaxURLLength}
+	normalized, err := policy.Normalize(rawURL)
+	if err != nil {
+		return "", err
+	}
+	u, err := url.Parse(normalized)
+	if err != nil {
+		return "", err
+	}
+	key := u.Host + u.EscapedPath()
+	if u.ForceQuery || u.RawQuery != "" {
+		key += "?" + u.RawQuery
+	}
+	return key, nil
+}
+
+// URLScreener checks destination URLs against the current blocklist, which can be replaced at any time
+// to pick up new entries without restarting.
+type URLScreener struct {
+	mu        sync.RWMutex
+	blocklist *URLBlocklist
+}
+
+func NewURLScreener(blocklist *URLBlocklist) *URLScreener {
+	return &URLScreener{blocklist: blocklist}
+}
+
+// Replace swaps the blocklist used by the following screenings.
+func (s *URLScreener) Replace(blocklist *URLBlocklist) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.blocklist = blocklist
+}
+
+// Screen returns the blocklist entry blocking the normalized URL, if any.
+func (s *URLScreener) Screen(normalizedURL string) (string, bool) {
+	s.mu.RLock()
+	blocklist := s.blocklist
+	s.mu.RUnlock()
+	if blocklist == nil {
+		return "", false
+	}
+	return blocklist.Match(normalizedURL)
+}
+
+// WithURLScreener rejects destination URLs on the blocklist of the screener when links are created,
+// updated or imported, and stops resolving the links whose destinations were blocked afterwards.
+func WithURLScreener(screener *URLScreener) ServiceOption {
+	return func(s *URLShortenerService) {
+		s.urlScreener = screener
+	}
+}
+
+// normalizeURL normalizes the destination URL with the URL policy and rejects it with ErrURLBlocked when
+// it is on the blocklist.
+func (s *URLShortenerService) normalizeURL(rawURL string) (string, error) {
+	normalized, err := s.urlPolicy.Normalize(rawURL)
+	if err != nil {
+		return "", err
+	}
+	if s.urlScreener != nil {
+		if _, blocked := s.urlScreener.Screen(normalized); blocked {
+			return "", ErrURLBlocked
+		}
+	}
+	return normalized, nil
+}
+
+// screenLink checks the destination of a resolved shortened URL against the current blocklist, recording
+// the outcome on the shortened URL when it changed so admins can review the flagged links. It returns
+// ErrLinkFlagged when the destination is blocked.
+func (s *URLShortenerService) screenLink(shortUrl *entity.ShortenedURL) error {
+	if s.urlScreener == nil {
+		return nil
+	}
+	entry, blocked := s.urlScreener.Screen(shortUrl.Url)
+	if blocked == shortUrl.IsFlagged() {
+		if blocked {
+			return ErrLinkFlagged
+		}
+		return nil
+	}
+	shortUrl.FlaggedAt, shortUrl.FlagReason = nil, ""
+	if blocked {
+		flaggedAt := time.Now().UTC()
+		if len(entry) > maxFlagReasonLength {
+			entry = entry[:maxFlagReasonLength]
+		}
+		shortUrl.FlaggedAt, shortUrl.FlagReason = &flaggedAt, entry
+		log.Println("Flagged shortened URL", shortUrl.Alias, "blocked by", entry)
+	}
+	if err := s.Repository.UpdateFlag(shortUrl); err != nil {
+		return err
+	}
+	if blocked {
+		return ErrLinkFlagged
+	}
+	return nil
+}
+
+// ListFlaggedLinks works like ListLinks, keeping only the shortened URLs flagged by the URL screener.
+func (s *URLShortenerService) ListFlaggedLinks(query entity.LinkQuery, cursor string) (*LinkPage, error) {
+	query.Flagged = true
+	return s.ListLinks(query, cursor)
+}
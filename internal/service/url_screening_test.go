@@ -0,0 +1,102 @@
+package service
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/lucasfarolfi/hire.me/internal/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func newURLBlocklist(t *testing.T, content string) *URLBlocklist {
+	blocklist := NewURLBlocklist()
+	assert.NoError(t, blocklist.Load(strings.NewReader(content)))
+	return blocklist
+}
+
+func TestURLBlocklistUnit(t *testing.T) {
+	t.Run("Given a hosts file and a plain list, when they are loaded, then it should block their hosts and urls", func(t *testing.T) {
+		blocklist := newURLBlocklist(t, `# malware hosts
+127.0.0.1 localhost
+0.0.0.0 evil.example.com phishing.example.org # two hosts
+0.0.0.0 BÜCHER.example
+
+scam.example.net
+https://www.bemobi.com.br/fake-login?next=1
+www.google.com/phish
+`)
+
+		assert.Equal(t, 6, blocklist.Len())
+		for url, entry := range map[string]string{
+			"http://evil.example.com/path":                  "evil.example.com",
+			"https://login.phishing.example.org":            "phishing.example.org",
+			"http://xn--bcher-kva.example":                  "xn--bcher-kva.example",
+			"https://a.b.scam.example.net/x":                "scam.example.net",
+			"http://www.bemobi.com.br/fake-login?next=1#id": "www.bemobi.com.br/fake-login?next=1",
+			"https://www.google.com/phish":                  "www.google.com/phish",
+		} {
+			matched, ok := blocklist.Match(url)
+			assert.True(t, ok, url)
+			assert.Equal(t, entry, matched, url)
+		}
+		for _, url := range []string{"http://localhost:8080", "http://example.com", "http://notevil.example.com",
+			"http://www.bemobi.com.br/fake-login", "https://www.google.com/phish/other"} {
+			_, ok := blocklist.Match(url)
+			assert.False(t, ok, url)
+		}
+	})
+
+	t.Run("Given malformed entries, when they are loaded, then it should report the line", func(t *testing.T) {
+		for _, content := range []string{"ok.example.com\nevil.example.com other.example.com", "http://[::1", "0.0.0.0 bad_host!"} {
+			err := NewURLBlocklist().Load(strings.NewReader(content))
+			assert.ErrorIs(t, err, ErrInvalidURLBlocklist, content)
+		}
+		err := NewURLBlocklist().Load(strings.NewReader("ok.example.com\nevil.example.com other.example.com"))
+		assert.ErrorContains(t, err, "line 2")
+	})
+}
+
+func TestShortenerServiceMemory_URLScreening(t *testing.T) {
+	t.Run("Given a blocked destination, when a link is created, updated or imported with it, then it should be rejected", func(t *testing.T) {
+		service := newMemoryService(WithURLScreener(NewURLScreener(newURLBlocklist(t, "evil.example.com"))))
+		_, err := service.Create("abc123", "http://www.bemobi.com.br")
+		assert.NoError(t, err)
+
+		_, err = service.Create("", "HTTP://Login.Evil.Example.com:80/")
+		assert.ErrorIs(t, err, ErrURLBlocked)
+		_, err = service.Update("abc123", "https://evil.example.com")
+		assert.ErrorIs(t, err, ErrURLBlocked)
+		_, err = service.ImportLinks(strings.NewReader(`{"alias":"imported","url":"http://evil.example.com"}`+"\n"), TransferFormatNDJSON, ConflictFail)
+		assert.ErrorIs(t, err, ErrURLBlocked)
+	})
+
+	t.Run("Given a link whose destination is blocked later, when it is resolved, then it should be flagged until the entry is removed", func(t *testing.T) {
+		screener := NewURLScreener(nil)
+		service := newMemoryService(WithURLScreener(screener))
+		_, err := service.Create("abc123", "http://evil.example.com/login")
+		assert.NoError(t, err)
+		_, err = service.Create("clean", "http://www.bemobi.com.br")
+		assert.NoError(t, err)
+
+		screener.Replace(newURLBlocklist(t, "0.0.0.0 evil.example.com"))
+		_, err = service.RetrieveByAlias("abc123", nil)
+
+		assert.ErrorIs(t, err, ErrLinkFlagged)
+		page, err := service.ListFlaggedLinks(entity.LinkQuery{}, "")
+		assert.NoError(t, err)
+		assert.Len(t, page.Links, 1)
+		assert.Equal(t, "evil.example.com", page.Links[0].FlagReason)
+		assert.NotNil(t, page.Links[0].FlaggedAt)
+		shortUrl, err := service.GetByAlias("abc123")
+		assert.NoError(t, err)
+		assert.Equal(t, int32(0), shortUrl.AccessTimes, "Flagged links should not count accesses")
+
+		screener.Replace(NewURLBlocklist())
+		shortUrl, err = service.RetrieveByAlias("abc123", nil)
+		assert.NoError(t, err)
+		assert.False(t, shortUrl.IsFlagged())
+		page, err = service.ListFlaggedLinks(entity.LinkQuery{}, "")
+		assert.NoError(t, err)
+		assert.Empty(t, page.Links)
+	})
+}
@@ -3,42 +3,150 @@ package service
 import (
 	"crypto/rand"
 	"encoding/binary"
+	"errors"
 	"fmt"
+	"log"
 	"time"
 
 	"github.com/lucasfarolfi/hire.me/internal/entity"
+	"gorm.io/gorm"
 )
 
 var ErrAliasAlreadyExists = fmt.Errorf("alias already exists")
+var ErrInvalidRedirectType = fmt.Errorf("invalid redirect type")
+var ErrInvalidExpiration = fmt.Errorf("expiration must be in the future")
+var ErrLinkExpired = fmt.Errorf("shortened url has expired")
+var ErrInvalidMaxAccessTimes = fmt.Errorf("max access times must be positive")
+var ErrAccessLimitReached = fmt.Errorf("shortened url reached its access limit")
+var ErrLinkDeleted = fmt.Errorf("shortened url was deleted")
+var ErrInvalidRankingQuery = fmt.Errorf("invalid most accessed ranking query")
+
+const MaxRankingLimit = 100
 
 type URLShortenerService struct {
-	Repository ShortenedURLRepository
+	Repository           ShortenedURLRepository
+	ClickEventRepository ClickEventRepository
+	accessCounter        *AccessCounter
+	anonymizeClientIP    bool
+
+	aliasGenerator          AliasGenerator
+	aliasGenerationAttempts int
+	deduplicate             bool
+	urlPolicy               URLPolicy
+	aliasPolicy             AliasPolicy
+	urlScreener             *URLScreener
+
+	transactor    Transactor
+	bulkBatchSize int
+
+	tenant *entity.Tenant
 }
 
 type ShortenedURLRepository interface {
 	Create(shortUrl *entity.ShortenedURL) error
-	FindByAlias(alias string) (*entity.ShortenedURL, error)
-	ExistsByAlias(alias string) bool
-	IncrementAccessTimesByID(id int) error
-	Get10MostAcessedUrls() ([]entity.ShortenedURL, error)
+	FindByAlias(tenantID int, alias string) (*entity.ShortenedURL, error)
+	FindReusableByURLHash(tenantID int, urlHash string, redirectType int, ownerID string) (*entity.ShortenedURL, error)
+	ExistsByAlias(tenantID int, alias string) bool
+	Update(shortUrl *entity.ShortenedURL) error
+	Replace(shortUrl *entity.ShortenedURL) error
+	SoftDelete(shortUrl *entity.ShortenedURL) error
+	UpdateFlag(shortUrl *entity.ShortenedURL) error
+	IncrementAccessTimesByID(id int) (bool, error)
+	AddAccessTimes(increments map[int]int32) error
+	FindMostAcessedUrls(tenantID, limit, offset int, since, until *time.Time) ([]entity.ShortenedURL, error)
+	CountLinks(tenantID int) (int64, error)
+	FindLinks(query entity.LinkQuery) ([]entity.ShortenedURL, error)
+	DeleteExpiredBefore(before time.Time) (int64, error)
 }
 
-func NewURLShortenerService(repository ShortenedURLRepository) *URLShortenerService {
-	return &URLShortenerService{repository}
+type ClickEventRepository interface {
+	Create(event *entity.ClickEvent) error
+	// CountByBucket counts the clicks between from and to in buckets of step length starting at
+	// bucketStart, keyed by the index of the bucket.
+	CountByBucket(shortenedURLID int, from, to, bucketStart time.Time, step time.Duration) (map[int]int64, error)
+	CountUniqueVisitors(shortenedURLID int, from, to time.Time) (int64, error)
+	CountByReferrer(shortenedURLID int, from, to time.Time, limit int) ([]entity.ClickCount, error)
+	CountByUserAgent(shortenedURLID int, from, to time.Time) ([]entity.ClickCount, error)
 }
 
-func (s *URLShortenerService) GenerateRandomAlias() string {
-	for {
-		timestamp := uint64(time.Now().UnixMilli())
-		randomPart := uint64(randomUint32())
-		combined := (timestamp << 20) | randomPart
-		alias := encodeBase62(combined)
-		if !s.Repository.ExistsByAlias(alias) {
-			return alias
-		}
+// ServiceOption customizes an URLShortenerService.
+type ServiceOption func(s *URLShortenerService)
+
+// WithClickEventRepository makes the service record a click event for every resolved shortened URL.
+func WithClickEventRepository(repository ClickEventRepository) ServiceOption {
+	return func(s *URLShortenerService) {
+		s.ClickEventRepository = repository
+	}
+}
+
+// WithAccessCounter counts the accesses of shortened URLs without an access limit asynchronously
+// through the given counter instead of updating the repository on every resolution.
+func WithAccessCounter(counter *AccessCounter) ServiceOption {
+	return func(s *URLShortenerService) {
+		s.accessCounter = counter
+	}
+}
+
+// WithClientIPAnonymization masks the host part of the client IP before recording click events.
+func WithClientIPAnonymization() ServiceOption {
+	return func(s *URLShortenerService) {
+		s.anonymizeClientIP = true
+	}
+}
+
+// WithAliasGenerator replaces the default timestamp based alias generator.
+func WithAliasGenerator(generator AliasGenerator) ServiceOption {
+	return func(s *URLShortenerService) {
+		s.aliasGenerator = generator
 	}
 }
 
+// WithAliasGenerationAttempts bounds how many generated aliases are tried when creating a shortened URL.
+// A new alias is proposed each time the insert fails with gorm.ErrDuplicatedKey, until the attempts run
+// out and ErrAliasGenerationFailed is returned.
+func WithAliasGenerationAttempts(attempts int) ServiceOption {
+	return func(s *URLShortenerService) {
+		s.aliasGenerationAttempts = attempts
+	}
+}
+
+// WithURLPolicy replaces the default policy used to validate and normalize destination URLs.
+func WithURLPolicy(policy URLPolicy) ServiceOption {
+	return func(s *URLShortenerService) {
+		s.urlPolicy = policy
+	}
+}
+
+// WithAliasPolicy replaces the default rules for custom aliases. Generated aliases are only checked
+// against its blocklist.
+func WithAliasPolicy(policy AliasPolicy) ServiceOption {
+	return func(s *URLShortenerService) {
+		s.aliasPolicy = policy
+	}
+}
+
+// WithDeduplication makes requests without a custom alias reuse the existing shortened URL of the same destination.
+func WithDeduplication() ServiceOption {
+	return func(s *URLShortenerService) {
+		s.deduplicate = true
+	}
+}
+
+func NewURLShortenerService(repository ShortenedURLRepository, opts ...ServiceOption) *URLShortenerService {
+	s := &URLShortenerService{
+		Repository:              repository,
+		aliasGenerator:          TimestampAliasGenerator{},
+		aliasGenerationAttempts: DefaultAliasGenerationAttempts,
+		urlPolicy:               DefaultURLPolicy(),
+		aliasPolicy:             DefaultAliasPolicy(),
+		bulkBatchSize:           DefaultBulkBatchSize,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
 func randomUint32() uint32 {
 	var b [4]byte
 	if _, err := rand.Read(b[:]); err != nil {
@@ -55,40 +163,288 @@ func encodeBase62(num uint64) string {
 	result := ""
 	for num > 0 {
 		remainder := num % 62
-		result += string("0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"[remainder])
+		result += string(base62Alphabet[remainder])
 		num /= 62
 	}
 	return result
 }
 
-func (s *URLShortenerService) Create(alias, url string) (*entity.ShortenedURL, error) {
+// CreateOption customizes a shortened URL before it is stored.
+type CreateOption func(shortUrl *entity.ShortenedURL)
+
+// WithRedirectType overrides the redirect status used when the shortened URL is resolved.
+func WithRedirectType(code int) CreateOption {
+	return func(shortUrl *entity.ShortenedURL) {
+		shortUrl.RedirectType = code
+	}
+}
+
+// WithExpiresAt makes the shortened URL stop resolving once expiresAt is reached.
+func WithExpiresAt(expiresAt time.Time) CreateOption {
+	return func(shortUrl *entity.ShortenedURL) {
+		utc := expiresAt.UTC()
+		shortUrl.ExpiresAt = &utc
+	}
+}
+
+// WithoutExpiration removes the expiration date of the shortened URL.
+func WithoutExpiration() CreateOption {
+	return func(shortUrl *entity.ShortenedURL) {
+		shortUrl.ExpiresAt = nil
+	}
+}
+
+// WithMaxAccessTimes makes the shortened URL stop resolving after it is accessed maxAccessTimes times.
+func WithMaxAccessTimes(maxAccessTimes int32) CreateOption {
+	return func(shortUrl *entity.ShortenedURL) {
+		shortUrl.MaxAccessTimes = &maxAccessTimes
+	}
+}
+
+// WithOwner records the owner of the shortened URL. Deduplication only reuses shortened URLs of the same
+// owner.
+func WithOwner(ownerID string) CreateOption {
+	return func(shortUrl *entity.ShortenedURL) {
+		shortUrl.OwnerID = ownerID
+	}
+}
+
+// Create stores a shortened URL of the tenant of the service under the given custom alias or, when alias is
+// empty, under a generated one.
+// Generated aliases are inserted right away and regenerated when the unique constraint rejects them,
+// so concurrent replicas cannot end up with the same alias.
+func (s *URLShortenerService) Create(alias, url string, opts ...CreateOption) (*entity.ShortenedURL, error) {
+	shortenedUrl, _, err := s.CreateOrReuse(alias, url, opts...)
+	return shortenedUrl, err
+}
+
+// CreateOrReuse works like Create but, with deduplication enabled, returns the existing shortened URL of
+// the same destination instead of creating one when no custom alias, expiration or access limit is asked
+// for. It reports whether the shortened URL was reused.
+func (s *URLShortenerService) CreateOrReuse(alias, url string, opts ...CreateOption) (*entity.ShortenedURL, bool, error) {
+	url, err := s.normalizeURL(url)
+	if err != nil {
+		return nil, false, err
+	}
+	if alias != "" {
+		if alias, err = s.aliasPolicy.Apply(alias); err != nil {
+			return nil, false, err
+		}
+	}
 	shortenedUrl := entity.NewShortenedURL(alias, url)
-	err := s.Repository.Create(shortenedUrl)
+	shortenedUrl.CustomAlias = alias != ""
+	shortenedUrl.TenantID = s.TenantID()
+	for _, opt := range opts {
+		opt(shortenedUrl)
+	}
+	if shortenedUrl.RedirectType != 0 && !entity.IsValidRedirectType(shortenedUrl.RedirectType) {
+		return nil, false, ErrInvalidRedirectType
+	}
+	if shortenedUrl.IsExpired(time.Now()) {
+		return nil, false, ErrInvalidExpiration
+	}
+	if shortenedUrl.MaxAccessTimes != nil && *shortenedUrl.MaxAccessTimes <= 0 {
+		return nil, false, ErrInvalidMaxAccessTimes
+	}
+
+	if alias == "" {
+		if s.deduplicate && shortenedUrl.ExpiresAt == nil && shortenedUrl.MaxAccessTimes == nil {
+			existing, err := s.Repository.FindReusableByURLHash(s.TenantID(), shortenedUrl.URLHash, shortenedUrl.RedirectType, shortenedUrl.OwnerID)
+			if err == nil {
+				return existing, true, nil
+			}
+			if !errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, false, err
+			}
+		}
+		if err := s.checkQuota(); err != nil {
+			return nil, false, err
+		}
+		if err := s.createWithGeneratedAlias(shortenedUrl); err != nil {
+			return nil, false, err
+		}
+		return shortenedUrl, false, nil
+	}
+
+	if s.Repository.ExistsByAlias(s.TenantID(), alias) {
+		return nil, false, ErrAliasAlreadyExists
+	}
+	if err := s.checkQuota(); err != nil {
+		return nil, false, err
+	}
+	err = s.Repository.Create(shortenedUrl)
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return nil, false, ErrAliasAlreadyExists
+	}
 	if err != nil {
+		return nil, false, err
+	}
+	return shortenedUrl, false, nil
+}
+
+func (s *URLShortenerService) createWithGeneratedAlias(shortenedUrl *entity.ShortenedURL) error {
+	for attempt := 0; attempt < s.aliasGenerationAttempts; attempt++ {
+		alias, err := s.aliasGenerator.Generate(shortenedUrl.Url, attempt)
+		if err != nil {
+			return err
+		}
+		if s.aliasPolicy.IsBlocked(alias) {
+			continue
+		}
+		shortenedUrl.Alias = alias
+		err = s.Repository.Create(shortenedUrl)
+		if !errors.Is(err, gorm.ErrDuplicatedKey) {
+			return err
+		}
+	}
+	return ErrAliasGenerationFailed
+}
+
+// GetByAlias returns the shortened URL of the alias without counting an access, including expired
+// or exhausted ones.
+func (s *URLShortenerService) GetByAlias(alias string) (*entity.ShortenedURL, error) {
+	return s.findActiveByAlias(alias)
+}
+
+// Update changes the destination (when url is not empty), the expiration and the redirect type of the
+// shortened URL through the given options. Deleted shortened URLs cannot be updated.
+func (s *URLShortenerService) Update(alias, url string, opts ...CreateOption) (*entity.ShortenedURL, error) {
+	shortUrl, err := s.findActiveByAlias(alias)
+	if err != nil {
+		return nil, err
+	}
+	if url != "" {
+		if url, err = s.normalizeURL(url); err != nil {
+			return nil, err
+		}
+		shortUrl.Url = url
+		shortUrl.URLHash = entity.HashURL(url)
+		shortUrl.URLHost = entity.URLHost(url)
+	}
+	previousExpiresAt := shortUrl.ExpiresAt
+	for _, opt := range opts {
+		opt(shortUrl)
+	}
+	if shortUrl.RedirectType != 0 && !entity.IsValidRedirectType(shortUrl.RedirectType) {
+		return nil, ErrInvalidRedirectType
+	}
+	if shortUrl.ExpiresAt != previousExpiresAt && shortUrl.IsExpired(time.Now()) {
+		return nil, ErrInvalidExpiration
+	}
+
+	if err := s.Repository.Update(shortUrl); err != nil {
 		return nil, err
 	}
-	return shortenedUrl, nil
+	return shortUrl, nil
+}
+
+// Delete soft deletes the shortened URL. It stops resolving, but its alias stays reserved so it
+// cannot be registered again.
+func (s *URLShortenerService) Delete(alias string) error {
+	shortUrl, err := s.findActiveByAlias(alias)
+	if err != nil {
+		return err
+	}
+	deletedAt := time.Now().UTC()
+	shortUrl.DeletedAt = &deletedAt
+	return s.Repository.SoftDelete(shortUrl)
+}
+
+// Authorize returns ErrLinkNotOwned unless the API key may manage the shortened URL of the alias. Deleted
+// shortened URLs are authorized like the others, so their owner learns they are gone.
+func (s *URLShortenerService) Authorize(key *entity.APIKey, alias string) error {
+	shortUrl, err := s.Repository.FindByAlias(s.TenantID(), alias)
+	if err != nil {
+		return err
+	}
+	if !key.CanManage(shortUrl) {
+		return ErrLinkNotOwned
+	}
+	return nil
 }
 
-func (s *URLShortenerService) RetrieveByAlias(alias string) (*entity.ShortenedURL, error) {
-	shortUrl, err := s.Repository.FindByAlias(alias)
+func (s *URLShortenerService) findActiveByAlias(alias string) (*entity.ShortenedURL, error) {
+	shortUrl, err := s.Repository.FindByAlias(s.TenantID(), alias)
 	if err != nil {
 		return nil, err
 	}
-	err = s.Repository.IncrementAccessTimesByID(shortUrl.ID)
+	if shortUrl.IsDeleted() {
+		return nil, ErrLinkDeleted
+	}
+	return shortUrl, nil
+}
+
+// RetrieveByAlias resolves the shortened URL, counting the access and recording the click event when
+// one is given and a click event repository is configured. Shortened URLs whose destination is on the
+// blocklist of the URL screener are flagged and not resolved.
+func (s *URLShortenerService) RetrieveByAlias(alias string, click *entity.ClickEvent) (*entity.ShortenedURL, error) {
+	shortUrl, err := s.findActiveByAlias(alias)
 	if err != nil {
 		return nil, err
 	}
-	return s.Repository.FindByAlias(alias)
+	if shortUrl.IsExpired(time.Now()) {
+		return nil, ErrLinkExpired
+	}
+	if err := s.screenLink(shortUrl); err != nil {
+		return nil, err
+	}
+	if err := s.countAccess(shortUrl); err != nil {
+		return nil, err
+	}
+	shortUrl.AccessTimes++
+	s.recordClick(shortUrl, click)
+	return shortUrl, nil
+}
+
+// countAccess buffers the access in the access counter when possible. Shortened URLs with an access
+// limit are always counted synchronously, since the limit must be checked atomically.
+func (s *URLShortenerService) countAccess(shortUrl *entity.ShortenedURL) error {
+	if s.accessCounter != nil && shortUrl.MaxAccessTimes == nil {
+		s.accessCounter.Increment(shortUrl.ID)
+		return nil
+	}
+	incremented, err := s.Repository.IncrementAccessTimesByID(shortUrl.ID)
+	if err != nil {
+		return err
+	}
+	if !incremented {
+		return ErrAccessLimitReached
+	}
+	return nil
+}
+
+func (s *URLShortenerService) recordClick(shortUrl *entity.ShortenedURL, click *entity.ClickEvent) {
+	if click == nil || s.ClickEventRepository == nil {
+		return
+	}
+	click.ShortenedURLID = shortUrl.ID
+	click.Alias = shortUrl.Alias
+	if click.ClickedAt.IsZero() {
+		click.ClickedAt = time.Now().UTC()
+	}
+	if s.anonymizeClientIP {
+		click.ClientIP = anonymizeIP(click.ClientIP)
+	}
+	if err := s.ClickEventRepository.Create(click); err != nil {
+		log.Println("Failed to record click event for alias", shortUrl.Alias, ":", err)
+	}
 }
 
 func (s *URLShortenerService) ExistsByAlias(alias string) bool {
-	if s.Repository.ExistsByAlias(alias) {
+	if s.Repository.ExistsByAlias(s.TenantID(), alias) {
 		return true
 	}
 	return false
 }
 
-func (s *URLShortenerService) Get10MostAcessedUrls() ([]entity.ShortenedURL, error) {
-	return s.Repository.Get10MostAcessedUrls()
+// GetMostAcessedUrls returns a page of the most accessed shortened URLs, optionally ranking only the
+// accesses between since (inclusive) and until (exclusive).
+func (s *URLShortenerService) GetMostAcessedUrls(limit, offset int, since, until *time.Time) ([]entity.ShortenedURL, error) {
+	if limit <= 0 || limit > MaxRankingLimit || offset < 0 {
+		return nil, ErrInvalidRankingQuery
+	}
+	if since != nil && until != nil && !since.Before(*until) {
+		return nil, ErrInvalidRankingQuery
+	}
+	return s.Repository.FindMostAcessedUrls(s.TenantID(), limit, offset, since, until)
 }
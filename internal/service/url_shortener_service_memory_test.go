@@ -0,0 +1,270 @@
+package service
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/lucasfarolfi/hire.me/infrastructure/repository/memory"
+	"github.com/lucasfarolfi/hire.me/internal/entity"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func newMemoryService(opts ...ServiceOption) *URLShortenerService {
+	clickEvents := memory.NewClickEventRepository()
+	opts = append([]ServiceOption{WithClickEventRepository(clickEvents)}, opts...)
+	return NewURLShortenerService(memory.NewShortenedURLRepository(clickEvents), opts...)
+}
+
+func TestShortenerServiceMemory_CreateAndRetrieve(t *testing.T) {
+	t.Run("Given a created shortened URL, when it is retrieved several times, then it should count every access and record every click", func(t *testing.T) {
+		service := newMemoryService()
+		_, err := service.Create("abc123", "http://www.bemobi.com.br")
+		assert.NoError(t, err)
+
+		for i := 0; i < 3; i++ {
+			shortUrl, err := service.RetrieveByAlias("abc123", entity.NewClickEvent("", "curl/8.0", "10.0.0.1", ""))
+			assert.NoError(t, err)
+			assert.Equal(t, int32(i+1), shortUrl.AccessTimes)
+		}
+
+		now := time.Now().UTC()
+		stats, err := service.GetStatsByAlias("abc123", now.Add(-time.Hour), now.Add(time.Hour), StatsIntervalHour)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), stats.TotalClicks)
+		assert.Equal(t, int64(3), stats.RangeClicks)
+		assert.Equal(t, int64(1), stats.UniqueVisitors)
+	})
+
+	t.Run("Given an unknown alias, when RetrieveByAlias is called, then it should return a record not found error", func(t *testing.T) {
+		service := newMemoryService()
+
+		shortUrl, err := service.RetrieveByAlias("unknown", nil)
+
+		assert.Nil(t, shortUrl)
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	})
+}
+
+func TestShortenerServiceMemory_UpdateAndDelete(t *testing.T) {
+	t.Run("Given a shortened URL, when its destination is updated, then it should redirect to the normalized new destination", func(t *testing.T) {
+		service := newMemoryService()
+		_, err := service.Create("abc123", "http://www.bemobi.com.br")
+		assert.NoError(t, err)
+
+		updated, err := service.Update("abc123", "HTTP://WWW.Bemobi.com.br:80/fixed", WithRedirectType(301), WithExpiresAt(time.Now().Add(time.Hour)))
+		assert.NoError(t, err)
+		assert.Equal(t, "http://www.bemobi.com.br/fixed", updated.Url)
+
+		shortUrl, err := service.RetrieveByAlias("abc123", nil)
+		assert.NoError(t, err)
+		assert.Equal(t, "http://www.bemobi.com.br/fixed", shortUrl.Url)
+		assert.Equal(t, 301, shortUrl.RedirectType)
+		assert.NotNil(t, shortUrl.ExpiresAt)
+
+		updated, err = service.Update("abc123", "", WithoutExpiration())
+		assert.NoError(t, err)
+		assert.Nil(t, updated.ExpiresAt)
+		assert.Equal(t, "http://www.bemobi.com.br/fixed", updated.Url)
+	})
+
+	t.Run("Given invalid changes, when Update is called, then it should reject them", func(t *testing.T) {
+		service := newMemoryService()
+		_, err := service.Create("abc123", "http://www.bemobi.com.br")
+		assert.NoError(t, err)
+
+		_, err = service.Update("abc123", "javascript:alert(1)")
+		assert.ErrorIs(t, err, ErrURLSchemeNotAllowed)
+		_, err = service.Update("abc123", "", WithExpiresAt(time.Now().Add(-time.Hour)))
+		assert.ErrorIs(t, err, ErrInvalidExpiration)
+		_, err = service.Update("abc123", "", WithRedirectType(200))
+		assert.ErrorIs(t, err, ErrInvalidRedirectType)
+		_, err = service.Update("unknown", "http://www.bemobi.com.br")
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	})
+
+	t.Run("Given a deleted shortened URL, when it is used again, then it should be gone but its alias should stay reserved", func(t *testing.T) {
+		service := newMemoryService(WithDeduplication())
+		_, err := service.Create("abc123", "http://www.bemobi.com.br")
+		assert.NoError(t, err)
+
+		assert.NoError(t, service.Delete("abc123"))
+
+		_, err = service.RetrieveByAlias("abc123", nil)
+		assert.ErrorIs(t, err, ErrLinkDeleted)
+		_, err = service.GetByAlias("abc123")
+		assert.ErrorIs(t, err, ErrLinkDeleted)
+		_, err = service.Update("abc123", "http://www.example.com")
+		assert.ErrorIs(t, err, ErrLinkDeleted)
+		assert.ErrorIs(t, service.Delete("abc123"), ErrLinkDeleted)
+		_, err = service.Create("abc123", "http://www.example.com")
+		assert.ErrorIs(t, err, ErrAliasAlreadyExists)
+	})
+}
+
+func TestShortenerServiceMemory_AccessLimit(t *testing.T) {
+	t.Run("Given a burn-after-N link retrieved concurrently, when the budget is exhausted, then exactly N retrievals should succeed", func(t *testing.T) {
+		service := newMemoryService()
+		_, err := service.Create("once", "http://www.bemobi.com.br", WithMaxAccessTimes(5))
+		assert.NoError(t, err)
+
+		var wg sync.WaitGroup
+		var succeeded, limited atomic.Int32
+		for i := 0; i < 40; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, err := service.RetrieveByAlias("once", nil)
+				switch {
+				case err == nil:
+					succeeded.Add(1)
+				case assert.ErrorIs(t, err, ErrAccessLimitReached):
+					limited.Add(1)
+				}
+			}()
+		}
+		wg.Wait()
+
+		assert.Equal(t, int32(5), succeeded.Load())
+		assert.Equal(t, int32(35), limited.Load())
+	})
+}
+
+func TestShortenerServiceMemory_AccessCounter(t *testing.T) {
+	t.Run("Given async access counting, when the counter is closed, then the ranking should reflect every access", func(t *testing.T) {
+		clickEvents := memory.NewClickEventRepository()
+		repo := memory.NewShortenedURLRepository(clickEvents)
+		counter := NewAccessCounter(repo, time.Hour, 1000)
+		service := NewURLShortenerService(repo, WithAccessCounter(counter))
+		_, err := service.Create("hot", "http://www.bemobi.com.br")
+		assert.NoError(t, err)
+		_, err = service.Create("cold", "http://www.google.com")
+		assert.NoError(t, err)
+
+		for i := 0; i < 20; i++ {
+			_, err := service.RetrieveByAlias("hot", nil)
+			assert.NoError(t, err)
+		}
+		_, err = service.RetrieveByAlias("cold", nil)
+		assert.NoError(t, err)
+		assert.NoError(t, counter.Close())
+
+		ranking, err := service.GetMostAcessedUrls(10, 0, nil, nil)
+		assert.NoError(t, err)
+		assert.Len(t, ranking, 2)
+		assert.Equal(t, "hot", ranking[0].Alias)
+		assert.Equal(t, int32(20), ranking[0].AccessTimes)
+		assert.Equal(t, "cold", ranking[1].Alias)
+		assert.Equal(t, int32(1), ranking[1].AccessTimes)
+	})
+}
+
+func TestShortenerServiceMemory_Deduplication(t *testing.T) {
+	t.Run("Given deduplication, when the same URL is shortened twice without alias, then the second call should reuse the first shortened URL", func(t *testing.T) {
+		service := newMemoryService(WithDeduplication())
+
+		first, reused, err := service.CreateOrReuse("", "http://www.bemobi.com.br")
+		assert.NoError(t, err)
+		assert.False(t, reused)
+		second, reused, err := service.CreateOrReuse("", "http://www.bemobi.com.br")
+
+		assert.NoError(t, err)
+		assert.True(t, reused)
+		assert.Equal(t, first.Alias, second.Alias)
+	})
+
+	t.Run("Given deduplication, when a custom alias or options are given, then a new shortened URL should always be created", func(t *testing.T) {
+		service := newMemoryService(WithDeduplication())
+		first, _, err := service.CreateOrReuse("", "http://www.bemobi.com.br")
+		assert.NoError(t, err)
+
+		custom, reused, err := service.CreateOrReuse("mine", "http://www.bemobi.com.br")
+		assert.NoError(t, err)
+		assert.False(t, reused)
+		assert.Equal(t, "mine", custom.Alias)
+
+		limited, reused, err := service.CreateOrReuse("", "http://www.bemobi.com.br", WithMaxAccessTimes(1))
+		assert.NoError(t, err)
+		assert.False(t, reused)
+		assert.NotEqual(t, first.Alias, limited.Alias)
+
+		again, reused, err := service.CreateOrReuse("", "http://www.bemobi.com.br")
+		assert.NoError(t, err)
+		assert.True(t, reused)
+		assert.Equal(t, first.Alias, again.Alias, "Custom aliases and limited links should never be reused")
+	})
+
+	t.Run("Given deduplication disabled, when the same URL is shortened twice, then two shortened URLs should be created", func(t *testing.T) {
+		service := newMemoryService()
+
+		first, _, err := service.CreateOrReuse("", "http://www.bemobi.com.br")
+		assert.NoError(t, err)
+		second, reused, err := service.CreateOrReuse("", "http://www.bemobi.com.br")
+
+		assert.NoError(t, err)
+		assert.False(t, reused)
+		assert.NotEqual(t, first.Alias, second.Alias)
+	})
+}
+
+func TestShortenerServiceMemory_ListLinks(t *testing.T) {
+	t.Run("Given more links than a page, when every page is listed, then it should return each link once in order", func(t *testing.T) {
+		service := newMemoryService()
+		for i := 0; i < 25; i++ {
+			shortUrl, err := service.Create("", "http://www.bemobi.com.br")
+			assert.NoError(t, err)
+			for j := 0; j < i%4; j++ {
+				_, err = service.RetrieveByAlias(shortUrl.Alias, nil)
+				assert.NoError(t, err)
+			}
+		}
+
+		seen := map[string]bool{}
+		var previous *entity.ShortenedURL
+		cursor := ""
+		pages := 0
+		for {
+			page, err := service.ListLinks(entity.LinkQuery{SortBy: entity.LinkSortAccessTimes, Descending: true, Limit: 10}, cursor)
+			assert.NoError(t, err)
+			pages++
+			for i := range page.Links {
+				link := &page.Links[i]
+				assert.False(t, seen[link.Alias], "link %s listed twice", link.Alias)
+				seen[link.Alias] = true
+				if previous != nil {
+					assert.True(t, previous.AccessTimes > link.AccessTimes ||
+						(previous.AccessTimes == link.AccessTimes && previous.ID > link.ID))
+				}
+				previous = link
+			}
+			if page.NextCursor == "" {
+				break
+			}
+			cursor = page.NextCursor
+		}
+		assert.Len(t, seen, 25)
+		assert.Equal(t, 3, pages)
+	})
+
+	t.Run("Given invalid queries or a cursor of another sort, when ListLinks is called, then it should return an invalid link query error", func(t *testing.T) {
+		service := newMemoryService()
+		for i := 0; i < 3; i++ {
+			_, err := service.Create("", "http://www.bemobi.com.br")
+			assert.NoError(t, err)
+		}
+		page, err := service.ListLinks(entity.LinkQuery{Limit: 1}, "")
+		assert.NoError(t, err)
+		assert.NotEmpty(t, page.NextCursor)
+
+		_, err = service.ListLinks(entity.LinkQuery{Limit: 1, SortBy: entity.LinkSortAlias}, page.NextCursor)
+		assert.ErrorIs(t, err, ErrInvalidLinkQuery)
+		_, err = service.ListLinks(entity.LinkQuery{}, "not a cursor")
+		assert.ErrorIs(t, err, ErrInvalidLinkQuery)
+		_, err = service.ListLinks(entity.LinkQuery{SortBy: "url"}, "")
+		assert.ErrorIs(t, err, ErrInvalidLinkQuery)
+		_, err = service.ListLinks(entity.LinkQuery{Limit: MaxLinkPageLimit + 1}, "")
+		assert.ErrorIs(t, err, ErrInvalidLinkQuery)
+	})
+}
@@ -2,23 +2,323 @@ package service
 
 import (
 	"testing"
+	"time"
+
+	"github.com/lucasfarolfi/hire.me/internal/entity"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
 )
 
-func TestShortenerServiceUnit_GenerateRandomAlias(t *testing.T) {
-	t.Run("Should keep generating a valid random alias until a non-existing alias is found in the database", func(t *testing.T) {
+func TestShortenerServiceUnit_Create(t *testing.T) {
+	t.Run("Given an unsupported redirect type, when Create is called, then it should return an error without storing the shortened URL", func(t *testing.T) {
+		repo := &MockShortenedURLRepository{}
+		service := NewURLShortenerService(repo)
+
+		created, err := service.Create("abc123", "http://www.bemobi.com.br", WithRedirectType(200))
+
+		assert.ErrorIs(t, err, ErrInvalidRedirectType)
+		assert.Nil(t, created)
+		repo.AssertNotCalled(t, "Create", mock.Anything)
+	})
+
+	t.Run("Given an expiration in the past, when Create is called, then it should return an error without storing the shortened URL", func(t *testing.T) {
+		repo := &MockShortenedURLRepository{}
+		service := NewURLShortenerService(repo)
+
+		created, err := service.Create("abc123", "http://www.bemobi.com.br", WithExpiresAt(time.Now().Add(-time.Minute)))
+
+		assert.ErrorIs(t, err, ErrInvalidExpiration)
+		assert.Nil(t, created)
+		repo.AssertNotCalled(t, "Create", mock.Anything)
+	})
+
+	t.Run("Given an expiration in the future, when Create is called, then it should store the expiration in UTC", func(t *testing.T) {
+		repo := &MockShortenedURLRepository{}
+		repo.On("ExistsByAlias", 0, "abc123").Return(false)
+		repo.On("Create", mock.AnythingOfType("*entity.ShortenedURL")).Return(nil)
+		service := NewURLShortenerService(repo)
+
+		expiresAt := time.Now().Add(time.Hour)
+		created, err := service.Create("abc123", "http://www.bemobi.com.br", WithExpiresAt(expiresAt))
+
+		assert.NoError(t, err)
+		assert.True(t, expiresAt.Equal(*created.ExpiresAt), "The expiration should be kept")
+		assert.Equal(t, time.UTC, created.ExpiresAt.Location(), "The expiration should be stored in UTC")
+	})
+
+	t.Run("Given a non-positive max access times, when Create is called, then it should return an error without storing the shortened URL", func(t *testing.T) {
+		repo := &MockShortenedURLRepository{}
+		service := NewURLShortenerService(repo)
+
+		created, err := service.Create("abc123", "http://www.bemobi.com.br", WithMaxAccessTimes(0))
+
+		assert.ErrorIs(t, err, ErrInvalidMaxAccessTimes)
+		assert.Nil(t, created)
+		repo.AssertNotCalled(t, "Create", mock.Anything)
+	})
+
+	t.Run("Given a disallowed URL, when Create is called, then it should return a validation error without storing the shortened URL", func(t *testing.T) {
+		repo := &MockShortenedURLRepository{}
+		service := NewURLShortenerService(repo)
+
+		created, err := service.Create("abc123", "javascript:alert(1)")
+
+		assert.ErrorIs(t, err, ErrURLSchemeNotAllowed)
+		assert.Nil(t, created)
+		repo.AssertNotCalled(t, "Create", mock.Anything)
+	})
+
+	t.Run("Given a non canonical URL, when Create is called, then it should store and hash the normalized URL", func(t *testing.T) {
 		repo := &MockShortenedURLRepository{}
-		repo.On("ExistsByAlias", mock.AnythingOfType("string")).Return(true).Once()
-		repo.On("ExistsByAlias", mock.AnythingOfType("string")).Return(true).Once()
-		repo.On("ExistsByAlias", mock.AnythingOfType("string")).Return(false).Once()
+		repo.On("ExistsByAlias", 0, "abc123").Return(false)
+		repo.On("Create", mock.AnythingOfType("*entity.ShortenedURL")).Return(nil)
+		service := NewURLShortenerService(repo)
+
+		created, err := service.Create("abc123", "HTTP://WWW.Bemobi.com.br:80/%7eoffers")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "http://www.bemobi.com.br/~offers", created.Url)
+		assert.Equal(t, entity.HashURL("http://www.bemobi.com.br/~offers"), created.URLHash)
+	})
+
+	t.Run("Given a reserved or invalid custom alias, when Create is called, then it should be rejected before checking if it exists", func(t *testing.T) {
+		repo := &MockShortenedURLRepository{}
+		service := NewURLShortenerService(repo)
+
+		_, err := service.Create("most_acessed", "http://www.bemobi.com.br")
+		assert.ErrorIs(t, err, ErrReservedAlias)
+		_, err = service.Create("a/b", "http://www.bemobi.com.br")
+		assert.ErrorIs(t, err, ErrInvalidAlias)
+
+		repo.AssertNotCalled(t, "ExistsByAlias", mock.Anything)
+		repo.AssertNotCalled(t, "Create", mock.Anything)
+	})
+
+	t.Run("Given no alias, when the generated alias is blocked, then it should never be stored", func(t *testing.T) {
+		repo := &MockShortenedURLRepository{}
+		repo.On("Create", mock.AnythingOfType("*entity.ShortenedURL")).Return(nil)
+		policy := DefaultAliasPolicy()
+		policy.Blocklist = []string{"1"}
+		service := NewURLShortenerService(repo, WithAliasPolicy(policy), WithAliasGenerator(NewBlockAliasGenerator(NewCounterIDSource(1))))
+
+		created, err := service.Create("", "http://www.bemobi.com.br")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "2", created.Alias)
+		repo.AssertNumberOfCalls(t, "Create", 1)
+	})
+
+	t.Run("Given an existing custom alias, when Create is called, then it should return an alias already exists error", func(t *testing.T) {
+		repo := &MockShortenedURLRepository{}
+		repo.On("ExistsByAlias", 0, "abc123").Return(true)
+		service := NewURLShortenerService(repo)
+
+		created, err := service.Create("abc123", "http://www.bemobi.com.br")
+
+		assert.ErrorIs(t, err, ErrAliasAlreadyExists)
+		assert.Nil(t, created)
+		repo.AssertNotCalled(t, "Create", mock.Anything)
+	})
+
+	t.Run("Given a custom alias taken concurrently, when the insert violates the unique constraint, then it should return an alias already exists error", func(t *testing.T) {
+		repo := &MockShortenedURLRepository{}
+		repo.On("ExistsByAlias", 0, "abc123").Return(false)
+		repo.On("Create", mock.AnythingOfType("*entity.ShortenedURL")).Return(gorm.ErrDuplicatedKey)
+		service := NewURLShortenerService(repo)
+
+		created, err := service.Create("abc123", "http://www.bemobi.com.br")
+
+		assert.ErrorIs(t, err, ErrAliasAlreadyExists)
+		assert.Nil(t, created)
+	})
+
+	t.Run("Given no alias, when the generated alias is already taken, then it should insert the next generated alias", func(t *testing.T) {
+		repo := &MockShortenedURLRepository{}
+		repo.On("Create", mock.MatchedBy(func(shortUrl *entity.ShortenedURL) bool { return shortUrl.Alias == "1" })).Return(gorm.ErrDuplicatedKey)
+		repo.On("Create", mock.MatchedBy(func(shortUrl *entity.ShortenedURL) bool { return shortUrl.Alias == "2" })).Return(nil)
+		service := NewURLShortenerService(repo, WithAliasGenerator(NewBlockAliasGenerator(NewCounterIDSource(1))))
+
+		created, err := service.Create("", "http://www.bemobi.com.br")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "2", created.Alias)
+		repo.AssertNotCalled(t, "ExistsByAlias", mock.Anything)
+	})
 
+	t.Run("Given no alias, when every generated alias is taken, then it should give up after the configured attempts", func(t *testing.T) {
+		repo := &MockShortenedURLRepository{}
+		repo.On("Create", mock.AnythingOfType("*entity.ShortenedURL")).Return(gorm.ErrDuplicatedKey)
+		service := NewURLShortenerService(repo, WithAliasGenerationAttempts(3))
+
+		created, err := service.Create("", "http://www.bemobi.com.br")
+
+		assert.ErrorIs(t, err, ErrAliasGenerationFailed)
+		assert.Nil(t, created)
+		repo.AssertNumberOfCalls(t, "Create", 3)
+	})
+}
+
+func TestShortenerServiceUnit_RetrieveByAlias(t *testing.T) {
+	t.Run("Given an expired shortened URL, when RetrieveByAlias is called, then it should return an expired error without counting the access", func(t *testing.T) {
+		expiredAt := time.Now().Add(-time.Minute)
+		repo := &MockShortenedURLRepository{}
+		repo.On("FindByAlias", 0, "abc123").Return(&entity.ShortenedURL{ID: 1, Alias: "abc123", ExpiresAt: &expiredAt}, nil)
+		service := NewURLShortenerService(repo)
+
+		shortUrl, err := service.RetrieveByAlias("abc123", nil)
+
+		assert.ErrorIs(t, err, ErrLinkExpired)
+		assert.Nil(t, shortUrl)
+		repo.AssertNotCalled(t, "IncrementAccessTimesByID", mock.Anything)
+	})
+
+	t.Run("Given a shortened URL that reached its access limit, when RetrieveByAlias is called, then it should return an access limit error", func(t *testing.T) {
+		maxAccessTimes := int32(1)
+		repo := &MockShortenedURLRepository{}
+		repo.On("FindByAlias", 0, "abc123").Return(&entity.ShortenedURL{ID: 1, Alias: "abc123", AccessTimes: 1, MaxAccessTimes: &maxAccessTimes}, nil)
+		repo.On("IncrementAccessTimesByID", 1).Return(false, nil)
+		service := NewURLShortenerService(repo)
+
+		shortUrl, err := service.RetrieveByAlias("abc123", nil)
+
+		assert.ErrorIs(t, err, ErrAccessLimitReached)
+		assert.Nil(t, shortUrl)
+	})
+
+	t.Run("Given a shortened URL within its access limit, when RetrieveByAlias is called, then it should count the access with a single lookup", func(t *testing.T) {
+		repo := &MockShortenedURLRepository{}
+		repo.On("FindByAlias", 0, "abc123").Return(&entity.ShortenedURL{ID: 1, Alias: "abc123", Url: "http://www.bemobi.com.br"}, nil).Once()
+		repo.On("IncrementAccessTimesByID", 1).Return(true, nil)
+		service := NewURLShortenerService(repo)
+
+		shortUrl, err := service.RetrieveByAlias("abc123", nil)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int32(1), shortUrl.AccessTimes)
+		repo.AssertNumberOfCalls(t, "FindByAlias", 1)
+	})
+
+	t.Run("Given a click event repository, when RetrieveByAlias is called with a click, then it should record the click for the shortened URL", func(t *testing.T) {
+		repo := &MockShortenedURLRepository{}
+		repo.On("FindByAlias", 0, "abc123").Return(&entity.ShortenedURL{ID: 7, Alias: "abc123", Url: "http://www.bemobi.com.br"}, nil)
+		repo.On("IncrementAccessTimesByID", 7).Return(true, nil)
+		clickRepo := &MockClickEventRepository{}
+		clickRepo.On("Create", mock.MatchedBy(func(event *entity.ClickEvent) bool {
+			return event.ShortenedURLID == 7 && event.Alias == "abc123" && !event.ClickedAt.IsZero() &&
+				event.Referrer == "http://www.google.com" && event.ClientIP == "192.168.0.10"
+		})).Return(nil)
+		service := NewURLShortenerService(repo, WithClickEventRepository(clickRepo))
+
+		_, err := service.RetrieveByAlias("abc123", entity.NewClickEvent("http://www.google.com", "Mozilla/5.0", "192.168.0.10", "pt-BR"))
+
+		assert.NoError(t, err)
+		clickRepo.AssertExpectations(t)
+	})
+
+	t.Run("Given client IP anonymization, when RetrieveByAlias is called with a click, then it should record the click with the masked IP", func(t *testing.T) {
+		repo := &MockShortenedURLRepository{}
+		repo.On("FindByAlias", 0, "abc123").Return(&entity.ShortenedURL{ID: 7, Alias: "abc123", Url: "http://www.bemobi.com.br"}, nil)
+		repo.On("IncrementAccessTimesByID", 7).Return(true, nil)
+		clickRepo := &MockClickEventRepository{}
+		clickRepo.On("Create", mock.MatchedBy(func(event *entity.ClickEvent) bool {
+			return event.ClientIP == "192.168.0.0"
+		})).Return(nil)
+		service := NewURLShortenerService(repo, WithClickEventRepository(clickRepo), WithClientIPAnonymization())
+
+		_, err := service.RetrieveByAlias("abc123", entity.NewClickEvent("", "Mozilla/5.0", "192.168.0.10", ""))
+
+		assert.NoError(t, err)
+		clickRepo.AssertExpectations(t)
+	})
+
+	t.Run("Given a failing click event repository, when RetrieveByAlias is called with a click, then it should still resolve the shortened URL", func(t *testing.T) {
+		repo := &MockShortenedURLRepository{}
+		repo.On("FindByAlias", 0, "abc123").Return(&entity.ShortenedURL{ID: 7, Alias: "abc123", Url: "http://www.bemobi.com.br"}, nil)
+		repo.On("IncrementAccessTimesByID", 7).Return(true, nil)
+		clickRepo := &MockClickEventRepository{}
+		clickRepo.On("Create", mock.Anything).Return(assert.AnError)
+		service := NewURLShortenerService(repo, WithClickEventRepository(clickRepo))
+
+		shortUrl, err := service.RetrieveByAlias("abc123", entity.NewClickEvent("", "", "192.168.0.10", ""))
+
+		assert.NoError(t, err)
+		assert.Equal(t, "http://www.bemobi.com.br", shortUrl.Url)
+	})
+}
+
+func TestShortenerServiceUnit_RetrieveByAliasWithAccessCounter(t *testing.T) {
+	t.Run("Given an access counter, when RetrieveByAlias is called for an unlimited shortened URL, then it should buffer the access instead of updating the repository", func(t *testing.T) {
+		repo := &MockShortenedURLRepository{}
+		repo.On("FindByAlias", 0, "abc123").Return(&entity.ShortenedURL{ID: 1, Alias: "abc123", AccessTimes: 4}, nil)
+		repo.On("AddAccessTimes", map[int]int32{1: 1}).Return(nil).Once()
+		counter := NewAccessCounter(repo, time.Hour, 1000)
+		service := NewURLShortenerService(repo, WithAccessCounter(counter))
+
+		shortUrl, err := service.RetrieveByAlias("abc123", nil)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int32(5), shortUrl.AccessTimes)
+		repo.AssertNotCalled(t, "IncrementAccessTimesByID", mock.Anything)
+
+		assert.NoError(t, counter.Close())
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("Given an access counter, when RetrieveByAlias is called for a limited shortened URL, then it should still check the limit synchronously", func(t *testing.T) {
+		maxAccessTimes := int32(1)
+		repo := &MockShortenedURLRepository{}
+		repo.On("FindByAlias", 0, "abc123").Return(&entity.ShortenedURL{ID: 1, Alias: "abc123", MaxAccessTimes: &maxAccessTimes}, nil)
+		repo.On("IncrementAccessTimesByID", 1).Return(false, nil)
+		counter := NewAccessCounter(repo, time.Hour, 1000)
+		service := NewURLShortenerService(repo, WithAccessCounter(counter))
+
+		_, err := service.RetrieveByAlias("abc123", nil)
+
+		assert.ErrorIs(t, err, ErrAccessLimitReached)
+		assert.NoError(t, counter.Close())
+		repo.AssertNotCalled(t, "AddAccessTimes", mock.Anything)
+	})
+}
+
+func TestShortenerServiceUnit_AnonymizeIP(t *testing.T) {
+	t.Run("Should mask the host part of IPv4 and IPv6 addresses and drop invalid values", func(t *testing.T) {
+		assert.Equal(t, "203.0.113.0", anonymizeIP("203.0.113.195"))
+		assert.Equal(t, "2001:db8:85a3::", anonymizeIP("2001:db8:85a3:8d3:1319:8a2e:370:7348"))
+		assert.Equal(t, "", anonymizeIP("not-an-ip"))
+	})
+}
+
+func TestShortenerServiceUnit_GetMostAcessedUrls(t *testing.T) {
+	t.Run("Given an invalid page or window, when GetMostAcessedUrls is called, then it should return an invalid ranking error", func(t *testing.T) {
+		repo := &MockShortenedURLRepository{}
+		service := NewURLShortenerService(repo)
+		since := time.Now()
+		until := since.Add(-time.Hour)
+
+		_, err := service.GetMostAcessedUrls(0, 0, nil, nil)
+		assert.ErrorIs(t, err, ErrInvalidRankingQuery)
+		_, err = service.GetMostAcessedUrls(MaxRankingLimit+1, 0, nil, nil)
+		assert.ErrorIs(t, err, ErrInvalidRankingQuery)
+		_, err = service.GetMostAcessedUrls(10, -1, nil, nil)
+		assert.ErrorIs(t, err, ErrInvalidRankingQuery)
+		_, err = service.GetMostAcessedUrls(10, 0, &since, &until)
+		assert.ErrorIs(t, err, ErrInvalidRankingQuery)
+
+		repo.AssertNotCalled(t, "FindMostAcessedUrls", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Given a valid page and window, when GetMostAcessedUrls is called, then it should query the repository ranking", func(t *testing.T) {
+		since := time.Now().Add(-7 * 24 * time.Hour)
+		ranking := []entity.ShortenedURL{{Alias: "abc123", AccessTimes: 5}}
+		repo := &MockShortenedURLRepository{}
+		repo.On("FindMostAcessedUrls", 0, 50, 0, &since, (*time.Time)(nil)).Return(ranking, nil)
 		service := NewURLShortenerService(repo)
 
-		resultedAlias := service.GenerateRandomAlias()
+		result, err := service.GetMostAcessedUrls(50, 0, &since, nil)
 
-		assert.Regexp(t, "^[a-zA-Z0-9]{11}$", resultedAlias, "Alias should be a 6-character alphanumeric string")
-		repo.AssertNumberOfCalls(t, "ExistsByAlias", 3)
+		assert.NoError(t, err)
+		assert.Equal(t, ranking, result)
 	})
 }
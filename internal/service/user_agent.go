@@ -0,0 +1,32 @@
+package service
+
+import "strings"
+
+// userAgentFamilies is checked in order, since most browsers also mention the engines they are based on
+// (e.g. Edge user agents contain "Chrome" and "Safari").
+var userAgentFamilies = []struct {
+	family  string
+	markers []string
+}{
+	{"Bot", []string{"bot", "crawler", "spider", "slurp", "facebookexternalhit", "whatsapp"}},
+	{"curl", []string{"curl/"}},
+	{"Edge", []string{"edg/", "edge/"}},
+	{"Opera", []string{"opr/", "opera"}},
+	{"Samsung Internet", []string{"samsungbrowser/"}},
+	{"Firefox", []string{"firefox/", "fxios/"}},
+	{"Chrome", []string{"chrome/", "crios/", "chromium/"}},
+	{"Safari", []string{"safari/"}},
+}
+
+// userAgentFamily classifies a raw User-Agent header into a browser (or client) family.
+func userAgentFamily(userAgent string) string {
+	lower := strings.ToLower(userAgent)
+	for _, candidate := range userAgentFamilies {
+		for _, marker := range candidate.markers {
+			if strings.Contains(lower, marker) {
+				return candidate.family
+			}
+		}
+	}
+	return unknownUserAgent
+}